@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/assessment"
+	"github.com/fzeitner/Nursebeecs-master-thesis/experiment"
+	"github.com/fzeitner/Nursebeecs-master-thesis/obs"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/observer"
+)
+
+// The following function runs 10 pairs of a control and a dimethoate treatment of beecs_ecotox with common random numbers.
+// Control and treatment of each pair share the random seed, so they are identical until the day of application
+// and the differences written to the CSV files in the "out" folder are caused by the PPP only.
+// Finally, the reductions of colony size, foragers and brood are evaluated against protection goals of 7%, 10% and 35%,
+// and written to out/assessment.csv and out/assessment.md.
+func main() {
+	app := app.New()
+
+	p := params.Default()
+	p.Termination.MaxTicks = 365
+
+	control := params.DefaultEtox()
+
+	treatment := params.DefaultEtox()
+	treatment.PPPApplication.Application = true
+	treatment.PPPApplication.PPPname = "dimethoate"
+	treatment.PPPApplication.PPPconcentrationNectar = 1320  // concentration in the nectar of the simulated patches [mug/kg]
+	treatment.PPPApplication.PPPconcentrationPollen = 36200 // concentration in the pollen of the simulated patches [mug/kg]
+	treatment.PPPApplication.AppDay = 217
+	treatment.PPPApplication.ExposurePeriod = 9
+	treatment.PPPToxicity.ForagerOralLD50 = 0.127
+	treatment.PPPToxicity.ForagerOralSlope = 4.37
+	treatment.PPPToxicity.LarvaeOralLD50 = 0.24
+	treatment.PPPToxicity.LarvaeOralSlope = 1.186
+
+	results := []experiment.PairedResult{}
+	for i := range 10 {
+		p.RandomSeed.Seed = i + 1
+
+		paired := experiment.Paired{
+			Params:     p,
+			Control:    control,
+			Treatments: []params.DefaultParamsEtox{treatment},
+			Observer:   func() observer.Row { return &obs.RiskEndpoints{} },
+		}
+		result, err := paired.Run(app)
+		if err != nil {
+			panic(err)
+		}
+		if err := result.WriteCSV(fmt.Sprintf("out/paired-%04d.csv", i), ";"); err != nil {
+			panic(err)
+		}
+		results = append(results, result)
+	}
+
+	controlRuns, treatedRuns, err := assessment.FromPaired(results, 0)
+	if err != nil {
+		panic(err)
+	}
+	a := assessment.Assessment{
+		Windows: []assessment.Window{
+			{Name: "After application", Start: 224, End: 224},
+			{Name: "End of season", Start: 280, End: 300},
+			{Name: "End of year", Start: 364, End: 364},
+		},
+		Goals: []assessment.ProtectionGoal{
+			{Endpoint: "ColonySize", Threshold: 0.07},
+			{Endpoint: "ColonySize", Threshold: 0.1},
+			{Endpoint: "ColonySize", Threshold: 0.35},
+		},
+		Paired: true,
+	}
+	report, err := a.Evaluate(&controlRuns, &treatedRuns)
+	if err != nil {
+		panic(err)
+	}
+	if err := assessment.WriteReport(report, "out/assessment"); err != nil {
+		panic(err)
+	}
+}
@@ -67,8 +67,7 @@ func main() {
 		Nursebeecsv1:           true,
 		ForesightedCannibalism: false,
 
-		HGEffects:    false,
-		HGFoodIntake: false,
+		HGEffects: false,
 	}
 	// the NursingRework parameter subset can be adjusted slightly; specifically the boolean switches beginning with NewBroodCare above can be switched to true to enable
 	// specific simulation. At default everything but NewBroodCare and Nursebeecsv1 is turned off; NewBroodCare enables any new brood care mechanisms that are not the BEEHAVE/beecs default brood care
@@ -121,8 +120,7 @@ func main() {
 	// the last two switches activate the simulated sublethal effects on brood care that were one of the main topics of my master thesis. To consider simulations with these the
 	// nursebeecs_ecotox model needs to be run. Therefore, lets define the ecotox parameters and run the model:
 	pe := params.DefaultEtox()
-	pn.NursingRework.HGEffects = true     // this activates HGEffects by lowering the ceiling of ProteinFactorNurses and forcing a fraction of brood to be killed every timestep when the PPP threshold gets exceeded
-	pn.NursingRework.HGFoodIntake = false // activating this as well would deactivate the ceiling effect and activate the reduction in jelly provision by reducing the pollen intake of nurse bees
+	pn.NursingRework.HGEffects = true // this activates the dynamic HPG state model; PPP doses of IHbees shrink their glands and thereby the jelly production capacity of nurses
 	filename = "sublethal1"
 	for i := 0; i < 100; i++ {
 		runNursebeecsEtox(app, i, filename, &p, &pe, &pn)
@@ -130,12 +128,12 @@ func main() {
 	dur = time.Since(start)
 	fmt.Println(dur)
 
-	// note that the effect thresholds for PPP as well as the effects on the respective parameters are defined in the PPPToxicity parameter set of the etox parameters:
-	pe.PPPToxicity.HGthreshold = []float64{0.000727 / 12.78, 0.000727 / 12.78 * 10, 0.000727 / 12.78 * 100}
-	pe.PPPToxicity.ProteinFactorNurseExposed = []float64{0.65, 0.65, 0.65}
-	pe.PPPToxicity.MaxPollenRed = []float64{0.3, 0.3, 0.3}
-	// these slices leave place for three effect thresholds in the HGthreshold variable that connects to three effect levels in the PFNexposed or MaxPollenRed slices
-	// all of this is experimental and based on Schott et al. (2021). It should be reevaluated and implemented properly; this was only a first try at implementing sublethal effects at all.
+	// note that the effect of PPP on gland size is defined in the PPPToxicity parameter set of the etox parameters,
+	// while gland development with age and nutrition is defined in the HPGDevelopment parameter set of the nursebeecs parameters:
+	pe.PPPToxicity.HPGInhibitionED50 = 0.00002
+	pe.PPPToxicity.HPGMaxInhibition = 0.35
+	pn.HPGDevelopment.GrowthRate = 0.5
+	// all of this is experimental and loosely based on Schott et al. (2021).
 }
 
 // lastly, define the functions that run the model versions here. This can be copy-pasted easily to run various model versions
@@ -77,8 +77,7 @@ func main() {
 		Nursebeecsv1:           true,
 		ForesightedCannibalism: false,
 
-		HGEffects:    false,
-		HGFoodIntake: false,
+		HGEffects: false,
 	}
 
 	// the NursingRework parameter subset can be adjusted slightly; specifically the boolean switches beginning with NewBroodCare above can be switched to true to enable
@@ -0,0 +1,192 @@
+package assessment
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// Window is a time window of the assessment. Assessment dates are windows of a single tick.
+type Window struct {
+	Name  string // Name of the window, for the report.
+	Start int    // First tick of the window.
+	End   int    // Last tick of the window.
+}
+
+// ProtectionGoal is a specific protection goal, given as the maximum tolerable reduction of an endpoint.
+type ProtectionGoal struct {
+	Endpoint  string  // Name of the endpoint.
+	Threshold float64 // Maximum tolerable reduction relative to the control, e.g. 0.07, 0.1 or 0.35 [ ].
+}
+
+// Outcome of the evaluation of a protection goal.
+type Outcome uint8
+
+const (
+	// Met if the upper confidence limit of the reduction does not exceed the threshold.
+	Met Outcome = iota
+	// Exceeded if the lower confidence limit of the reduction exceeds the threshold.
+	Exceeded
+	// Inconclusive if the confidence interval includes the threshold.
+	Inconclusive
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Met:
+		return "met"
+	case Exceeded:
+		return "exceeded"
+	default:
+		return "inconclusive"
+	}
+}
+
+// Assessment configures the evaluation of treatment ensembles against protection goals.
+type Assessment struct {
+	Endpoints  []string         // Endpoints to evaluate; all endpoints of the ensembles if empty.
+	Windows    []Window         // Time windows to evaluate.
+	Goals      []ProtectionGoal // Protection goals to evaluate.
+	Paired     bool             // Whether control and treatment runs are pairs with common random numbers, e.g. from [FromPaired].
+	Confidence float64          // Confidence level of the intervals; 0.95 if zero.
+	Bootstrap  int              // Number of bootstrap samples; 1000 if zero.
+	Seed       uint64           // Seed for the bootstrap.
+}
+
+// Result is the reduction of one endpoint in one window, with the evaluated protection goals.
+type Result struct {
+	Window    Window        // The time window.
+	Endpoint  string        // Name of the endpoint.
+	Control   float64       // Mean of the control over runs and ticks of the window.
+	Treatment float64       // Mean of the treatment over runs and ticks of the window.
+	Reduction float64       // Reduction relative to the control; negative for an increase [ ].
+	Lower     float64       // Lower confidence limit of the reduction [ ].
+	Upper     float64       // Upper confidence limit of the reduction [ ].
+	Goals     []GoalOutcome // Outcomes of the protection goals for this endpoint.
+}
+
+// GoalOutcome is the outcome of a protection goal.
+type GoalOutcome struct {
+	Threshold float64 // Maximum tolerable reduction [ ].
+	Outcome   Outcome // The outcome.
+}
+
+// Evaluate calculates the reductions of all endpoints in all windows, and evaluates them against the protection goals.
+//
+// The reduction is 1 - mean(treatment) / mean(control), with the means taken over runs and the ticks of the window.
+// Confidence intervals are percentile bootstrap intervals, resampling pairs of runs if Paired is set, and runs of both ensembles independently otherwise.
+// The reduction and its limits are NaN if the control mean is zero.
+func (a *Assessment) Evaluate(control *Ensemble, treatment *Ensemble) ([]Result, error) {
+	if len(control.Runs) == 0 || len(treatment.Runs) == 0 {
+		return nil, fmt.Errorf("control and treatment ensembles must contain runs")
+	}
+	if a.Paired && len(control.Runs) != len(treatment.Runs) {
+		return nil, fmt.Errorf("paired ensembles must have the same number of runs, got %d and %d", len(control.Runs), len(treatment.Runs))
+	}
+	for _, w := range a.Windows {
+		if w.Start < 0 || w.End < w.Start {
+			return nil, fmt.Errorf("invalid window '%s' from %d to %d", w.Name, w.Start, w.End)
+		}
+	}
+	endpoints := a.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = control.Endpoints
+	}
+
+	rng := rand.New(rand.NewPCG(a.Seed, 0))
+	results := []Result{}
+	for _, w := range a.Windows {
+		for _, ep := range endpoints {
+			ci, err := control.endpointIndex(ep)
+			if err != nil {
+				return nil, err
+			}
+			ti, err := treatment.endpointIndex(ep)
+			if err != nil {
+				return nil, err
+			}
+			c := control.windowMeans(ci, w)
+			t := treatment.windowMeans(ti, w)
+
+			res := Result{
+				Window:    w,
+				Endpoint:  ep,
+				Control:   stat.Mean(c, nil),
+				Treatment: stat.Mean(t, nil),
+			}
+			res.Reduction = reduction(res.Control, res.Treatment)
+			res.Lower, res.Upper = a.confidenceInterval(c, t, rng)
+
+			for _, g := range a.Goals {
+				if g.Endpoint == ep {
+					res.Goals = append(res.Goals, GoalOutcome{Threshold: g.Threshold, Outcome: evaluateGoal(res.Lower, res.Upper, g.Threshold)})
+				}
+			}
+			results = append(results, res)
+		}
+	}
+	return results, nil
+}
+
+// confidenceInterval returns the percentile bootstrap confidence interval of the reduction.
+func (a *Assessment) confidenceInterval(control []float64, treatment []float64, rng *rand.Rand) (float64, float64) {
+	samples := a.Bootstrap
+	if samples <= 0 {
+		samples = 1000
+	}
+	confidence := a.Confidence
+	if confidence <= 0 {
+		confidence = 0.95
+	}
+
+	reductions := make([]float64, 0, samples)
+	for range samples {
+		c, t := 0.0, 0.0
+		for range control {
+			i := rng.IntN(len(control))
+			c += control[i]
+			if a.Paired {
+				t += treatment[i]
+			}
+		}
+		if !a.Paired {
+			for range treatment {
+				t += treatment[rng.IntN(len(treatment))]
+			}
+		}
+		r := reduction(c/float64(len(control)), t/float64(len(treatment)))
+		if math.IsNaN(r) {
+			return math.NaN(), math.NaN()
+		}
+		reductions = append(reductions, r)
+	}
+	slices.Sort(reductions)
+
+	alpha := (1 - confidence) / 2
+	return stat.Quantile(alpha, stat.Empirical, reductions, nil), stat.Quantile(1-alpha, stat.Empirical, reductions, nil)
+}
+
+// reduction returns the reduction of the treatment relative to the control.
+func reduction(control float64, treatment float64) float64 {
+	if control == 0 {
+		return math.NaN()
+	}
+	return 1 - treatment/control
+}
+
+// evaluateGoal compares the confidence interval of a reduction to the threshold of a protection goal.
+func evaluateGoal(lower float64, upper float64, threshold float64) Outcome {
+	if math.IsNaN(lower) || math.IsNaN(upper) {
+		return Inconclusive
+	}
+	if upper <= threshold {
+		return Met
+	}
+	if lower > threshold {
+		return Exceeded
+	}
+	return Inconclusive
+}
@@ -0,0 +1,111 @@
+package assessment_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/assessment"
+	"github.com/fzeitner/Nursebeecs-master-thesis/experiment"
+	"github.com/stretchr/testify/assert"
+)
+
+func run(ticks int, colony float64, brood float64) [][]float64 {
+	rows := make([][]float64, ticks)
+	for i := range rows {
+		rows[i] = []float64{colony, brood}
+	}
+	return rows
+}
+
+func TestAssessment(t *testing.T) {
+	control := assessment.NewEnsemble("ColonySize", "Brood")
+	treatment := assessment.NewEnsemble("ColonySize", "Brood")
+	for i := range 10 {
+		noise := float64(i%3) - 1
+		assert.Nil(t, control.Add(run(20, 10000+100*noise, 1000)))
+		assert.Nil(t, treatment.Add(run(20, 8000+100*noise, 1000)))
+	}
+	assert.NotNil(t, control.Add([][]float64{{1}}))
+
+	a := assessment.Assessment{
+		Windows: []assessment.Window{{Name: "Day 10", Start: 10, End: 10}, {Name: "Late", Start: 15, End: 19}},
+		Goals: []assessment.ProtectionGoal{
+			{Endpoint: "ColonySize", Threshold: 0.07},
+			{Endpoint: "ColonySize", Threshold: 0.2},
+			{Endpoint: "ColonySize", Threshold: 0.35},
+		},
+		Paired: true,
+		Seed:   1,
+	}
+	results, err := a.Evaluate(&control, &treatment)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(results))
+
+	colony := results[0]
+	assert.Equal(t, "ColonySize", colony.Endpoint)
+	assert.InDelta(t, 0.2, colony.Reduction, 0.005)
+	assert.Less(t, colony.Lower, colony.Reduction)
+	assert.Greater(t, colony.Upper, colony.Reduction)
+	assert.Equal(t, []assessment.GoalOutcome{
+		{Threshold: 0.07, Outcome: assessment.Exceeded},
+		{Threshold: 0.2, Outcome: assessment.Inconclusive},
+		{Threshold: 0.35, Outcome: assessment.Met},
+	}, colony.Goals)
+
+	brood := results[1]
+	assert.Equal(t, 0.0, brood.Reduction)
+	assert.Equal(t, 0, len(brood.Goals))
+
+	a.Paired = false
+	unpaired, err := a.Evaluate(&control, &treatment)
+	assert.Nil(t, err)
+	assert.InDelta(t, colony.Reduction, unpaired[0].Reduction, 1e-12)
+	assert.Greater(t, unpaired[0].Upper-unpaired[0].Lower, colony.Upper-colony.Lower) // pairing removes the common noise
+
+	// runs terminated by extinction count as zero
+	treatment.Runs[0] = treatment.Runs[0][:5]
+	results, err = a.Evaluate(&control, &treatment)
+	assert.Nil(t, err)
+	assert.Greater(t, results[2].Reduction, colony.Reduction)
+
+	file := filepath.Join(t.TempDir(), "report")
+	assert.Nil(t, assessment.WriteReport(results, file))
+	_, err = os.Stat(file + ".md")
+	assert.Nil(t, err)
+
+	a.Endpoints = []string{"Foo"}
+	_, err = a.Evaluate(&control, &treatment)
+	assert.NotNil(t, err)
+}
+
+func TestAssessmentZeroControl(t *testing.T) {
+	control := assessment.NewEnsemble("Brood")
+	treatment := assessment.NewEnsemble("Brood")
+	assert.Nil(t, control.Add([][]float64{{0}}))
+	assert.Nil(t, treatment.Add([][]float64{{0}}))
+
+	a := assessment.Assessment{
+		Windows: []assessment.Window{{Start: 0, End: 0}},
+		Goals:   []assessment.ProtectionGoal{{Endpoint: "Brood", Threshold: 0.1}},
+	}
+	results, err := a.Evaluate(&control, &treatment)
+	assert.Nil(t, err)
+	assert.True(t, math.IsNaN(results[0].Reduction))
+	assert.Equal(t, assessment.Inconclusive, results[0].Goals[0].Outcome)
+}
+
+func TestFromPaired(t *testing.T) {
+	results := []experiment.PairedResult{
+		{Columns: []string{"ColonySize"}, Control: [][]float64{{100}}, Treatments: [][][]float64{{{90}}}},
+		{Columns: []string{"ColonySize"}, Control: [][]float64{{110}}, Treatments: [][][]float64{{{95}}}},
+	}
+	control, treatment, err := assessment.FromPaired(results, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(control.Runs))
+	assert.Equal(t, 95.0, treatment.Runs[1][0][0])
+
+	_, _, err = assessment.FromPaired(results, 1)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,6 @@
+// Package assessment evaluates ensembles of control and treatment runs against the specific protection goals
+// of regulatory risk assessments for honey bees, like those of the EFSA bee guidance.
+//
+// Reductions of endpoints like colony size, foragers or brood relative to the control are calculated per time window,
+// with bootstrap confidence intervals, and compared to thresholds such as 7%, 10% or 35%.
+package assessment
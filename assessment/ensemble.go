@@ -0,0 +1,75 @@
+package assessment
+
+import (
+	"fmt"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/experiment"
+)
+
+// Ensemble contains the daily values of the assessment endpoints for multiple runs of one scenario.
+type Ensemble struct {
+	Endpoints []string      // Names of the endpoints, e.g. the columns of [github.com/fzeitner/Nursebeecs-master-thesis/obs.RiskEndpoints].
+	Runs      [][][]float64 // Values per run, tick and endpoint.
+}
+
+// NewEnsemble creates an empty ensemble for the given endpoints.
+func NewEnsemble(endpoints ...string) Ensemble {
+	return Ensemble{Endpoints: endpoints}
+}
+
+// Add adds a run, given as rows of endpoint values per tick.
+func (e *Ensemble) Add(rows [][]float64) error {
+	for i, row := range rows {
+		if len(row) != len(e.Endpoints) {
+			return fmt.Errorf("row %d has %d values, but the ensemble has %d endpoints", i, len(row), len(e.Endpoints))
+		}
+	}
+	e.Runs = append(e.Runs, rows)
+	return nil
+}
+
+// FromPaired creates the control and treatment ensembles of the treatment with the given index from paired runs.
+// The runs of both ensembles are in the same order, so they can be evaluated as pairs (see [Assessment] Paired).
+func FromPaired(results []experiment.PairedResult, treatment int) (control Ensemble, treated Ensemble, err error) {
+	if len(results) == 0 {
+		return control, treated, fmt.Errorf("no paired results given")
+	}
+	control = NewEnsemble(results[0].Columns...)
+	treated = NewEnsemble(results[0].Columns...)
+	for i, r := range results {
+		if treatment >= len(r.Treatments) {
+			return control, treated, fmt.Errorf("paired result %d has no treatment %d", i, treatment)
+		}
+		if err = control.Add(r.Control); err != nil {
+			return control, treated, err
+		}
+		if err = treated.Add(r.Treatments[treatment]); err != nil {
+			return control, treated, err
+		}
+	}
+	return control, treated, nil
+}
+
+// endpointIndex returns the index of the endpoint with the given name.
+func (e *Ensemble) endpointIndex(name string) (int, error) {
+	for i, n := range e.Endpoints {
+		if n == name {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("endpoint '%s' not found in ensemble", name)
+}
+
+// windowMeans returns the mean value of an endpoint over the window for each run.
+// Ticks missing in runs that terminated early, e.g. by extinction, count as zero.
+func (e *Ensemble) windowMeans(endpoint int, w Window) []float64 {
+	means := make([]float64, len(e.Runs))
+	for r, run := range e.Runs {
+		sum := 0.0
+		for tick := w.Start; tick <= w.End && tick < len(run); tick++ {
+			sum += run[tick][endpoint]
+		}
+		means[r] = sum / float64(w.End-w.Start+1)
+	}
+	return means
+}
@@ -0,0 +1,47 @@
+package assessment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteReport writes the results as a CSV and a Markdown table, to file.csv and file.md.
+// There is one row per window, endpoint and protection goal, or one row per window and endpoint without goals.
+func WriteReport(results []Result, file string) error {
+	csv := strings.Builder{}
+	md := strings.Builder{}
+	csv.WriteString("Window;Start;End;Endpoint;Control;Treatment;Reduction;Lower;Upper;Threshold;Outcome\n")
+	md.WriteString("| Window | Ticks | Endpoint | Control | Treatment | Reduction [%] | CI [%] | Goal [%] | Outcome |\n")
+	md.WriteString("|--------|-------|----------|--------:|----------:|--------------:|-------:|---------:|---------|\n")
+
+	for _, r := range results {
+		goals := r.Goals
+		if len(goals) == 0 {
+			goals = []GoalOutcome{{Threshold: -1}}
+		}
+		for _, g := range goals {
+			threshold, outcome, goal := "", "", "-"
+			if g.Threshold >= 0 {
+				threshold = fmt.Sprintf("%g", g.Threshold)
+				outcome = g.Outcome.String()
+				goal = fmt.Sprintf("%.0f", g.Threshold*100)
+			}
+			fmt.Fprintf(&csv, "%s;%d;%d;%s;%g;%g;%g;%g;%g;%s;%s\n",
+				r.Window.Name, r.Window.Start, r.Window.End, r.Endpoint, r.Control, r.Treatment, r.Reduction, r.Lower, r.Upper, threshold, outcome)
+			fmt.Fprintf(&md, "| %s | %d-%d | %s | %.1f | %.1f | %.1f | %.1f to %.1f | %s | %s |\n",
+				r.Window.Name, r.Window.Start, r.Window.End, r.Endpoint, r.Control, r.Treatment, r.Reduction*100, r.Lower*100, r.Upper*100, goal, outcome)
+		}
+	}
+
+	if dir := filepath.Dir(file); dir != "" {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(file+".csv", []byte(csv.String()), 0666); err != nil {
+		return err
+	}
+	return os.WriteFile(file+".md", []byte(md.String()), 0666)
+}
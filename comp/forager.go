@@ -2,6 +2,7 @@ package comp
 
 import (
 	"github.com/fzeitner/Nursebeecs-master-thesis/enum/activity"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/virus"
 	"github.com/mlange-42/ark/ecs"
 )
 
@@ -33,3 +34,8 @@ type NectarLoad struct {
 	Energy float64 // Current nectar energy load per individual [kJ]
 	Pollen float64 // Current amount of pollen per individual [g]
 }
+
+// Virus component for forager squadrons. Only used by the Varroa mite module.
+type Virus struct {
+	State virus.State // Infection state of the squadron.
+}
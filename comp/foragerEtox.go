@@ -17,8 +17,24 @@ type PPPExpo struct {
 	OralDose    float64 // Current daily oral dose of this squadron to PPP used in dose-respnse of BEEHAVE_ecotox [µg]
 	ContactDose float64 // Current daily contact dose of this squadron to PPP used in dose-respnse of BEEHAVE_ecotox [µg]
 
+	TreatmentDose float64 // Current daily contact dose from the residues of in-hive treatments; kept apart from ContactDose, which may be averaged over foraging trips [µg]
+
 	RdmSurvivalContact float64 // Survival chance or "resilience" of the squadron to PPP contact exposure
 	RdmSurvivalOral    float64 // Survival chance or "resilience" of the squadron to PPP oral exposure
+
+	CumOralDose     float64 // Lifetime cumulative oral dose of this squadron, summed up over the daily doses [µg]
+	CumContactDose  float64 // Lifetime cumulative contact dose of this squadron, summed up over the daily doses [µg]
+	DaysExposed     int     // Number of days with a non-zero oral or contact dose
+	PeakOralDose    float64 // Highest daily oral dose of this squadron [µg]
+	PeakContactDose float64 // Highest daily contact dose of this squadron [µg]
+
+	RecentDose float64 // Oral and contact doses of past days, decaying with PPPToxicity.SublethalHalfLife; drives sublethal effects [µg]
+	LarvalDose float64 // Cumulative oral dose per bee received as a larva, carried over from the in-hive cohorts; drives the longevity effect [µg]
+}
+
+// Contact returns the total daily contact dose of the squadron, from foraging and from in-hive treatments [µg].
+func (e *PPPExpo) Contact() float64 {
+	return e.ContactDose + e.TreatmentDose
 }
 
 // analogous to KnownPatch, but used in beecs_ecotox.
@@ -38,6 +54,4 @@ type ActivityEtox struct {
 
 	Winterbee bool // switches to have foragers counted as winterbees --> they can forage and nurse at the same time. This will need to be adressed in the future. for now necessary for nursing
 	Reverted  bool // switches to have foragers counted as reverted foragers that can nurse again but do not forage. for now necessary for nursing
-
-	WaterForager bool // Whether it is currently foraging for water.
 }
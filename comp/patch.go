@@ -5,16 +5,20 @@ import "github.com/fzeitner/Nursebeecs-master-thesis/enum/interp"
 // PatchConfig for initialization of flower patches.
 // Not used as a component directly!
 //
-// Exactly one of ConstantPatch, SeasonalPatch and ScriptedPatch must be non-nil.
+// Exactly one of ConstantPatch, SeasonalPatch, ScriptedPatch and WaterPatch must be non-nil.
 type PatchConfig struct {
 	// Distance to the colony [m].
 	DistToColony float64
+	// Distance to the treated field for spray drift [m]. 0 for patches within the treated field. Only used by _ecotox.
+	DistToField float64
 	// Configuration for patches with constant resources.
 	ConstantPatch *ConstantPatch `json:",omitempty"`
 	// Configuration for patches with simple seasonal resource dynamics.
 	SeasonalPatch *SeasonalPatch `json:",omitempty"`
 	// Configuration for patches with scripted/arbitrary resource dynamics.
 	ScriptedPatch *ScriptedPatch `json:",omitempty"`
+	// Configuration for water sources like puddles, guttation drops or surface water. Only used by _ecotox water foraging.
+	WaterPatch *WaterPatch `json:",omitempty"`
 	// Optional coordinates for visualization. Calculated otherwise.
 	Coords *Coords `json:",omitempty"`
 }
@@ -47,6 +51,33 @@ type ScriptedPatch struct {
 	Interpolation        interp.Interpolation
 }
 
+// WaterPatch configuration and component for water sources.
+//
+// Water patches are not flower patches and are only visited by water foragers.
+type WaterPatch struct {
+	DetectionProbability float64      // Detection probability of the water source.
+	PPPconcentration     float64      // PPP concentration in the water [µg/L].
+	PPPseries            [][2]float64 // Optional PPP concentrations by day of year [µg/L], interpolated step-wise; replaces PPPconcentration if not empty.
+}
+
+// Concentration returns the PPP concentration in the water on the given day of the year [µg/L].
+func (p *WaterPatch) Concentration(dayOfYear int) float64 {
+	if len(p.PPPseries) == 0 {
+		return p.PPPconcentration
+	}
+	if float64(dayOfYear) < p.PPPseries[0][0] {
+		return 0
+	}
+	conc := 0.0
+	for _, row := range p.PPPseries {
+		if row[0] > float64(dayOfYear) {
+			break
+		}
+		conc = row[1]
+	}
+	return conc
+}
+
 // PatchProperties component for flower patches.
 type PatchProperties struct {
 	MaxNectar            float64 // Maximum of available nectar [L].
@@ -58,6 +89,7 @@ type PatchProperties struct {
 // PatchDistance component for flower patches.
 type PatchDistance struct {
 	DistToColony float64 // Distance to the colony [m].
+	DistToField  float64 // Distance to the treated field [m]; 0 within the treated field.
 }
 
 // Resource component for flower patches.
@@ -0,0 +1,13 @@
+// Package capping provides an enumeration of honey capping rules.
+package capping
+
+// Rule type alias for use as enumeration.
+type Rule uint8
+
+const (
+	// Uncapped honey is capped as a whole once it is older than the number of uncapped days, as in BEEHAVE_ecotox.
+	AfterDays Rule = iota
+	// A fixed fraction of each uncapped compartment is capped every day,
+	// and any remainder is capped once it is older than the number of uncapped days.
+	DailyFraction
+)
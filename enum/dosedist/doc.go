@@ -0,0 +1,12 @@
+// Package dosedist provides an enumeration of within-cohort dose distributions.
+package dosedist
+
+// Distribution type alias for use as enumeration.
+type Distribution uint8
+
+const (
+	// All individuals of a cohort receive the mean cohort dose, as in BEEHAVE_ecotox.
+	Mean Distribution = iota
+	// Individual doses follow a log-normal distribution around the mean cohort dose.
+	LogNormal
+)
@@ -0,0 +1,14 @@
+// Package doseresponse provides an enumeration of dose-response model families.
+package doseresponse
+
+// Model type alias for use as enumeration.
+type Model uint8
+
+const (
+	// Log-logistic dose-response, as used in BEEHAVE_ecotox.
+	LogLogistic Model = iota
+	// Probit (log-normal) dose-response.
+	Probit
+	// Weibull dose-response.
+	Weibull
+)
@@ -0,0 +1,14 @@
+// Package drift provides an enumeration of spray drift curves.
+package drift
+
+// Curve type alias for use as enumeration.
+type Curve uint8
+
+const (
+	// No drift gradient; all patches receive the full in-field exposure, as in BEEHAVE_ecotox.
+	None Curve = iota
+	// Basic drift values for field crops of the German BBA (Rautmann et al. 2001), 90th percentile of a single application.
+	BBA
+	// User-provided table of drift deposition by distance, interpolated linearly.
+	Table
+)
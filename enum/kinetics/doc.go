@@ -0,0 +1,14 @@
+// Package kinetics provides an enumeration of residue degradation kinetics.
+package kinetics
+
+// Model type alias for use as enumeration.
+type Model uint8
+
+const (
+	// Single first-order kinetics.
+	SFO Model = iota
+	// First-order multi-compartment (Gustafson-Holden) kinetics.
+	FOMC
+	// Double first-order in parallel kinetics.
+	DFOP
+)
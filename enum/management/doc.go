@@ -0,0 +1,18 @@
+// Package management provides an enumeration of beekeeper management actions.
+package management
+
+// Action type alias for use as enumeration.
+type Action uint8
+
+const (
+	// Honey harvest; removes all honey above the given amount left in the hive [kg].
+	HoneyHarvest Action = iota
+	// Feeding of sugar syrup; adds the energy of the given amount of sucrose to the honey stores [kg].
+	SugarFeeding
+	// Feeding of pollen substitute; adds the given amount to the pollen stores [g].
+	PollenSubstitute
+	// Exchange of brood frames; adds the given number of worker brood cells, or removes them if negative [cells].
+	BroodExchange
+	// Colony split; removes the given fraction of the adult bees, the brood and the stores to form a new colony [ ].
+	Split
+)
@@ -0,0 +1,18 @@
+// Package queen provides an enumeration of the states of a colony concerning its queen.
+package queen
+
+// State type alias for use as enumeration.
+type State uint8
+
+const (
+	// Laying colonies have a mated queen that lays eggs.
+	Laying State = iota
+	// Queenless colonies have just lost their queen, and start rearing a new one if they have young worker brood.
+	Queenless
+	// Rearing colonies rear an emergency queen from a young worker larva.
+	Rearing
+	// Mating colonies have a virgin queen on her mating flights.
+	Mating
+	// Hopeless colonies have no queen and no young worker brood to rear a new one from.
+	Hopeless
+)
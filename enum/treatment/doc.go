@@ -0,0 +1,14 @@
+// Package treatment provides an enumeration of application methods of in-hive treatments.
+package treatment
+
+// Method type alias for use as enumeration.
+type Method uint8
+
+const (
+	// Strips placed between the combs, releasing the active ingredient continuously until they are removed.
+	Strip Method = iota
+	// Solution trickled onto the bees in the seams; the residue persists in the hive and degrades.
+	Trickling
+	// Vapour or smoke; the residue not taken up on the day of the pulse is vented from the hive.
+	Fumigation
+)
@@ -0,0 +1,14 @@
+// Package virus provides an enumeration of virus infection states of bees, used by the Varroa mite module.
+package virus
+
+// State type alias for use as enumeration.
+type State uint8
+
+const (
+	// Healthy bees carry no virus.
+	Healthy State = iota
+	// InfectedAsPupa bees were infected by a mite in the capped brood cell.
+	InfectedAsPupa
+	// InfectedAsAdult bees were infected by a phoretic mite after emergence.
+	InfectedAsAdult
+)
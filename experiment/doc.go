@@ -1,2 +1,3 @@
-// Package experiment provides utilities for systematic and randomized parameter variation.
+// Package experiment provides utilities for systematic and randomized parameter variation,
+// and for paired control and treatment runs with common random numbers.
 package experiment
@@ -0,0 +1,191 @@
+package experiment
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/model"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/mlange-42/ark-tools/reporter"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Paired runs a control and one or more treatments of the etox models with common random numbers.
+//
+// All runs use the same random seed. As each sub-model draws from its own random number stream
+// (see [github.com/fzeitner/Nursebeecs-master-thesis/globals.RandStreams]), the runs are identical
+// until the first exposure, and differences afterwards are caused by the treatment rather than by stochastic noise.
+type Paired struct {
+	Params     params.DefaultParams            // Parameters shared by all runs. A random seed <= 0 is drawn once for all runs.
+	Nursebeecs *params.DefaultParamsNursebeecs // Nursebeecs parameters; runs DefaultNbeecsEtox if non-nil, DefaultEtox otherwise.
+	Control    params.DefaultParamsEtox        // Etox parameters of the control.
+	Treatments []params.DefaultParamsEtox      // Etox parameters of the treatments.
+	Observer   func() observer.Row             // Creates the observer to compare; called once per run, and once before the runs to check the columns.
+	Columns    []string                        // Observer columns to compare; all columns if empty. Checked against the observer header before the runs.
+}
+
+// PairedResult contains the compared observer columns of a paired run, per tick.
+type PairedResult struct {
+	Seed       int           // Random seed used for all runs.
+	Columns    []string      // Names of the compared columns.
+	Control    [][]float64   // Values of the control per tick and column.
+	Treatments [][][]float64 // Values of each treatment per tick and column.
+}
+
+// Run performs the control and all treatment runs.
+// Returns an error without running the model if any of the requested columns is not in the observer header.
+// Runs with accounting events (see [github.com/fzeitner/Nursebeecs-master-thesis/globals.AccountingEvents]) are completed,
+// and the first event of each such run is returned as a joined error together with the results.
+//
+// If the argument a is non-nil, the model is reset and re-used for all runs.
+func (p *Paired) Run(a *app.App) (PairedResult, error) {
+	pars := p.Params
+	if pars.RandomSeed.Seed <= 0 {
+		pars.RandomSeed.Seed = int(rand.Int32())
+	}
+	result := PairedResult{Seed: pars.RandomSeed.Seed}
+
+	header := p.Observer().Header()
+	indices, err := columnIndices(header, p.Columns)
+	if err != nil {
+		return result, err
+	}
+	result.Columns = make([]string, len(indices))
+	for i, idx := range indices {
+		result.Columns[i] = header[idx]
+	}
+
+	errs := []error{}
+	var data [][]float64
+	data, err = p.run(a, &pars, &p.Control, indices)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("control: %w", err))
+	}
+	result.Control = data
+	for i := range p.Treatments {
+		data, err = p.run(a, &pars, &p.Treatments[i], indices)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("treatment %d: %w", i, err))
+		}
+		result.Treatments = append(result.Treatments, data)
+	}
+	return result, errors.Join(errs...)
+}
+
+func (p *Paired) run(a *app.App, pars *params.DefaultParams, etox *params.DefaultParamsEtox, indices []int) ([][]float64, error) {
+	if p.Nursebeecs != nil {
+		a = model.DefaultNbeecsEtox(pars, etox, p.Nursebeecs, a)
+	} else {
+		a = model.DefaultEtox(pars, etox, a)
+	}
+
+	data := [][]float64{}
+	a.AddSystem(&reporter.RowCallback{
+		Observer: p.Observer(),
+		Callback: func(step int, row []float64) {
+			values := make([]float64, len(indices))
+			for i, idx := range indices {
+				values[i] = row[idx]
+			}
+			data = append(data, values)
+		},
+	})
+	a.Run()
+
+	return data, ecs.GetResource[globals.AccountingEvents](&a.World).Err()
+}
+
+// Difference returns the difference treatment minus control per tick and column, for the treatment with the given index.
+// If a run terminated early, only the ticks covered by both runs are compared.
+func (r *PairedResult) Difference(treatment int) [][]float64 {
+	return r.compare(treatment, func(c, t float64) float64 { return t - c })
+}
+
+// Ratio returns the ratio of treatment to control per tick and column, for the treatment with the given index.
+// The ratio is NaN where the control is zero.
+// If a run terminated early, only the ticks covered by both runs are compared.
+func (r *PairedResult) Ratio(treatment int) [][]float64 {
+	return r.compare(treatment, func(c, t float64) float64 {
+		if c == 0 {
+			return math.NaN()
+		}
+		return t / c
+	})
+}
+
+// WriteCSV writes control and treatment values, differences and ratios of all treatments to a CSV file,
+// with one row per treatment and tick.
+func (r *PairedResult) WriteCSV(file string, sep string) error {
+	b := strings.Builder{}
+	header := []string{"Tick", "Treatment"}
+	for _, c := range r.Columns {
+		header = append(header, c+"_control", c, c+"_diff", c+"_ratio")
+	}
+	b.WriteString(strings.Join(header, sep) + "\n")
+
+	for t := range r.Treatments {
+		diff := r.Difference(t)
+		ratio := r.Ratio(t)
+		for tick := range diff {
+			fmt.Fprintf(&b, "%d%s%d", tick, sep, t)
+			for c := range r.Columns {
+				fmt.Fprintf(&b, "%s%g%s%g%s%g%s%g", sep, r.Control[tick][c], sep, r.Treatments[t][tick][c], sep, diff[tick][c], sep, ratio[tick][c])
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if dir := filepath.Dir(file); dir != "" {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(file, []byte(b.String()), 0666)
+}
+
+func (r *PairedResult) compare(treatment int, fn func(c, t float64) float64) [][]float64 {
+	data := r.Treatments[treatment]
+	ticks := min(len(r.Control), len(data))
+	result := make([][]float64, ticks)
+	for tick := range ticks {
+		result[tick] = make([]float64, len(r.Columns))
+		for c := range r.Columns {
+			result[tick][c] = fn(r.Control[tick][c], data[tick][c])
+		}
+	}
+	return result
+}
+
+// columnIndices returns the indices of the given columns in the header, or of all columns if none are given.
+func columnIndices(header []string, columns []string) ([]int, error) {
+	if len(columns) == 0 {
+		indices := make([]int, len(header))
+		for i := range header {
+			indices[i] = i
+		}
+		return indices, nil
+	}
+	indices := make([]int, len(columns))
+	for i, c := range columns {
+		idx := -1
+		for j, h := range header {
+			if h == c {
+				idx = j
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("column '%s' not found in observer header", c)
+		}
+		indices[i] = idx
+	}
+	return indices, nil
+}
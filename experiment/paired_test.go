@@ -0,0 +1,80 @@
+package experiment
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/obs"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark-tools/observer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaired(t *testing.T) {
+	p := params.Default()
+	p.Termination.MaxTicks = 200
+
+	control := params.DefaultEtox()
+	treatment := params.DefaultEtox()
+	treatment.PPPApplication.Application = true
+	treatment.PPPApplication.AppDay = 150
+	treatment.PPPApplication.PPPconcentrationNectar = 1000
+	treatment.PPPApplication.PPPconcentrationPollen = 10000
+	treatment.PPPApplication.ExposurePeriod = 10
+
+	paired := Paired{
+		Params:     p,
+		Control:    control,
+		Treatments: []params.DefaultParamsEtox{treatment},
+		Observer:   func() observer.Row { return &obs.Debug{} },
+		Columns:    []string{"TotalForagers", "TotalPop"},
+	}
+	res, err := paired.Run(nil)
+	assert.Nil(t, err)
+	assert.Greater(t, res.Seed, 0)
+	assert.Equal(t, []string{"TotalForagers", "TotalPop"}, res.Columns)
+	assert.Equal(t, 200, len(res.Control))
+	assert.Equal(t, 1, len(res.Treatments))
+
+	diff := res.Difference(0)
+	ratio := res.Ratio(0)
+	for tick := range treatment.PPPApplication.AppDay {
+		assert.Equal(t, []float64{0, 0}, diff[tick])
+		assert.Equal(t, 1.0, ratio[tick][1])
+	}
+	assert.Less(t, diff[len(diff)-1][0], 0.0)
+
+	assert.Nil(t, res.WriteCSV(filepath.Join(t.TempDir(), "paired.csv"), ";"))
+
+	paired.Columns = []string{"Foo"}
+	runs := 0
+	paired.Observer = func() observer.Row { runs++; return &obs.Debug{} }
+	_, err = paired.Run(nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, runs) // the header is checked before any run
+}
+
+func TestPairedAccountingEvents(t *testing.T) {
+	p := params.Default()
+	p.Termination.MaxTicks = 200
+	p.RandomSeed.Seed = 1
+
+	treatment := params.DefaultEtox()
+	treatment.PPPApplication.Application = true
+	treatment.PPPApplication.AppDay = 150
+	treatment.PPPApplication.AccountingTolerance = 1e-15 // rounding errors of the stores become events
+	treatment.MassBalance.Epsilon = 0
+
+	paired := Paired{
+		Params:     p,
+		Control:    params.DefaultEtox(),
+		Treatments: []params.DefaultParamsEtox{treatment},
+		Observer:   func() observer.Row { return &obs.AccountingEvents{} },
+	}
+	res, err := paired.Run(nil)
+	assert.ErrorContains(t, err, "treatment 0: tick")
+	assert.NotContains(t, err.Error(), "control")
+	assert.Equal(t, 200, len(res.Treatments[0])) // the run is completed
+	assert.Greater(t, res.Treatments[0][199][0], 0.0)
+	assert.Equal(t, 0.0, res.Control[199][0])
+}
@@ -0,0 +1,44 @@
+package globals
+
+import "fmt"
+
+// AccountingEvent is a structured warning on an imbalance in the mass or energy accounting
+// of the ecotox stores and doses. It implements error.
+type AccountingEvent struct {
+	Tick     int64   // Tick in which the imbalance was detected.
+	Check    string  // Name of the failed check.
+	Expected float64 // Reference value.
+	Actual   float64 // Accounted value.
+}
+
+func (e AccountingEvent) Error() string {
+	return fmt.Sprintf("tick %d: %s imbalance: expected %f, got %f", e.Tick, e.Check, e.Expected, e.Actual)
+}
+
+// MaxAccountingEvents is the number of accounting events kept per run. Further events are only counted,
+// so that a persistent drift does not grow the list in every tick.
+const MaxAccountingEvents = 100
+
+// AccountingEvents collects the accounting events of a run.
+// Imbalances are reconciled where possible, so the run can continue.
+// A summary is logged at the end of the run by sys.PPPMassBalance, see also obs.AccountingEvents.
+type AccountingEvents struct {
+	Events []AccountingEvent // The first MaxAccountingEvents events.
+	Count  int               // Number of all events, including those not kept.
+}
+
+// Add an event.
+func (a *AccountingEvents) Add(tick int64, check string, expected float64, actual float64) {
+	a.Count++
+	if len(a.Events) < MaxAccountingEvents {
+		a.Events = append(a.Events, AccountingEvent{Tick: tick, Check: check, Expected: expected, Actual: actual})
+	}
+}
+
+// Err returns the first event as an error, or nil if there were no events.
+func (a *AccountingEvents) Err() error {
+	if len(a.Events) == 0 {
+		return nil
+	}
+	return a.Events[0]
+}
@@ -0,0 +1,20 @@
+package globals_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountingEvents(t *testing.T) {
+	events := globals.AccountingEvents{}
+	assert.Nil(t, events.Err())
+
+	for tick := range int64(globals.MaxAccountingEvents + 10) {
+		events.Add(tick, "honey store energy", 100, 90)
+	}
+	assert.Len(t, events.Events, globals.MaxAccountingEvents)
+	assert.Equal(t, globals.MaxAccountingEvents+10, events.Count)
+	assert.EqualError(t, events.Err(), "tick 0: honey store energy imbalance: expected 100.000000, got 90.000000")
+}
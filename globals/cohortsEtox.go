@@ -4,10 +4,33 @@ package globals
 type LarvaeEtox struct {
 	WorkerCohortDose []float64 // Mean PPP oral dose per cohort.
 	DroneCohortDose  []float64 // Mean PPP oral dose per cohort.
+
+	WorkerDoseHistory [][]float64 // Daily oral doses per cohort over the chronic exposure window, most recent day first.
+	DroneDoseHistory  [][]float64 // Daily oral doses per cohort over the chronic exposure window, most recent day first.
+
+	WorkerLarvalDose []float64 // Cumulative oral dose per larva since hatching; exposure marker for sublethal effects.
+	DroneLarvalDose  []float64 // Cumulative oral dose per larva since hatching; exposure marker for sublethal effects.
+}
+
+// PupaeEtox contains the exposure marker of worker and drone pupae cohorts, carried over from the larval stage.
+type PupaeEtox struct {
+	WorkerLarvalDose []float64 // Cumulative oral dose per individual received as a larva.
+	DroneLarvalDose  []float64 // Cumulative oral dose per individual received as a larva.
 }
 
-// InHiveEtox contains oral doses for in-hive worker and drone cohorts; divided by age like in BEEHAVE.
+// InHiveEtox contains oral and contact doses for in-hive worker and drone cohorts; divided by age like in BEEHAVE.
 type InHiveEtox struct {
 	WorkerCohortDose []float64 // Mean PPP oral dose per cohort.
 	DroneCohortDose  []float64 // Mean PPP oral dose per cohort.
+
+	WorkerDoseHistory [][]float64 // Daily oral doses per cohort over the chronic exposure window, most recent day first.
+	DroneDoseHistory  [][]float64 // Daily oral doses per cohort over the chronic exposure window, most recent day first.
+
+	WorkerContactDose []float64 // Mean PPP contact dose per cohort, e.g. from in-hive treatments.
+	DroneContactDose  []float64 // Mean PPP contact dose per cohort, e.g. from in-hive treatments.
+
+	WorkerLarvalDose []float64 // Cumulative oral dose per individual received as a larva; exposure marker for sublethal effects.
+	DroneLarvalDose  []float64 // Cumulative oral dose per individual received as a larva; exposure marker for sublethal effects.
+
+	ForagerLarvalDose float64 // Mean exposure marker of the in-hive workers turning into foragers in the current step; handed on to the new squadrons.
 }
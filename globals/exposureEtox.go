@@ -0,0 +1,39 @@
+package globals
+
+import "github.com/fzeitner/Nursebeecs-master-thesis/comp"
+
+// ForagerExposure is the lifetime exposure of a forager squadron to PPP.
+type ForagerExposure struct {
+	Tick            int64   // Tick of death, or of the observation for living squadrons.
+	Alive           bool    // Whether the squadron was still alive.
+	Reverted        bool    // Whether the squadron was a reverted forager.
+	CumOralDose     float64 // Lifetime cumulative oral dose [µg].
+	CumContactDose  float64 // Lifetime cumulative contact dose [µg].
+	DaysExposed     int     // Number of days with a non-zero oral or contact dose.
+	PeakOralDose    float64 // Highest daily oral dose [µg].
+	PeakContactDose float64 // Highest daily contact dose [µg].
+}
+
+// NewForagerExposure creates the lifetime exposure record of a squadron.
+// The daily doses that have not been evaluated by the mortality check yet are included.
+func NewForagerExposure(tick int64, expo *comp.PPPExpo, reverted bool, alive bool) ForagerExposure {
+	rec := ForagerExposure{
+		Tick:            tick,
+		Alive:           alive,
+		Reverted:        reverted,
+		CumOralDose:     expo.CumOralDose + expo.OralDose,
+		CumContactDose:  expo.CumContactDose + expo.Contact(),
+		DaysExposed:     expo.DaysExposed,
+		PeakOralDose:    max(expo.PeakOralDose, expo.OralDose),
+		PeakContactDose: max(expo.PeakContactDose, expo.Contact()),
+	}
+	if expo.OralDose > 0 || expo.Contact() > 0 {
+		rec.DaysExposed++
+	}
+	return rec
+}
+
+// ForagerExposureRecords collects the lifetime exposure of forager squadrons at their death.
+type ForagerExposureRecords struct {
+	Dead []ForagerExposure
+}
@@ -0,0 +1,20 @@
+package globals
+
+import "github.com/fzeitner/Nursebeecs-master-thesis/enum/management"
+
+// ManagementRecord is a beekeeper management action that took place, with its effects on the colony.
+type ManagementRecord struct {
+	Tick   int64             // Tick of the action.
+	Name   string            // Name of the event.
+	Action management.Action // Type of the action.
+	Honey  float64           // Change of the honey stores [kJ].
+	Pollen float64           // Change of the pollen stores [g].
+	Brood  int               // Change of the number of brood cells.
+	Adults int               // Change of the number of adult bees.
+}
+
+// ManagementRecords collects all beekeeper management actions of a run,
+// see [github.com/fzeitner/Nursebeecs-master-thesis/params.Management].
+type ManagementRecords struct {
+	Records []ManagementRecord
+}
@@ -15,9 +15,10 @@ type NursingGlobals struct {
 	NurseAgeMax   int     // current upper bound age of nurses
 	NurseWorkLoad float64 // ratio of protein nurses need to provide to the amount they on average can provide as a metric for their workload
 
-	Total_honey           float64 // amount of total honey to be taken in by nurses on top of their own intake this timestep [mg/d]
-	Total_pollen          float64 // amount of total pollen to be taken in by nurses on top of their own intake this timestep [mg/d]
-	CurrentMaxPollenNurse float64 // maximum intake of pollen at the current point in time; this can might be reduced during PPP exposure because of HG effects
+	Total_honey           float64   // amount of total honey to be taken in by nurses on top of their own intake this timestep [mg/d]
+	Total_pollen          float64   // amount of total pollen to be taken in by nurses on top of their own intake this timestep [mg/d]
+	CurrentMaxPollenNurse float64   // maximum intake of pollen at the current point in time
+	Capability            []float64 // nursing capability per IHbee age today; Nursingcapabiliies from the parameters, or the HPG size if HGEffects are turned on
 
 	WLHoney  float64 // amount of direct honey worker larvae take in this timestep [mg/d]
 	WLPollen float64 // amount of direct pollen worker larvae take in this timestep [mg/d]
@@ -32,6 +33,15 @@ type NursingGlobals struct {
 	SquadstoReduce int // amount of reverted forager squadrons that will be returned to normal forager squadrons next timestep
 }
 
+// HPG contains the state of the hypopharyngeal glands of IHbee worker cohorts; divided by age like the cohorts.
+//
+// Only IHbee cohorts have a gland state. Reverted foragers and winter bees nurse with the maximum pollen intake of nurses
+// (NursingGlobals.CurrentMaxPollenNurse) instead, so nutrition and PPP do not affect their jelly production via the glands.
+type HPG struct {
+	Size []float64 // relative gland size per cohort in [0, 1]; sets the jelly production capacity of nurses
+	Dose []float64 // daily oral PPP dose per bee of each cohort from the previous day [µg]
+}
+
 // track nursing related consumption and population stats; the ints below are also used for regulations in nursing subsystems
 type NursingStats struct {
 	MaxPollenIntake  float64 // current maximum of pollen intake per nurse
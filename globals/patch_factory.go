@@ -14,7 +14,8 @@ type PatchFactory struct {
 		comp.PatchDistance, comp.Trip, comp.HandlingTime,
 		comp.Resource, comp.Mortality, comp.Dance,
 		comp.Visits]
-	initMapper *ecs.Map2[comp.Coords, comp.PatchDistance]
+	initMapper  *ecs.Map2[comp.Coords, comp.PatchDistance]
+	waterMapper *ecs.Map3[comp.Coords, comp.PatchDistance, comp.WaterPatch]
 
 	constantPatchMapper *ecs.Map1[comp.ConstantPatch]
 	seasonalPatchMapper *ecs.Map1[comp.SeasonalPatch]
@@ -30,7 +31,8 @@ func NewPatchFactory(world *ecs.World) PatchFactory {
 			comp.Resource, comp.Mortality, comp.Dance,
 			comp.Visits](world),
 
-		initMapper: ecs.NewMap2[comp.Coords, comp.PatchDistance](world),
+		initMapper:  ecs.NewMap2[comp.Coords, comp.PatchDistance](world),
+		waterMapper: ecs.NewMap3[comp.Coords, comp.PatchDistance, comp.WaterPatch](world),
 
 		constantPatchMapper: ecs.NewMap1[comp.ConstantPatch](world),
 		seasonalPatchMapper: ecs.NewMap1[comp.SeasonalPatch](world),
@@ -56,11 +58,20 @@ func (f *PatchFactory) CreatePatches(conf []comp.PatchConfig) {
 }
 
 func (f *PatchFactory) createPatch(conf comp.PatchConfig, coords comp.Coords) {
+	if conf.WaterPatch != nil {
+		if conf.ConstantPatch != nil || conf.SeasonalPatch != nil || conf.ScriptedPatch != nil {
+			panic("each patch must have exactly one of ConstantPatch, SeasonalPatch, ScriptedPatch or WaterPatch, has multiple")
+		}
+		f.waterMapper.NewEntity(&coords, &comp.PatchDistance{DistToColony: conf.DistToColony, DistToField: conf.DistToField}, conf.WaterPatch)
+		return
+	}
+
 	e := f.builder.NewEntityFn(nil)
 
 	co, dist := f.initMapper.Get(e)
 	*co = coords
 	dist.DistToColony = conf.DistToColony
+	dist.DistToField = conf.DistToField
 
 	anyPatch := false
 
@@ -71,7 +82,7 @@ func (f *PatchFactory) createPatch(conf comp.PatchConfig, coords comp.Coords) {
 
 	if conf.SeasonalPatch != nil {
 		if anyPatch {
-			panic("each patch must have exactly one of ConstantPatch, SeasonalPatch or ScriptedPatch, has multiple")
+			panic("each patch must have exactly one of ConstantPatch, SeasonalPatch, ScriptedPatch or WaterPatch, has multiple")
 		}
 		f.seasonalPatchMapper.Add(e, conf.SeasonalPatch)
 		anyPatch = true
@@ -79,13 +90,13 @@ func (f *PatchFactory) createPatch(conf comp.PatchConfig, coords comp.Coords) {
 
 	if conf.ScriptedPatch != nil {
 		if anyPatch {
-			panic("each patch must have exactly one of ConstantPatch, SeasonalPatch or ScriptedPatch, has multiple")
+			panic("each patch must have exactly one of ConstantPatch, SeasonalPatch, ScriptedPatch or WaterPatch, has multiple")
 		}
 		f.scriptedPatchMapper.Add(e, conf.ScriptedPatch)
 		anyPatch = true
 	}
 
 	if !anyPatch {
-		panic("each patch must have exactly one of ConstantPatch, SeasonalPatch or ScriptedPatch, has none")
+		panic("each patch must have exactly one of ConstantPatch, SeasonalPatch, ScriptedPatch or WaterPatch, has none")
 	}
 }
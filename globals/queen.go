@@ -0,0 +1,33 @@
+package globals
+
+import "github.com/fzeitner/Nursebeecs-master-thesis/enum/queen"
+
+// Queen contains the state of the colony's queen, see [github.com/fzeitner/Nursebeecs-master-thesis/params.Queen].
+type Queen struct {
+	State        queen.State // State of the colony concerning its queen.
+	Age          int         // Age of the laying queen, or of the virgin queen while mating [d].
+	Timer        int         // Remaining days of emergency rearing or mating [d].
+	Supersedure  int         // Remaining days until a queen reared for supersedure emerges; 0 if none is reared [d].
+	Replacements int         // Number of new queens that started laying since the start of the simulation.
+
+	Dose      float64 // Oral dose of the queen today [µg]. Only with PPP effects on the queen.
+	Fecundity float64 // Fraction of the maximum egg laying rate achieved by the queen today, reduced by PPP [ ].
+}
+
+// Lose removes the laying queen, e.g. because she died or left with a swarm.
+// A queen reared for supersedure takes over, otherwise the colony tries to rear an emergency queen.
+func (q *Queen) Lose() {
+	if q.Supersedure > 0 {
+		q.State = queen.Rearing
+		q.Timer = q.Supersedure
+		q.Supersedure = 0
+		return
+	}
+	q.State = queen.Queenless
+	q.Timer = 0
+}
+
+// IsLaying returns whether the colony has a laying queen.
+func (q *Queen) IsLaying() bool {
+	return q.State == queen.Laying
+}
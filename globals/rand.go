@@ -0,0 +1,37 @@
+package globals
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+)
+
+// RandStreams provides named random number streams for the systems and processes of the model.
+//
+// All streams are derived from the random seed of the run, but are independent of each other
+// and of the order in which they are requested. Thus, enabling a sub-model only changes the draws
+// made by that sub-model, and runs with the same seed remain comparable (common random numbers).
+type RandStreams struct {
+	seed    uint64
+	streams map[string]*rand.PCG
+}
+
+// NewRandStreams creates the random number streams for the given seed.
+func NewRandStreams(seed int) RandStreams {
+	return RandStreams{
+		seed:    uint64(seed),
+		streams: map[string]*rand.PCG{},
+	}
+}
+
+// Get returns the random source of the stream with the given name.
+// Systems requesting the same name share the source.
+func (r *RandStreams) Get(name string) rand.Source {
+	if src, ok := r.streams[name]; ok {
+		return src
+	}
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	src := rand.NewPCG(r.seed, h.Sum64())
+	r.streams[name] = src
+	return src
+}
@@ -23,6 +23,13 @@ type PopulationStatsEtox struct {
 
 	CumDoseNurses  float64 // changed to a mean dose per bee; doesn´t do anything, just there for debugging
 	MeanDoseNurses float64 // cumulative dose before calculating a mean, used for debugging
+
+	PupalFailures   int // number of pupae that failed to develop due to their larval dose in the current step
+	DelayedPupae    int // number of pupae held back from emergence due to their larval dose in the current step
+	LongevityDeaths int // number of in-hive bees and foragers that died due to reduced longevity from their larval dose in the current step
+
+	WorkerJellyConc float64 // PPP concentration in the food processed by nurses for worker larvae in the current step [µg/kg]
+	DroneJellyConc  float64 // PPP concentration in the food processed by nurses for drone larvae in the current step [µg/kg]
 }
 
 // Reset all stats to zero.
@@ -42,6 +49,13 @@ func (s *PopulationStatsEtox) Reset() {
 
 	s.PPPNursebees = 0
 
+	s.PupalFailures = 0
+	s.DelayedPupae = 0
+	s.LongevityDeaths = 0
+
+	s.WorkerJellyConc = 0
+	s.DroneJellyConc = 0
+
 	s.CumDoseNurses = 0
 	s.MeanDoseNurses = 0
 
@@ -72,6 +86,7 @@ type ForagingStatsEtox struct {
 	Pollensuccess          float64 // debugging global; successful pollen foraging flights
 	Collectionflightstotal int     // debugging global; total amount of successful foraging flights
 	Foragerdied            int     // debugging global; amount squadrons that died due to direct foraging mortality
+	HomingFailures         int     // amount of squadrons lost due to sublethal homing failure; included in Foragerdied
 	TotalSearches          int     //debugging global; total amount of searching flights
 
 	MaxEnergyExpenditure  float64 // for tracking the energy use of foragers per foraging day
@@ -89,6 +104,7 @@ func (s *ForagingStatsEtox) Reset() {
 	s.Pollensuccess = 0.
 	s.Collectionflightstotal = 0
 	s.Foragerdied = 0
+	s.HomingFailures = 0
 	s.TotalSearches = 0
 
 	s.MaxEnergyExpenditure = 0.
@@ -1,10 +1,25 @@
 package globals
 
+import (
+	"math"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
+)
+
 // globals necessary for the _ecotox additions of water foraging, ecotoxicological variables and honey storage compartimentation
-// none of this is used with the default ecotox settings and should not, because of a lack of testing atm
 
+// WaterNeeds holds today's water need for cooling the hive.
 type WaterNeeds struct {
-	ETOX_Waterneedforcooling float64 // The amount of water needed for cooling today
+	ETOX_Waterneedforcooling float64 // The amount of water needed for cooling today [g]
+}
+
+// WaterForagingStats contains statistics of today's water foraging.
+type WaterForagingStats struct {
+	NeedCooling  float64 // Water needed for cooling today [g].
+	NeedDilution float64 // Water needed for the dilution of honey eaten yesterday [g].
+	Collected    float64 // Water collected today [g].
+	Squadrons    int     // Number of forager squadrons foraging for water today.
+	PPP          float64 // PPP brought into the hive with water today [µg].
 }
 
 // WaterForagingPeriodData contains data on daily foraging hours.
@@ -16,26 +31,23 @@ type WaterForagingPeriodData struct {
 	CurrentYear int
 }
 
-// StoragesEtox tracks the current concentration of PPP inside of the compartimentarized honey stores added in BEEHAVE_ecotox.
+// StoragesEtox tracks the current concentration of PPP inside of the pollen stores,
+// for the compartimentarized honey stores added in BEEHAVE_ecotox see [HoneyStoresEtox].
 // It also traks some PPP-related ovserving variables as well as the energy necessary for thermoregulation.
 type StoragesEtox struct {
-	PPPInHivePollenConc float64 // Concentration of PPP currently in stored pollen [mug/g].
+	PPPInHivePollenConc float64 // Concentration of PPP currently in stored pollen [mug/g]; with pollen compartments, the concentration of the pollen eaten during consumption.
 	ETOX_EnergyThermo   float64 // Energy needed for Thermoregulation of hive/brood made global for Etox_consumption purposes.
 
-	ETOX_HES_E_Capped float64 // Energy in the capped honey cells                                                 [kJ]
-	ETOX_HES_C_Capped float64 // Average concentration of pesticide in the capped honey cells                     [µg/kJ]
-	ETOX_HES_E_D0     float64 // Energy in the uncapped honey cells of today                                      [kJ]
-	ETOX_HES_C_D0     float64 // Average concentration of pesticide in the uncapped honey cells of today          [µg/kJ]
-	ETOX_HES_E_D1     float64 // Energy in the uncapped honey cells of yesterday                                  [kJ]
-	ETOX_HES_C_D1     float64 // Average concentration of pesticide in the uncapped honey cells of yesterday      [µg/kJ]
-	ETOX_HES_E_D2     float64 // Energy in the uncapped honey cells of two days ago                               [kJ]
-	ETOX_HES_C_D2     float64 // Average concentration of pesticide in the uncapped honey cells of two days ago   [µg/kJ]
-	ETOX_HES_E_D3     float64 // Energy in the uncapped honey cells of three days ago                             [kJ]
-	ETOX_HES_C_D3     float64 // Average concentration of pesticide in the uncapped honey cells of three days ago [µg/kJ]
-	ETOX_HES_E_D4     float64 // Energy in the uncapped honey cells of four days ago                              [kJ]
-	ETOX_HES_C_D4     float64 // Average concentration of pesticide in the uncapped honey cells of four days ago  [µg/kJ]
-
-	ETOX_Waterneedfordilution float64 // The amount of water needed for diluation of honey yesterday
+	HoneyResidueAge  float64 // Mean age of the PPP in the honey stores, weighted by the deposits; the time base for biphasic degradation kinetics [d].
+	PollenResidueAge float64 // Mean age of the PPP in the pollen stores, weighted by the deposits; the time base for biphasic degradation kinetics [d].
+	HoneyResidue     float64 // PPP left in the honey stores after the last degradation [µg].
+	PollenResidue    float64 // PPP left in the pollen stores after the last degradation [µg].
+
+	ETOX_Waterneedfordilution float64 // The amount of water needed for diluation of honey eaten since the last water foraging [g]
+	ETOX_Waterfordilution     float64 // Water collected for the dilution of honey and not used yet [g]
+	PPPwater                  float64 // PPP in the water collected for dilution and not used yet [µg]
+	PPPwax                    float64 // PPP in the comb wax [µg]
+	PPPtreatment              float64 // PPP in the residue of in-hive treatments, available for contact exposure of adults [µg]
 
 	Pollenconcbeforeeating float64 // added for bugfixing
 	Nectarconcbeforeeating float64 // added for bugfixing
@@ -45,8 +57,373 @@ type StoragesEtox struct {
 	PPPTotal       float64 // total amount of PPP in all stores this timestep
 }
 
+// UseWater takes the given amount of water [g] for diluting honey from the collected water
+// and returns the PPP contained in it [µg]. Only collected water carries PPP.
+func (s *StoragesEtox) UseWater(water float64) float64 {
+	if s.ETOX_Waterfordilution <= 0 || water <= 0 {
+		return 0
+	}
+	used := math.Min(water, s.ETOX_Waterfordilution)
+	ppp := s.PPPwater * used / s.ETOX_Waterfordilution
+	s.ETOX_Waterfordilution -= used
+	s.PPPwater -= ppp
+	return ppp
+}
+
+// HoneyStoresEtox tracks the honey stores of BEEHAVE_ecotox in daily compartments of uncapped honey plus capped cells.
+//
+// Index 0 of the uncapped compartments holds the nectar stored today, the number of compartments is
+// the number of days until honey gets capped. Capped honey is well mixed and eaten last.
+type HoneyStoresEtox struct {
+	Energy []float64 // Energy in the uncapped honey cells per age [kJ].
+	Conc   []float64 // Average concentration of pesticide in the uncapped honey cells per age [µg/kJ].
+
+	CappedEnergy float64 // Energy in the capped honey cells [kJ].
+	CappedConc   float64 // Average concentration of pesticide in the capped honey cells [µg/kJ].
+
+	Deficit float64 // Energy eaten while the stores were empty, paid back by the next nectar stored [kJ].
+}
+
+// Add nectar with the given amount of PPP to today's compartment.
+// Any deficit is paid back first, the PPP stays in the stores.
+// Returns the amount of PPP that could not be stored because there is no honey left to hold it.
+func (h *HoneyStoresEtox) Add(energy float64, ppp float64) (lost float64) {
+	if energy <= 0 {
+		return
+	}
+	if h.Deficit > 0 {
+		paid := math.Min(energy, h.Deficit)
+		h.Deficit -= paid
+		energy -= paid
+		if energy <= 0 {
+			if h.CappedEnergy > 0 {
+				h.CappedConc += ppp / h.CappedEnergy
+				return
+			}
+			return ppp
+		}
+	}
+	h.Conc[0] = (h.Conc[0]*h.Energy[0] + ppp) / (h.Energy[0] + energy)
+	h.Energy[0] += energy
+	return
+}
+
+// Exchange adds the given amount of PPP to the honey stores, or removes it if negative, e.g. for the exchange with comb wax.
+// Added PPP is spread evenly over all honey, removed PPP is taken proportionally to the PPP in each compartment.
+func (h *HoneyStoresEtox) Exchange(ppp float64) {
+	if ppp >= 0 {
+		energy := h.Total()
+		if energy <= 0 {
+			return
+		}
+		for i := range h.Conc {
+			h.Conc[i] += ppp / energy
+		}
+		h.CappedConc += ppp / energy
+		return
+	}
+	total := h.TotalPPP()
+	if total <= 0 {
+		return
+	}
+	factor := math.Max(1+ppp/total, 0)
+	for i := range h.Conc {
+		h.Conc[i] *= factor
+	}
+	h.CappedConc *= factor
+}
+
+// Uncapped returns the total energy in the uncapped honey cells [kJ].
+func (h *HoneyStoresEtox) Uncapped() float64 {
+	return util.Sum(h.Energy...)
+}
+
+// Total returns the total energy in the honey stores [kJ].
+func (h *HoneyStoresEtox) Total() float64 {
+	sum := util.CompensatedSum{}
+	for _, e := range h.Energy {
+		sum.Add(e)
+	}
+	sum.Add(h.CappedEnergy)
+	sum.Add(-h.Deficit)
+	return sum.Value()
+}
+
+// TotalPPP returns the total amount of PPP in the honey stores [µg].
+func (h *HoneyStoresEtox) TotalPPP() float64 {
+	sum := util.CompensatedSum{}
+	for i, e := range h.Energy {
+		sum.Add(e * h.Conc[i])
+	}
+	sum.Add(h.CappedEnergy * h.CappedConc)
+	return sum.Value()
+}
+
+// Reconcile scales all compartments to the given total energy, e.g. the honey store of beecs.
+// The amount of PPP in the stores is preserved. Returns the energy before reconciliation.
+func (h *HoneyStoresEtox) Reconcile(energy float64) (before float64) {
+	before = h.Total()
+	if before == energy {
+		return
+	}
+	// only the honey actually present gets scaled, the deficit is kept
+	present := before + h.Deficit
+	target := energy + h.Deficit
+	if present <= 0 || target <= 0 {
+		ppp := h.TotalPPP()
+		h.Clear()
+		if energy > 0 {
+			h.CappedEnergy = energy
+			h.CappedConc = ppp / energy
+		} else {
+			h.Deficit = -energy
+		}
+		return
+	}
+	factor := target / present
+	for i := range h.Energy {
+		h.Energy[i] *= factor
+		h.Conc[i] /= factor
+	}
+	h.CappedEnergy *= factor
+	h.CappedConc /= factor
+	return
+}
+
+// NextConc returns the concentration of the honey that would be eaten next [µg/kJ].
+func (h *HoneyStoresEtox) NextConc(oldestFirst bool) float64 {
+	n := len(h.Energy)
+	for j := range n {
+		i := j
+		if oldestFirst {
+			i = n - 1 - j
+		}
+		if h.Energy[i] != 0 {
+			return h.Conc[i]
+		}
+	}
+	if h.CappedEnergy != 0 {
+		return h.CappedConc
+	}
+	return 0
+}
+
+// Feed removes the given energy from the stores.
+// Uncapped honey is eaten first, newest or oldest first, followed by capped honey.
+// Returns the amount of PPP ingested, and the energy taken from capped cells.
+func (h *HoneyStoresEtox) Feed(cons float64, oldestFirst bool) (ppp float64, capped float64) {
+	n := len(h.Energy)
+	for j := range n {
+		i := j
+		if oldestFirst {
+			i = n - 1 - j
+		}
+		if cons < h.Energy[i] {
+			ppp += cons * h.Conc[i]
+			h.Energy[i] -= cons
+			return
+		}
+		ppp += h.Energy[i] * h.Conc[i]
+		cons -= h.Energy[i]
+		h.Energy[i] = 0
+	}
+	capped = math.Min(cons, h.CappedEnergy)
+	ppp += capped * h.CappedConc
+	h.CappedEnergy -= capped
+	h.Deficit += cons - capped
+	return
+}
+
+// Harvest removes the given energy from the stores, like a beekeeper taking honey combs.
+// Capped honey is taken first, followed by uncapped honey oldest first. Returns the amount of PPP removed.
+func (h *HoneyStoresEtox) Harvest(energy float64) (ppp float64) {
+	capped := math.Min(energy, h.CappedEnergy)
+	ppp += capped * h.CappedConc
+	h.CappedEnergy -= capped
+	energy -= capped
+	for i := len(h.Energy) - 1; i >= 0 && energy > 0; i-- {
+		e := math.Min(energy, h.Energy[i])
+		ppp += e * h.Conc[i]
+		h.Energy[i] -= e
+		energy -= e
+	}
+	return
+}
+
+// Split removes the given fraction of the honey in each compartment, e.g. for a colony split.
+// Returns the amount of PPP removed.
+func (h *HoneyStoresEtox) Split(fraction float64) (ppp float64) {
+	ppp = h.TotalPPP() * fraction
+	for i := range h.Energy {
+		h.Energy[i] *= 1 - fraction
+	}
+	h.CappedEnergy *= 1 - fraction
+	return
+}
+
+// Shift ages the uncapped compartments by one day and caps the oldest one.
+// Additionally, the given fraction of each uncapped compartment gets capped.
+func (h *HoneyStoresEtox) Shift(cappingFraction float64) {
+	last := len(h.Energy) - 1
+	if cappingFraction > 0 {
+		for i := 0; i < last; i++ {
+			h.cap(h.Energy[i]*cappingFraction, h.Conc[i])
+			h.Energy[i] -= h.Energy[i] * cappingFraction
+		}
+	}
+	h.cap(h.Energy[last], h.Conc[last])
+	copy(h.Energy[1:], h.Energy[:last])
+	copy(h.Conc[1:], h.Conc[:last])
+	h.Energy[0] = 0
+	h.Conc[0] = 0
+}
+
+func (h *HoneyStoresEtox) cap(energy float64, conc float64) {
+	if h.CappedEnergy+energy > 0 {
+		h.CappedConc = (h.CappedConc*h.CappedEnergy + conc*energy) / (h.CappedEnergy + energy)
+	}
+	h.CappedEnergy += energy
+}
+
+// Clear empties all compartments.
+func (h *HoneyStoresEtox) Clear() {
+	for i := range h.Energy {
+		h.Energy[i] = 0
+		h.Conc[i] = 0
+	}
+	h.CappedEnergy = 0
+	h.CappedConc = 0
+	h.Deficit = 0
+}
+
+// PollenStoresEtox tracks stored pollen (bee bread) in daily age compartments.
+// It is only used if [params.PPPApplication] PollenCompartments is true,
+// otherwise the well-mixed StoragesEtox.PPPInHivePollenConc is used.
+//
+// Index 0 holds the pollen stored today, the last index pools all older pollen.
+// Pollen is consumed oldest first.
+type PollenStoresEtox struct {
+	Mass []float64 // Pollen per age compartment [g].
+	Conc []float64 // Concentration of PPP per age compartment [mug/g].
+}
+
+// Add pollen with the given amount of PPP to today's compartment.
+func (p *PollenStoresEtox) Add(mass float64, ppp float64) {
+	if mass <= 0 {
+		return
+	}
+	p.Conc[0] = (p.Conc[0]*p.Mass[0] + ppp) / (p.Mass[0] + mass)
+	p.Mass[0] += mass
+}
+
+// Exchange adds the given amount of PPP to the pollen stores, or removes it if negative, e.g. for the exchange with comb wax.
+// Added PPP is spread evenly over all pollen, removed PPP is taken proportionally to the PPP in each compartment.
+func (p *PollenStoresEtox) Exchange(ppp float64) {
+	mass, total := p.Total()
+	if ppp >= 0 {
+		if mass <= 0 {
+			return
+		}
+		for i := range p.Conc {
+			p.Conc[i] += ppp / mass
+		}
+		return
+	}
+	if total <= 0 {
+		return
+	}
+	factor := math.Max(1+ppp/total, 0)
+	for i := range p.Conc {
+		p.Conc[i] *= factor
+	}
+}
+
+// AddOld adds pollen with the given concentration to the oldest compartment, e.g. for initial stores.
+func (p *PollenStoresEtox) AddOld(mass float64, conc float64) {
+	last := len(p.Mass) - 1
+	if mass <= 0 {
+		return
+	}
+	p.Conc[last] = (p.Conc[last]*p.Mass[last] + conc*mass) / (p.Mass[last] + mass)
+	p.Mass[last] += mass
+}
+
+// ConsumptionConc returns the mean concentration of the given amount of pollen
+// when it is consumed oldest first, without removing it.
+// If the stores hold less pollen, the missing part is treated as free of PPP.
+func (p *PollenStoresEtox) ConsumptionConc(mass float64) float64 {
+	remaining := mass
+	ppp := 0.
+	taken := 0.
+	for i := len(p.Mass) - 1; i >= 0 && remaining > 0; i-- {
+		m := min(p.Mass[i], remaining)
+		ppp += m * p.Conc[i]
+		taken += m
+		remaining -= m
+	}
+	if taken <= 0 {
+		return 0
+	}
+	return ppp / mass
+}
+
+// Consume removes the given amount of pollen oldest first and returns the amount of PPP removed with it.
+func (p *PollenStoresEtox) Consume(mass float64) (ppp float64) {
+	remaining := mass
+	for i := len(p.Mass) - 1; i >= 0 && remaining > 0; i-- {
+		m := min(p.Mass[i], remaining)
+		ppp += m * p.Conc[i]
+		p.Mass[i] -= m
+		remaining -= m
+		if p.Mass[i] <= 0 {
+			p.Mass[i] = 0
+			p.Conc[i] = 0
+		}
+	}
+	return
+}
+
+// Split removes the given fraction of the pollen in each compartment, e.g. for a colony split.
+// Returns the amount of PPP removed.
+func (p *PollenStoresEtox) Split(fraction float64) (ppp float64) {
+	_, ppp = p.Total()
+	ppp *= fraction
+	for i := range p.Mass {
+		p.Mass[i] *= 1 - fraction
+	}
+	return
+}
+
+// Shift ages all compartments by one day, pooling the two oldest ones.
+func (p *PollenStoresEtox) Shift() {
+	last := len(p.Mass) - 1
+	if last == 0 {
+		return
+	}
+	if m := p.Mass[last] + p.Mass[last-1]; m > 0 {
+		p.Conc[last] = (p.Conc[last]*p.Mass[last] + p.Conc[last-1]*p.Mass[last-1]) / m
+	} else {
+		p.Conc[last] = 0
+	}
+	p.Mass[last] += p.Mass[last-1]
+	copy(p.Mass[1:last], p.Mass[:last-1])
+	copy(p.Conc[1:last], p.Conc[:last-1])
+	p.Mass[0] = 0
+	p.Conc[0] = 0
+}
+
+// Total returns the total amount of pollen [g] and PPP [mug] in the stores.
+func (p *PollenStoresEtox) Total() (mass float64, ppp float64) {
+	massSum, pppSum := util.CompensatedSum{}, util.CompensatedSum{}
+	for i, m := range p.Mass {
+		massSum.Add(m)
+		pppSum.Add(m * p.Conc[i])
+	}
+	return massSum.Value(), pppSum.Value()
+}
+
 // PPPFate tracks the total amount of PPP that flows into the respective PPP-sinks and was used to create
-// PPP mass balances (see examples/beecs_ecotox)
+// PPP mass balances (see examples/beecs_ecotox). The mass balance is checked in every tick by sys.PPPMassBalance.
 type PPPFate struct {
 	TotalPPPforaged      float64 // Total amount of PPP foraged by all foragers; used to create a mass balance
 	PPPhoneyStores       float64 // Total amount of PPP that ends up in honey stores after being foraged
@@ -61,4 +438,93 @@ type PPPFate struct {
 	PPPlarvae         float64 // amount of PPP taken in by larvae
 	PPPdrones         float64 // amount of PPP taken in by drones
 	PPPdlarvae        float64 // amount of PPP taken in by drone larvae
+
+	PPPhoneyDegraded  float64 // amount of PPP degraded in the honey stores
+	PPPpollenDegraded float64 // amount of PPP degraded in the pollen stores
+	PPPstoresLost     float64 // amount of PPP removed from the stores together with honey or pollen that was not eaten, e.g. by overflow of the honey store
+	PPPpollenExcess   float64 // amount of PPP eaten beyond the content of the single pollen store on days it runs empty, if PollenEmptyfix is false; an input besides the PPP foraged
+
+	PPPdissipatedHSU    float64 // amount of PPP lost to the second honey stomach uptake when unloading nectar, if HSUfix is false
+	PPPdissipatedNurses float64 // amount of PPP filtered from larval food by the nursebee factors, if Nursebeefix is false
+
+	PPPwaterForaged    float64 // amount of PPP brought into the hive with water; part of TotalPPPforaged
+	PPPwaterEvaporated float64 // amount of PPP in water used for cooling or not used for dilution, left behind on the combs when the water evaporates
+
+	PPPwaxInput    float64 // amount of PPP in the comb wax at the start of the run and in new foundation wax; an input besides the PPP foraged
+	PPPwaxToHoney  float64 // net amount of PPP that moved from the comb wax into the honey stores
+	PPPwaxToPollen float64 // net amount of PPP that moved from the comb wax into the pollen stores
+	PPPwaxToLarvae float64 // amount of PPP taken up by larvae from the comb wax; part of PPPlarvae and PPPdlarvae
+	PPPwaxDegraded float64 // amount of PPP degraded in the comb wax
+	PPPwaxReplaced float64 // amount of PPP removed with old comb wax during comb replacement
+
+	PPPtreatmentInput    float64 // amount of PPP released by in-hive treatments; an input besides the PPP foraged
+	PPPtreatmentToHoney  float64 // amount of PPP released by in-hive treatments into the honey stores
+	PPPtreatmentToWax    float64 // amount of PPP released by in-hive treatments into the comb wax
+	PPPtreatmentContact  float64 // amount of PPP picked up by adult bees from the residue of in-hive treatments by contact
+	PPPtreatmentDegraded float64 // amount of PPP degraded or vented from the residue of in-hive treatments
+
+	PPPswarm      float64 // amount of PPP that left the hive with the honey taken along by swarms
+	PPPmanagement float64 // amount of PPP removed from the hive by the beekeeper, with harvested honey and the stores of colony splits
+
+	PPPpatchDegraded float64 // amount of PPP degraded in the standing nectar and pollen of patches; not part of the PPP foraged
+	PPPpatchCleared  float64 // amount of PPP in the standing nectar and pollen of patches when residues are removed at the end of the exposure period; not part of the PPP foraged
+}
+
+// MassBalanceEntry is the amount of PPP in one sink of the PPP mass balance.
+type MassBalanceEntry struct {
+	Sink string  // Name of the sink.
+	PPP  float64 // Amount of PPP [µg].
+}
+
+// Input returns the total PPP that entered the hive so far: the PPP foraged, put into the comb wax,
+// released by in-hive treatments and eaten beyond the content of an empty pollen store.
+func (f *PPPFate) Input() float64 {
+	sum := util.CompensatedSum{}
+	sum.Add(f.TotalPPPforaged)
+	sum.Add(f.PPPwaxInput)
+	sum.Add(f.PPPtreatmentInput)
+	sum.Add(f.PPPpollenExcess)
+	return sum.Value()
+}
+
+// MassBalance splits the total PPP input (see [PPPFate.Input]) into the sinks it ended up in,
+// including the PPP still in the stores. The last entry is the remainder that is not
+// attributed to any sink, which should be close to zero.
+func (f *PPPFate) MassBalance(stores *StoragesEtox) []MassBalanceEntry {
+	entries := []MassBalanceEntry{
+		{"PPPforagersImmediate", f.PPPforagersImmediate},
+		{"ForagerDiedInFlight", f.ForagerDiedInFlight},
+		{"PPPforagersinHive", f.PPPforagersinHive},
+		{"PPPIHbees", f.PPPIHbees},
+		{"PPPNurses", f.PPPNurses},
+		{"PPPlarvae", f.PPPlarvae},
+		{"PPPdrones", f.PPPdrones},
+		{"PPPdlarvae", f.PPPdlarvae},
+		{"PPPhoneyDegraded", f.PPPhoneyDegraded},
+		{"PPPpollenDegraded", f.PPPpollenDegraded},
+		{"PPPstoresLost", f.PPPstoresLost},
+		{"PPPdissipatedHSU", f.PPPdissipatedHSU},
+		{"PPPdissipatedNurses", f.PPPdissipatedNurses},
+		{"PPPwaterEvaporated", f.PPPwaterEvaporated},
+		{"PPPwaterTotal", stores.PPPwater},
+		{"PPPwaxDegraded", f.PPPwaxDegraded},
+		{"PPPwaxReplaced", f.PPPwaxReplaced},
+		{"PPPwaxTotal", stores.PPPwax},
+		{"PPPtreatmentContact", f.PPPtreatmentContact},
+		{"PPPtreatmentDegraded", f.PPPtreatmentDegraded},
+		{"PPPtreatmentTotal", stores.PPPtreatment},
+		{"PPPswarm", f.PPPswarm},
+		{"PPPmanagement", f.PPPmanagement},
+		{"PPPhoneyTotal", stores.PPPhoneyTotal},
+		{"PPPpollenTotal", stores.PPPpollenTotal},
+	}
+	sum := util.CompensatedSum{}
+	sum.Add(f.TotalPPPforaged)
+	sum.Add(f.PPPwaxInput)
+	sum.Add(f.PPPtreatmentInput)
+	sum.Add(f.PPPpollenExcess)
+	for _, e := range entries {
+		sum.Add(-e.PPP)
+	}
+	return append(entries, MassBalanceEntry{"Unaccounted", sum.Value()})
 }
@@ -0,0 +1,235 @@
+package globals_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPollenStores(days int) globals.PollenStoresEtox {
+	return globals.PollenStoresEtox{
+		Mass: make([]float64, days),
+		Conc: make([]float64, days),
+	}
+}
+
+func TestPollenStoresConsume(t *testing.T) {
+	p := newPollenStores(3)
+	p.AddOld(10, 1)
+	p.Add(5, 50) // 10 µg/g
+
+	// oldest first, without removing the pollen
+	assert.InDelta(t, 1, p.ConsumptionConc(8), 1e-12)
+	assert.InDelta(t, (10*1+5*10)/20., p.ConsumptionConc(20), 1e-12) // missing pollen is free of PPP
+
+	ppp := p.Consume(12)
+	assert.InDelta(t, 10*1+2*10, ppp, 1e-12)
+	assert.Equal(t, []float64{3, 0, 0}, p.Mass)
+	assert.Equal(t, []float64{10, 0, 0}, p.Conc) // emptied compartments are cleared
+
+	mass, total := p.Total()
+	assert.InDelta(t, 3, mass, 1e-12)
+	assert.InDelta(t, 30, total, 1e-12)
+
+	ppp = p.Consume(10)
+	assert.InDelta(t, 30, ppp, 1e-12)
+	mass, total = p.Total()
+	assert.Equal(t, 0.0, mass)
+	assert.Equal(t, 0.0, total)
+}
+
+func TestPollenStoresShift(t *testing.T) {
+	p := newPollenStores(3)
+	p.AddOld(4, 1)
+	p.Add(2, 20)
+	p.Shift()
+	assert.Equal(t, []float64{0, 2, 4}, p.Mass)
+	assert.Equal(t, []float64{0, 10, 1}, p.Conc)
+
+	p.Add(1, 0)
+	p.Shift()
+	// the two oldest compartments are pooled
+	assert.Equal(t, []float64{0, 1, 6}, p.Mass)
+	assert.InDelta(t, 0, p.Conc[1], 1e-12)
+	assert.InDelta(t, (4*1+2*10)/6., p.Conc[2], 1e-12)
+
+	_, before := p.Total()
+	p.Shift()
+	p.Shift()
+	mass, after := p.Total()
+	assert.InDelta(t, 7, mass, 1e-12)
+	assert.InDelta(t, before, after, 1e-12)
+
+	single := newPollenStores(1)
+	single.Add(1, 1)
+	single.Shift()
+	assert.Equal(t, []float64{1}, single.Mass)
+}
+
+func TestPollenStoresExchange(t *testing.T) {
+	p := newPollenStores(2)
+	p.Exchange(10) // no pollen to hold the PPP
+	_, total := p.Total()
+	assert.Equal(t, 0.0, total)
+
+	p.AddOld(6, 1)
+	p.Add(4, 8)
+	p.Exchange(5) // spread evenly over all pollen
+	assert.InDelta(t, 2.5, p.Conc[0], 1e-12)
+	assert.InDelta(t, 1.5, p.Conc[1], 1e-12)
+	_, total = p.Total()
+	assert.InDelta(t, 6+8+5, total, 1e-12)
+
+	p.Exchange(-9.5) // taken proportionally to the PPP in each compartment
+	assert.InDelta(t, 1.25, p.Conc[0], 1e-12)
+	assert.InDelta(t, 0.75, p.Conc[1], 1e-12)
+
+	p.Exchange(-100) // never below zero
+	_, total = p.Total()
+	assert.Equal(t, 0.0, total)
+}
+
+func newHoneyStores(days int) globals.HoneyStoresEtox {
+	return globals.HoneyStoresEtox{
+		Energy: make([]float64, days),
+		Conc:   make([]float64, days),
+	}
+}
+
+func TestHoneyStoresFeed(t *testing.T) {
+	h := newHoneyStores(3)
+	h.CappedEnergy, h.CappedConc = 100, 1
+	h.Energy = []float64{10, 20, 30}
+	h.Conc = []float64{3, 2, 4}
+
+	// newest uncapped honey first
+	ppp, capped := h.Feed(15, false)
+	assert.InDelta(t, 10*3+5*2, ppp, 1e-12)
+	assert.Equal(t, 0.0, capped)
+	assert.Equal(t, []float64{0, 15, 30}, h.Energy)
+
+	// oldest uncapped honey first
+	ppp, capped = h.Feed(20, true)
+	assert.InDelta(t, 20*4, ppp, 1e-12)
+	assert.Equal(t, 0.0, capped)
+	assert.Equal(t, []float64{0, 15, 10}, h.Energy)
+	assert.Equal(t, 2.0, h.NextConc(false))
+	assert.Equal(t, 4.0, h.NextConc(true))
+
+	// capped honey last
+	ppp, capped = h.Feed(45, false)
+	assert.InDelta(t, 15*2+10*4+20*1, ppp, 1e-12)
+	assert.Equal(t, 20.0, capped)
+	assert.Equal(t, 80.0, h.CappedEnergy)
+	assert.Equal(t, 1.0, h.NextConc(false))
+}
+
+func TestHoneyStoresDeficit(t *testing.T) {
+	h := newHoneyStores(2)
+	h.Add(10, 20)
+
+	// eating more than there is leaves a deficit
+	ppp, capped := h.Feed(15, false)
+	assert.InDelta(t, 20, ppp, 1e-12)
+	assert.Equal(t, 0.0, capped)
+	assert.Equal(t, 5.0, h.Deficit)
+	assert.Equal(t, -5.0, h.Total())
+
+	// nectar pays back the deficit first; without honey to hold it, its PPP is lost
+	lost := h.Add(3, 6)
+	assert.Equal(t, 6.0, lost)
+	assert.Equal(t, 2.0, h.Deficit)
+
+	// with capped honey, the PPP of the nectar paying back the deficit goes there
+	h.CappedEnergy = 10
+	lost = h.Add(2, 5)
+	assert.Equal(t, 0.0, lost)
+	assert.Equal(t, 0.0, h.Deficit)
+	assert.InDelta(t, 0.5, h.CappedConc, 1e-12)
+
+	// the remainder after paying back is stored in today's compartment
+	h.Deficit = 1
+	lost = h.Add(5, 8)
+	assert.Equal(t, 0.0, lost)
+	assert.Equal(t, 4.0, h.Energy[0])
+	assert.InDelta(t, 2, h.Conc[0], 1e-12)
+}
+
+func TestHoneyStoresCapping(t *testing.T) {
+	h := newHoneyStores(2)
+	h.Add(10, 10)
+	h.Shift(0)
+	assert.Equal(t, []float64{0, 10}, h.Energy)
+	assert.Equal(t, 0.0, h.CappedEnergy)
+
+	h.Add(20, 60)
+	h.Shift(0.5)
+	// the oldest compartment gets capped, half of the younger one as well
+	assert.Equal(t, []float64{0, 10}, h.Energy)
+	assert.Equal(t, []float64{0, 3}, h.Conc)
+	assert.Equal(t, 20.0, h.CappedEnergy)
+	assert.InDelta(t, (10*1+10*3)/20., h.CappedConc, 1e-12)
+	assert.InDelta(t, 70, h.TotalPPP(), 1e-12)
+}
+
+func TestHoneyStoresReconcile(t *testing.T) {
+	h := newHoneyStores(2)
+	h.Add(10, 20)
+	h.Shift(0)
+	h.Add(30, 30)
+
+	before := h.Reconcile(20)
+	assert.Equal(t, 40.0, before)
+	assert.InDelta(t, 20, h.Total(), 1e-12)
+	assert.InDelta(t, 50, h.TotalPPP(), 1e-12) // the PPP is kept
+	assert.InDelta(t, 2, h.Conc[0], 1e-12)
+
+	// with a deficit, only the honey present gets scaled
+	h.Feed(25, false)
+	assert.InDelta(t, -5, h.Total(), 1e-12)
+	h.Reconcile(10)
+	assert.InDelta(t, 10, h.Total(), 1e-12)
+	assert.Equal(t, 0.0, h.Deficit)
+	assert.Equal(t, 0.0, h.TotalPPP()) // all PPP was eaten
+
+	// the deficit is kept while the honey present is scaled
+	h.Clear()
+	h.CappedEnergy, h.CappedConc, h.Deficit = 5, 2, 5
+	h.Reconcile(4)
+	assert.InDelta(t, 4, h.Total(), 1e-12)
+	assert.InDelta(t, 9, h.CappedEnergy, 1e-12)
+	assert.Equal(t, 5.0, h.Deficit)
+	assert.InDelta(t, 10, h.TotalPPP(), 1e-12)
+
+	// without honey present, the reconciled energy becomes capped honey
+	h.Clear()
+	h.Deficit = 3
+	h.Reconcile(6)
+	assert.Equal(t, 6.0, h.CappedEnergy)
+	assert.Equal(t, 0.0, h.Deficit)
+	h.Reconcile(-2)
+	assert.Equal(t, 0.0, h.CappedEnergy)
+	assert.Equal(t, 2.0, h.Deficit)
+}
+
+func TestHoneyStoresExchange(t *testing.T) {
+	h := newHoneyStores(2)
+	h.Exchange(10) // no honey to hold the PPP
+	assert.Equal(t, 0.0, h.TotalPPP())
+
+	h.Add(10, 10)
+	h.CappedEnergy, h.CappedConc = 30, 0
+
+	h.Exchange(8) // spread evenly over all honey
+	assert.InDelta(t, 1.2, h.Conc[0], 1e-12)
+	assert.InDelta(t, 0.2, h.CappedConc, 1e-12)
+	assert.InDelta(t, 18, h.TotalPPP(), 1e-12)
+
+	h.Exchange(-9) // taken proportionally to the PPP in each compartment
+	assert.InDelta(t, 0.6, h.Conc[0], 1e-12)
+	assert.InDelta(t, 0.1, h.CappedConc, 1e-12)
+
+	h.Exchange(-100) // never below zero
+	assert.Equal(t, 0.0, h.TotalPPP())
+}
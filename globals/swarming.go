@@ -0,0 +1,11 @@
+package globals
+
+// Swarming contains the swarming history of the colony, see [github.com/fzeitner/Nursebeecs-master-thesis/params.Swarming].
+type Swarming struct {
+	Swarms    int   // Number of swarms since the start of the simulation.
+	LastSwarm int64 // Tick of the last swarm; -1 if the colony did not swarm yet.
+
+	Bees  int     // Workers that left with a swarm today, in-hive bees and foragers.
+	Honey float64 // Honey taken along by a swarm today [kJ].
+	Mites int     // Phoretic mites that left with a swarm today.
+}
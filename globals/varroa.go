@@ -0,0 +1,67 @@
+package globals
+
+// Mites contains the Varroa mite population. Only used by the mite module, see [github.com/fzeitner/Nursebeecs-master-thesis/params.Varroa].
+type Mites struct {
+	PhoreticHealthy  int // Phoretic mites free of virus.
+	PhoreticInfected int // Phoretic mites carrying the virus.
+
+	WorkerCellsHealthy  []int // Mites free of virus reproducing in capped worker cells, per day since capping.
+	WorkerCellsInfected []int // Mites carrying the virus reproducing in capped worker cells, per day since capping.
+	DroneCellsHealthy   []int // Mites free of virus reproducing in capped drone cells, per day since capping.
+	DroneCellsInfected  []int // Mites carrying the virus reproducing in capped drone cells, per day since capping.
+
+	Invaded int // Mites that invaded brood cells today.
+	Emerged int // Mites released from emerging brood today, including their offspring.
+	Died    int // Phoretic mites that died today from background mortality.
+	Treated int // Mites killed by treatments today.
+}
+
+// Phoretic returns the number of phoretic mites.
+func (m *Mites) Phoretic() int {
+	return m.PhoreticHealthy + m.PhoreticInfected
+}
+
+// InCells returns the number of mites in capped brood cells.
+func (m *Mites) InCells() int {
+	return sum(m.WorkerCellsHealthy) + sum(m.WorkerCellsInfected) + sum(m.DroneCellsHealthy) + sum(m.DroneCellsInfected)
+}
+
+// Infected returns the number of mites carrying the virus, phoretic and in cells.
+func (m *Mites) Infected() int {
+	return m.PhoreticInfected + sum(m.WorkerCellsInfected) + sum(m.DroneCellsInfected)
+}
+
+// Total returns the total number of mites, phoretic and in cells.
+func (m *Mites) Total() int {
+	return m.Phoretic() + m.InCells()
+}
+
+// InfectedCohorts contains the number of virus-infected bees in the brood and in-hive cohorts.
+// Only used by the mite module. Infected forager squadrons carry a [github.com/fzeitner/Nursebeecs-master-thesis/comp.Virus] component.
+type InfectedCohorts struct {
+	WorkerPupae []int // Infected worker pupae per day since capping.
+	DronePupae  []int // Infected drone pupae per day since capping.
+
+	WorkersAsPupa  []int // In-hive workers infected as pupae, per day of age.
+	WorkersAsAdult []int // In-hive workers infected as adults, per day of age.
+	DronesAsPupa   []int // Drones infected as pupae, per day of age.
+	DronesAsAdult  []int // Drones infected as adults, per day of age.
+}
+
+// Pupae returns the number of infected pupae.
+func (c *InfectedCohorts) Pupae() int {
+	return sum(c.WorkerPupae) + sum(c.DronePupae)
+}
+
+// InHive returns the number of infected in-hive workers and drones.
+func (c *InfectedCohorts) InHive() int {
+	return sum(c.WorkersAsPupa) + sum(c.WorkersAsAdult) + sum(c.DronesAsPupa) + sum(c.DronesAsAdult)
+}
+
+func sum(s []int) int {
+	total := 0
+	for _, v := range s {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,65 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/model"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark-tools/app"
+)
+
+// variant selects one of the four model setups.
+type variant int
+
+const (
+	beecs variant = iota
+	beecsEtox
+	nbeecs
+	nbeecsEtox
+)
+
+// variants lists all model setups, for tests that check a process in each of them.
+var variants = []variant{beecs, beecsEtox, nbeecs, nbeecsEtox}
+
+// etox returns whether the variant includes the ecotoxicological extension.
+func (v variant) etox() bool {
+	return v == beecsEtox || v == nbeecsEtox
+}
+
+// testParams bundles the parameters of all model setups.
+// Variants ignore the parameters of extensions they do not include.
+type testParams struct {
+	base  params.DefaultParams
+	etox  params.DefaultParamsEtox
+	nurse params.DefaultParamsNursebeecs
+}
+
+// newTestApp creates an app of the given variant from the default parameters, simulating one year with seed 1.
+// Argument mutate adjusts the parameters before the app is created and may be nil.
+func newTestApp(t *testing.T, v variant, mutate func(p *testParams)) *app.App {
+	t.Helper()
+
+	p := testParams{
+		base:  params.Default(),
+		etox:  params.DefaultEtox(),
+		nurse: params.DefaultNursebeecs(),
+	}
+	p.base.Termination.MaxTicks = 365
+	p.base.RandomSeed.Seed = 1
+	if mutate != nil {
+		mutate(&p)
+	}
+
+	switch v {
+	case beecs:
+		return model.Default(&p.base, nil)
+	case beecsEtox:
+		return model.DefaultEtox(&p.base, &p.etox, nil)
+	case nbeecs:
+		return model.DefaultNbeecs(&p.base, &p.nurse, nil)
+	case nbeecsEtox:
+		return model.DefaultNbeecsEtox(&p.base, &p.etox, &p.nurse, nil)
+	}
+	t.Fatalf("unknown model variant %d", v)
+	return nil
+}
@@ -24,6 +24,7 @@ func Default(p params.Params, app *app.App) *app.App {
 	app.AddSystem(&sys.InitPopulation{})
 	app.AddSystem(&sys.InitPatchesList{})
 	app.AddSystem(&sys.InitForagingPeriod{})
+	app.AddSystem(&sys.InitMites{})
 
 	// Sub-models
 	app.AddSystem(&sys.CalcAff{})
@@ -32,6 +33,9 @@ func Default(p params.Params, app *app.App) *app.App {
 
 	app.AddSystem(&sys.MortalityCohorts{})
 	app.AddSystem(&sys.AgeCohorts{})
+	app.AddSystem(&sys.QueenDynamics{}) // queen ageing, loss and replacement
+	app.AddSystem(&sys.Swarming{})      // reproductive swarming, only if enabled
+	app.AddSystem(&sys.Management{})    // beekeeper management events, if any are scheduled
 	app.AddSystem(&sys.EggLaying{})
 	app.AddSystem(&sys.TransitionForagers{})
 
@@ -39,6 +43,7 @@ func Default(p params.Params, app *app.App) *app.App {
 	app.AddSystem(&sys.BroodCare{})
 
 	app.AddSystem(&sys.NewCohorts{})      // here the new cohorts get initialized now
+	app.AddSystem(&sys.MiteProc{})        // Varroa mites and virus transmission, only if enabled
 	app.AddSystem(&sys.CountPopulation{}) // added here to reflect position in original model (miteproc), necessary to capture new Cohorts for foraging
 
 	app.AddSystem(&sys.Foraging{}) // also initializes foragers now
@@ -77,6 +82,9 @@ func initializeModel(p params.Params, a *app.App) *app.App {
 
 	p.Apply(&a.World)
 
+	streams := globals.NewRandStreams(ecs.GetResource[params.RandomSeed](&a.World).Seed)
+	ecs.AddResource(&a.World, &streams)
+
 	factory := globals.NewForagerFactory(&a.World)
 	ecs.AddResource(&a.World, &factory)
 
@@ -25,18 +25,23 @@ func DefaultEtox(p params.Params, pe params.ParamsEtox, app *app.App) *app.App {
 	app.AddSystem(&sys.InitPopulation{})
 	app.AddSystem(&sys.InitPatchesList{})
 	app.AddSystem(&sys.InitForagingPeriod{})
+	app.AddSystem(&sys.InitMites{})
 	app.AddSystem(&sys.InitEtox{}) // inits all the changes necessary for the beecs_ecotox submodels
 
 	// Sub-models
 	app.AddSystem(&sys.CalcAff{})
 	app.AddSystem(&sys.CalcForagingPeriod{})
-	//app.AddSystem(&sys.CalcWaterForagingPeriod{}) // might as well be disabled atm because no function actually uses water data as of yet, because water foraging seems irrelevant/untested in netlogo as well
-	app.AddSystem(&sys.ReplenishPatches{}) // unchanged to beecs
-	app.AddSystem(&sys.PPPApplication{})   // introduced calculation of PPP exposure at patches analogous to BEEHAVE_ecotox
+	app.AddSystem(&sys.CalcWaterForagingPeriod{}) // daily water need for cooling, only used with water foraging
+	app.AddSystem(&sys.ReplenishPatches{})        // unchanged to beecs
+	app.AddSystem(&sys.PPPApplication{})          // introduced calculation of PPP exposure at patches analogous to BEEHAVE_ecotox
 
 	app.AddSystem(&sys.MortalityCohorts{})     // unchanged to beecs
 	app.AddSystem(&sys.MortalityCohortsEtox{}) // introduced ETOXMortality as an additional process for all cohorts
+	app.AddSystem(&sys.DevelopmentEtox{})      // sublethal effects of the larval dose on development and longevity; shifts exposure markers along with the cohorts
 	app.AddSystem(&sys.AgeCohorts{})           // unchanged to beecs
+	app.AddSystem(&sys.QueenDynamics{})        // queen ageing, loss and replacement, including PPP effects on the queen
+	app.AddSystem(&sys.Swarming{})             // reproductive swarming, only if enabled
+	app.AddSystem(&sys.Management{})           // beekeeper management events, if any are scheduled
 	app.AddSystem(&sys.EggLaying{})            // unchanged to beecs
 	app.AddSystem(&sys.TransitionForagers{})   // unchanged to beecs
 
@@ -44,16 +49,21 @@ func DefaultEtox(p params.Params, pe params.ParamsEtox, app *app.App) *app.App {
 	app.AddSystem(&sys.BroodCare{})       // Moved after the first countingproc to resemble the original model further, as counting twice is inevitable because of ETOXmortality processes.
 
 	app.AddSystem(&sys.NewCohorts{})
+	app.AddSystem(&sys.MiteProc{})        // Varroa mites and virus transmission, only if enabled
 	app.AddSystem(&sys.CountPopulation{}) // added here to reflect position in original model (miteproc), necessary to capture new Cohorts for foraging
 
+	app.AddSystem(&sys.WaterForagingEtox{})     // water foragers collect water for cooling and honey dilution in the morning, before the regular foraging
 	app.AddSystem(&sys.ForagingEtox{})          // introduced the uptake of PPP into foragers and the hive through contaminated honey/pollen; would be far too tedious to decouple this from the normal foraging submodel
 	app.AddSystem(&sys.MortalityForagers{})     // unchanged to beecs
 	app.AddSystem(&sys.MortalityForagersEtox{}) // introduced ETOXMortality as an additional process for foragers after normal foraging mortality, analogous to BEEHAVE_ecotox
 
-	app.AddSystem(&sys.CountPopulation{})   // necessary here because of food comsumption in the next steps
-	app.AddSystem(&sys.PollenConsumption{}) // unchanged to beecs
-	app.AddSystem(&sys.HoneyConsumption{})  // unchanged to beecs
-	app.AddSystem(&sys.EtoxStorages{})      // regulates in-hive exposure and fate of PPP and the newly introduced honey compartiments
+	app.AddSystem(&sys.CountPopulation{})     // necessary here because of food comsumption in the next steps
+	app.AddSystem(&sys.PollenConsumption{})   // unchanged to beecs
+	app.AddSystem(&sys.HoneyConsumption{})    // unchanged to beecs
+	app.AddSystem(&sys.EtoxStorages{})        // regulates in-hive exposure and fate of PPP and the newly introduced honey compartiments
+	app.AddSystem(&sys.InHiveTreatmentEtox{}) // veterinary treatments applied by the beekeeper inside the hive
+	app.AddSystem(&sys.WaxEtox{})             // exchange of PPP between the comb wax, the stores and the larvae
+	app.AddSystem(&sys.PPPMassBalance{})      // checks that all PPP foraged is accounted for in the stores, the bees or a sink
 
 	app.AddSystem(&sys.FixedTermination{})
 
@@ -85,6 +95,9 @@ func initializeModelEtox(p params.Params, pe params.ParamsEtox, a *app.App) *app
 	p.Apply(&a.World)
 	pe.Apply(&a.World)
 
+	streams := globals.NewRandStreams(ecs.GetResource[params.RandomSeed](&a.World).Seed)
+	ecs.AddResource(&a.World, &streams)
+
 	factory := globals.NewForagerFactory(&a.World)
 	ecs.AddResource(&a.World, &factory)
 
@@ -24,6 +24,7 @@ func DefaultNbeecs(p params.Params, pn params.ParamsNursebeecs, app *app.App) *a
 	app.AddSystem(&sys.InitPopulation{})
 	app.AddSystem(&sys.InitPatchesList{})
 	app.AddSystem(&sys.InitForagingPeriod{})
+	app.AddSystem(&sys.InitMites{})
 	app.AddSystem(&sys.InitNursebeecs{}) // inits all the changes necessary for the etox and nursing module
 
 	// Sub-models
@@ -33,6 +34,9 @@ func DefaultNbeecs(p params.Params, pn params.ParamsNursebeecs, app *app.App) *a
 
 	app.AddSystem(&sys.MortalityCohorts{})
 	app.AddSystem(&sys.AgeCohorts{})
+	app.AddSystem(&sys.QueenDynamics{}) // queen ageing, loss and replacement
+	app.AddSystem(&sys.Swarming{})      // reproductive swarming, only if enabled
+	app.AddSystem(&sys.Management{})    // beekeeper management events, if any are scheduled
 	app.AddSystem(&sys.EggLaying{})
 	app.AddSystem(&sys.TransitionForagers{})
 
@@ -41,6 +45,7 @@ func DefaultNbeecs(p params.Params, pn params.ParamsNursebeecs, app *app.App) *a
 	app.AddSystem(&sys.Nbroodcare{})      // new nurse based brood care process (if turned on)
 
 	app.AddSystem(&sys.NewCohorts{})      // here the new cohorts get initialized now
+	app.AddSystem(&sys.MiteProc{})        // Varroa mites and virus transmission, only if enabled
 	app.AddSystem(&sys.CountPopulation{}) // added here to reflect position in original model (miteproc), necessary to capture new Cohorts for foraging
 
 	app.AddSystem(&sys.Foraging{})
@@ -79,6 +84,9 @@ func initializeModelNbeecs(p params.Params, pn params.ParamsNursebeecs, a *app.A
 	p.Apply(&a.World)
 	pn.Apply(&a.World)
 
+	streams := globals.NewRandStreams(ecs.GetResource[params.RandomSeed](&a.World).Seed)
+	ecs.AddResource(&a.World, &streams)
+
 	factory := globals.NewForagerFactory(&a.World)
 	ecs.AddResource(&a.World, &factory)
 
@@ -24,17 +24,23 @@ func DefaultNbeecsEtox(p params.Params, pe params.ParamsEtox, pn params.ParamsNu
 	app.AddSystem(&sys.InitPopulation{})
 	app.AddSystem(&sys.InitPatchesList{})
 	app.AddSystem(&sys.InitForagingPeriod{})
+	app.AddSystem(&sys.InitMites{})
 	app.AddSystem(&sys.InitEtoxNursebeecs{}) // inits all the changes necessary for the nursebeecs_ecotox submodels
 
 	// Sub-models
 	app.AddSystem(&sys.CalcAffNbeecs{})
 	app.AddSystem(&sys.CalcForagingPeriod{})
-	app.AddSystem(&sys.ReplenishPatches{}) // unchanged to beecs
-	app.AddSystem(&sys.PPPApplication{})   // introduced PPP exposure at patches
+	app.AddSystem(&sys.CalcWaterForagingPeriod{}) // daily water need for cooling, only used with water foraging
+	app.AddSystem(&sys.ReplenishPatches{})        // unchanged to beecs
+	app.AddSystem(&sys.PPPApplication{})          // introduced PPP exposure at patches
 
 	app.AddSystem(&sys.MortalityCohorts{})     // unchanged to beecs
 	app.AddSystem(&sys.MortalityCohortsEtox{}) // introduced ETOXMortality as an additional process for all cohorts
+	app.AddSystem(&sys.DevelopmentEtox{})      // sublethal effects of the larval dose on development and longevity; shifts exposure markers along with the cohorts
 	app.AddSystem(&sys.AgeCohorts{})           // unchanged to beecs
+	app.AddSystem(&sys.QueenDynamics{})        // queen ageing, loss and replacement, including PPP effects on the queen
+	app.AddSystem(&sys.Swarming{})             // reproductive swarming, only if enabled
+	app.AddSystem(&sys.Management{})           // beekeeper management events, if any are scheduled
 	app.AddSystem(&sys.EggLaying{})            // unchanged to beecs
 	app.AddSystem(&sys.TransitionForagers{})   // unchanged to beecs
 
@@ -43,8 +49,10 @@ func DefaultNbeecsEtox(p params.Params, pe params.ParamsEtox, pn params.ParamsNu
 	app.AddSystem(&sys.Nbroodcare{})      // new nurse based brood care process (if turned on)
 
 	app.AddSystem(&sys.NewCohorts{})      // unchanged to beecs
+	app.AddSystem(&sys.MiteProc{})        // Varroa mites and virus transmission, only if enabled
 	app.AddSystem(&sys.CountPopulation{}) // added here to reflect position in original model (miteproc), necessary to capture new Cohorts for foraging
 
+	app.AddSystem(&sys.WaterForagingEtox{})     // water foragers collect water for cooling and honey dilution in the morning, before the regular foraging
 	app.AddSystem(&sys.ForagingEtox{})          // introduced the uptake of PPP into foragers and the hive through contaminated honey/pollen, very tedious to decouple from normal foraging process
 	app.AddSystem(&sys.MortalityForagers{})     // unchanged to beecs
 	app.AddSystem(&sys.MortalityForagersEtox{}) // introduced ETOXMortality as an additional process for foragers after normal foraging mortality, analogous to BEEHAVE_ecotox
@@ -52,6 +60,9 @@ func DefaultNbeecsEtox(p params.Params, pe params.ParamsEtox, pn params.ParamsNu
 	app.AddSystem(&sys.CountPopulation{})      // necessary here because of food comsumption in the next steps
 	app.AddSystem(&sys.NurseConsumptionEtox{}) // regulates all consumption of honey and pollen with consideration of nurse bee feeding
 	app.AddSystem(&sys.EtoxStoragesNbeecs{})   // regulates in-hive exposure and fate of PPP for nursebeecs
+	app.AddSystem(&sys.InHiveTreatmentEtox{})  // veterinary treatments applied by the beekeeper inside the hive
+	app.AddSystem(&sys.WaxEtox{})              // exchange of PPP between the comb wax, the stores and the larvae
+	app.AddSystem(&sys.PPPMassBalance{})       // checks that all PPP foraged is accounted for in the stores, the bees or a sink
 
 	app.AddSystem(&sys.FixedTermination{})
 
@@ -84,6 +95,9 @@ func initializeModelNbeecsEtox(p params.Params, pe params.ParamsEtox, pn params.
 	pe.Apply(&a.World)
 	pn.Apply(&a.World)
 
+	streams := globals.NewRandStreams(ecs.GetResource[params.RandomSeed](&a.World).Seed)
+	ecs.AddResource(&a.World, &streams)
+
 	factory := globals.NewForagerFactory(&a.World)
 	ecs.AddResource(&a.World, &factory)
 
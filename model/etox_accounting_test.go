@@ -0,0 +1,204 @@
+package model_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+// multi-year runs used to end in a panic on the honey store comparison
+func TestEtoxAccountingMultiYear(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping multi-year run in short mode")
+	}
+
+	for _, seed := range []int{1, 2, 3} {
+		for _, v := range []variant{beecsEtox, nbeecsEtox} {
+			a := newTestApp(t, v, func(p *testParams) {
+				p.base.Termination.MaxTicks = 3650
+				p.base.RandomSeed.Seed = seed
+				p.etox.PPPApplication.Application = true
+				p.etox.PPPApplication.PPPname = "dimethoate"
+				p.etox.PPPApplication.ExposurePhase = 10
+				p.etox.PPPApplication.DegradationHoney = true
+			})
+			assert.NotPanics(t, func() { a.Run() })
+
+			stores := ecs.GetResource[globals.Stores](&a.World)
+			honey := ecs.GetResource[globals.HoneyStoresEtox](&a.World)
+			events := ecs.GetResource[globals.AccountingEvents](&a.World)
+
+			assert.InDelta(t, stores.Honey, honey.Total(), 1e-6*stores.Honey+1e-9)
+			assert.Nil(t, events.Err())
+		}
+	}
+}
+
+func TestPPPMassBalance(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "massbalance")
+	a := newTestApp(t, beecsEtox, func(p *testParams) {
+		p.etox.PPPApplication.Application = true
+		p.etox.PPPApplication.DegradationHoney = true
+		p.etox.PPPApplication.PollenStoreKinetics.DT50 = 10
+		p.etox.MassBalance.File = file
+	})
+	a.Run()
+
+	fate := ecs.GetResource[globals.PPPFate](&a.World)
+	stores := ecs.GetResource[globals.StoragesEtox](&a.World)
+	events := ecs.GetResource[globals.AccountingEvents](&a.World)
+
+	assert.Greater(t, fate.TotalPPPforaged, 0.0)
+	assert.Greater(t, fate.PPPhoneyDegraded, 0.0)
+	assert.Greater(t, fate.PPPpollenDegraded, 0.0)
+
+	balance := fate.MassBalance(stores)
+	assert.Equal(t, "Unaccounted", balance[len(balance)-1].Sink)
+	assert.InDelta(t, 0.0, balance[len(balance)-1].PPP, 1e-6*fate.TotalPPPforaged)
+	assert.Nil(t, events.Err())
+
+	csv, err := os.ReadFile(file + ".csv")
+	assert.Nil(t, err)
+	assert.Contains(t, string(csv), "PPPhoneyDegraded;")
+
+	md, err := os.ReadFile(file + ".md")
+	assert.Nil(t, err)
+	assert.Contains(t, string(md), "| TotalPPPforaged |")
+}
+
+func TestPPPMassBalanceDissipation(t *testing.T) {
+	a := newTestApp(t, beecsEtox, func(p *testParams) {
+		p.base.RandomSeed.Seed = 2
+		p.etox.PPPApplication.Application = true
+		p.etox.PPPApplication.HSUfix = false
+		p.etox.PPPApplication.Nursebeefix = false
+		p.etox.PPPToxicity.NursebeesPollen = 0.25
+	})
+	a.Run()
+
+	fate := ecs.GetResource[globals.PPPFate](&a.World)
+	stores := ecs.GetResource[globals.StoragesEtox](&a.World)
+	events := ecs.GetResource[globals.AccountingEvents](&a.World)
+
+	assert.Greater(t, fate.PPPdissipatedHSU, 0.0)
+	assert.Greater(t, fate.PPPdissipatedNurses, 0.0)
+	assert.Greater(t, fate.PPPpatchDegraded, 0.0)
+	assert.Greater(t, fate.PPPpatchCleared, 0.0)
+
+	balance := fate.MassBalance(stores)
+	assert.InDelta(t, 0.0, balance[len(balance)-1].PPP, 1e-6*fate.TotalPPPforaged)
+	assert.Nil(t, events.Err())
+}
+
+// the PPP mass balance must detect imbalances far below the 0.5 units floor of the store checks
+func TestPPPMassBalanceEpsilon(t *testing.T) {
+	a := newTestApp(t, beecsEtox, func(p *testParams) {
+		p.etox.PPPApplication.Application = true
+		p.etox.PPPApplication.AccountingTolerance = 1e-12
+	})
+	a.Initialize()
+	fate := ecs.GetResource[globals.PPPFate](&a.World)
+	events := ecs.GetResource[globals.AccountingEvents](&a.World)
+	for tick := 0; a.Update(); tick++ {
+		if tick == 200 {
+			assert.Greater(t, fate.TotalPPPforaged, 0.0)
+			assert.Nil(t, events.Err())
+			fate.TotalPPPforaged += 0.01
+		}
+	}
+	a.Finalize()
+
+	if assert.NotEmpty(t, events.Events) {
+		assert.Equal(t, "PPP mass balance", events.Events[0].Check)
+		assert.EqualValues(t, 201, events.Events[0].Tick)
+	}
+}
+
+// the age of the store residues must follow new deposits instead of counting from the first exposure
+func TestResidueAge(t *testing.T) {
+	a := newTestApp(t, beecsEtox, func(p *testParams) {
+		p.base.Termination.MaxTicks = 3 * 365
+		p.etox.PPPApplication.Application = true
+		p.etox.PPPApplication.DegradationHoney = true
+	})
+	a.Initialize()
+	stores := ecs.GetResource[globals.StoragesEtox](&a.World)
+	maxHoney, maxPollen := 0.0, 0.0
+	for a.Update() {
+		maxHoney = max(maxHoney, stores.HoneyResidueAge)
+		maxPollen = max(maxPollen, stores.PollenResidueAge)
+	}
+	a.Finalize()
+
+	assert.Greater(t, maxHoney, 0.0)
+	assert.Greater(t, maxPollen, 0.0)
+	assert.Less(t, maxHoney, 365.0)
+	assert.Less(t, maxPollen, 365.0)
+}
+
+// PPP eaten from the single pollen store on the day it runs empty is recorded as an input, unless prevented by PollenEmptyfix
+func TestPollenEmptyfix(t *testing.T) {
+	run := func(fix bool) *globals.PPPFate {
+		a := newTestApp(t, beecsEtox, func(p *testParams) {
+			p.etox.PPPApplication.Application = true
+			p.etox.PPPApplication.PollenEmptyfix = fix
+		})
+		a.Run()
+
+		fate := ecs.GetResource[globals.PPPFate](&a.World)
+		balance := fate.MassBalance(ecs.GetResource[globals.StoragesEtox](&a.World))
+		assert.InDelta(t, 0.0, balance[len(balance)-1].PPP, 1e-6*fate.Input())
+		assert.Nil(t, ecs.GetResource[globals.AccountingEvents](&a.World).Err())
+		return fate
+	}
+
+	assert.Greater(t, run(false).PPPpollenExcess, 0.0)
+	assert.Equal(t, 0.0, run(true).PPPpollenExcess)
+}
+
+// a mass balance report that cannot be written must not abort the finished run
+func TestPPPMassBalanceWriteError(t *testing.T) {
+	blocker := filepath.Join(t.TempDir(), "file")
+	assert.Nil(t, os.WriteFile(blocker, []byte{}, 0666))
+
+	a := newTestApp(t, beecsEtox, func(p *testParams) {
+		p.base.Termination.MaxTicks = 10
+		p.etox.MassBalance.File = filepath.Join(blocker, "massbalance")
+	})
+	assert.NotPanics(t, func() { a.Run() })
+}
+
+// the shares of the mass balance report refer to the total input, including PPP that was not foraged
+func TestPPPMassBalanceShares(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "massbalance")
+	a := newTestApp(t, beecsEtox, func(p *testParams) {
+		p.etox.Wax.WaxCompartment = true
+		p.etox.Wax.InitialResidue = 1000
+		p.etox.MassBalance.File = file
+	})
+	a.Run()
+
+	csv, err := os.ReadFile(file + ".csv")
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(string(csv)), "\n")
+	assert.Equal(t, "Sink;PPP;PercentOfInput", lines[0])
+
+	shares := map[string]float64{}
+	for _, line := range lines[1:] {
+		cols := strings.Split(line, ";")
+		share, err := strconv.ParseFloat(cols[2], 64)
+		assert.Nil(t, err)
+		assert.LessOrEqual(t, share, 100+1e-9)
+		shares[cols[0]] = share
+	}
+	assert.Equal(t, 0.0, shares["TotalPPPforaged"])
+	assert.Greater(t, shares["PPPwaxInput"], 0.0)
+	assert.InDelta(t, 100, shares["PPPwaxInput"]+shares["PPPtreatmentInput"]+shares["PPPpollenExcess"], 1e-9)
+	assert.Greater(t, shares["PPPwaxTotal"], 0.0)
+}
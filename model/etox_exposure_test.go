@@ -0,0 +1,69 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/drift"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/obs"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForagerLifetimeExposure(t *testing.T) {
+	a := newTestApp(t, nbeecsEtox, func(p *testParams) {
+		p.etox.PPPApplication.Application = true
+	})
+	a.Run()
+
+	records := ecs.GetResource[globals.ForagerExposureRecords](&a.World)
+	assert.Greater(t, len(records.Dead), 0)
+
+	o := obs.ForagerExposure{}
+	o.Initialize(&a.World)
+	rows := o.Values(&a.World)
+	assert.Greater(t, len(rows), len(records.Dead))
+
+	exposed := 0
+	for _, row := range rows {
+		assert.Len(t, row, len(o.Header()))
+		cumOral, cumContact, days, peakOral, peakContact := row[3], row[4], row[5], row[6], row[7]
+		assert.GreaterOrEqual(t, cumOral, peakOral)
+		assert.GreaterOrEqual(t, cumContact, peakContact)
+		assert.Equal(t, days > 0, cumOral+cumContact > 0)
+		if days > 0 {
+			exposed++
+		}
+	}
+	assert.Greater(t, exposed, 0)
+}
+
+func TestSprayDrift(t *testing.T) {
+	a := newTestApp(t, beecsEtox, func(p *testParams) {
+		p.base.InitialPatches.Patches[1].DistToField = 10
+		p.etox.PPPApplication.Application = true
+		p.etox.PPPApplication.DriftCurve = drift.BBA
+		p.base.Termination.MaxTicks = p.etox.PPPApplication.AppDay + 1 // directly after the application
+	})
+	a.Run()
+
+	var inField, offField comp.PatchPropertiesEtox
+	filter := ecs.NewFilter2[comp.PatchPropertiesEtox, comp.PatchDistance](&a.World)
+	query := filter.Query()
+	for query.Next() {
+		props, dist := query.Get()
+		if dist.DistToField > 0 {
+			offField = *props
+		} else {
+			inField = *props
+		}
+	}
+
+	fraction := util.BBADrift{}.Fraction(10)
+	assert.Greater(t, inField.PPPcontactDose, 0.0)
+	assert.InDelta(t, fraction, offField.PPPcontactDose/inField.PPPcontactDose, 1e-9)
+	assert.InDelta(t, fraction, offField.PPPconcentrationNectar/inField.PPPconcentrationNectar, 1e-9)
+	assert.InDelta(t, fraction, offField.PPPconcentrationPollen/inField.PPPconcentrationPollen, 1e-9)
+}
@@ -0,0 +1,111 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSublethalForagerEffects(t *testing.T) {
+	run := func(homing, handling bool) (homingFailures int, maxHandling float64, energy float64) {
+		a := newTestApp(t, beecsEtox, func(p *testParams) {
+			p.base.HandlingTime.ConstantHandlingTime = true
+			p.etox.PPPApplication.Application = true
+			p.etox.PPPToxicity.SublethalForagers = homing || handling
+			if !homing {
+				p.etox.PPPToxicity.HomingFailureED50 = 0
+			}
+			if !handling {
+				p.etox.PPPToxicity.HandlingED50 = 0
+			}
+		})
+		a.Initialize()
+		stats := ecs.GetResource[globals.ForagingStatsEtox](&a.World)
+		filter := ecs.NewFilter1[comp.HandlingTime](&a.World)
+		for a.Update() {
+			homingFailures += stats.HomingFailures
+			energy += stats.MeanEnergyExpenditure
+
+			query := filter.Query()
+			for query.Next() {
+				maxHandling = max(maxHandling, query.Get().Nectar)
+			}
+		}
+		a.Finalize()
+		return homingFailures, maxHandling, energy
+	}
+
+	failuresOff, handlingOff, energyOff := run(false, false)
+	failuresHoming, handlingHoming, _ := run(true, false)
+	failuresHandling, handlingHandling, energyHandling := run(false, true)
+
+	assert.Equal(t, 0, failuresOff)
+	assert.Greater(t, failuresHoming, 0)
+	assert.Equal(t, 0, failuresHandling)
+
+	// handling times are prolonged for the exposed squadrons only, not for the patches
+	assert.Equal(t, handlingOff, handlingHoming)
+	assert.Equal(t, handlingOff, handlingHandling)
+	assert.NotEqual(t, energyOff, energyHandling)
+}
+
+func TestSublethalParams(t *testing.T) {
+	pe := params.DefaultEtox()
+	assert.Equal(t, 0.0, pe.PPPToxicity.HomingFailureResponse().Mortality(1))
+
+	pe.PPPToxicity.SublethalForagers = true
+	assert.InDelta(t, 0.5, pe.PPPToxicity.HomingFailureResponse().Mortality(pe.PPPToxicity.HomingFailureED50), 1e-12)
+	assert.InDelta(t, 0.5, pe.PPPToxicity.SublethalCarryOver(), 1e-12)
+
+	pe.PPPToxicity.HandlingED50 = 0
+	assert.Equal(t, 0.0, pe.PPPToxicity.HandlingResponse().Mortality(1))
+
+	pe.PPPToxicity.SublethalHalfLife = 0
+	assert.Equal(t, 0.0, pe.PPPToxicity.SublethalCarryOver())
+}
+
+func TestSublethalLarvalEffects(t *testing.T) {
+	run := func(sublethal bool) (failures, delayed, longevity int, marked, foragersMarked bool) {
+		a := newTestApp(t, beecsEtox, func(p *testParams) {
+			p.etox.PPPApplication.Application = true
+			p.etox.PPPToxicity.SublethalLarvae = sublethal
+			p.etox.PPPToxicity.DevelopmentDelayED50 = 0.0001
+			p.etox.PPPToxicity.PupalFailureED50 = 0.0005
+			p.etox.PPPToxicity.LongevityED50 = 0.0001
+		})
+		a.Initialize()
+		stats := ecs.GetResource[globals.PopulationStatsEtox](&a.World)
+		inHive := ecs.GetResource[globals.InHiveEtox](&a.World)
+		filter := ecs.NewFilter1[comp.PPPExpo](&a.World)
+		for a.Update() {
+			failures += stats.PupalFailures
+			delayed += stats.DelayedPupae
+			longevity += stats.LongevityDeaths
+			for _, m := range inHive.WorkerLarvalDose {
+				marked = marked || m > 0
+			}
+			query := filter.Query()
+			for query.Next() {
+				foragersMarked = foragersMarked || query.Get().LarvalDose > 0
+			}
+		}
+		a.Finalize()
+		return
+	}
+
+	failures, delayed, longevity, marked, foragersMarked := run(false)
+	assert.Equal(t, 0, failures+delayed+longevity)
+	assert.True(t, marked)
+	assert.True(t, foragersMarked)
+
+	failures, delayed, longevity, marked, foragersMarked = run(true)
+	assert.Greater(t, failures, 0)
+	assert.Greater(t, delayed, 0)
+	assert.Greater(t, longevity, 0)
+	assert.True(t, marked)
+	assert.True(t, foragersMarked)
+}
@@ -0,0 +1,123 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/treatment"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInHiveTreatments(t *testing.T) {
+	run := func(v variant, treatments ...params.Treatment) (*globals.PPPFate, *globals.StoragesEtox, int) {
+		a := newTestApp(t, v, func(p *testParams) {
+			p.base.Termination.MaxTicks = 270
+			p.etox.PPPApplication.PollenCompartments = v == nbeecsEtox
+			p.etox.Wax.WaxCompartment = true
+			p.etox.InHiveTreatments.Treatments = treatments
+		})
+		a.Run()
+
+		fate := ecs.GetResource[globals.PPPFate](&a.World)
+		stores := ecs.GetResource[globals.StoragesEtox](&a.World)
+		pop := ecs.GetResource[globals.PopulationStats](&a.World)
+		assert.Nil(t, ecs.GetResource[globals.AccountingEvents](&a.World).Err())
+
+		balance := fate.MassBalance(stores)
+		assert.InDelta(t, 0.0, balance[len(balance)-1].PPP, 1e-6*(fate.PPPtreatmentInput+1))
+		return fate, stores, pop.TotalAdults
+	}
+
+	strip := params.Treatment{
+		Name: "Strip", Method: treatment.Strip, Start: 220, End: 262, Dose: 20000,
+		ReleaseRate: 0.05, ShareHoney: 0.1, ShareWax: 0.1, ContactRate: 0.5, DT50: 2,
+	}
+	fumigation := params.Treatment{
+		Name: "Fumigation", Method: treatment.Fumigation, Start: 220, End: 240, Interval: 7, Dose: 1000,
+		ShareHoney: 0.05, ContactRate: 0.2,
+	}
+
+	for _, v := range []variant{beecsEtox, nbeecsEtox} {
+		fate, _, control := run(v)
+		assert.Equal(t, 0.0, fate.PPPtreatmentInput)
+
+		fate, stores, treated := run(v, strip)
+		assert.Greater(t, fate.PPPtreatmentInput, 0.0)
+		assert.Less(t, fate.PPPtreatmentInput, strip.Dose) // strips are removed before they are empty
+		assert.Greater(t, fate.PPPtreatmentToHoney, 0.0)
+		assert.Greater(t, fate.PPPtreatmentToWax, 0.0)
+		assert.Greater(t, fate.PPPtreatmentContact, 0.0)
+		assert.Greater(t, fate.PPPtreatmentDegraded, 0.0)
+		assert.Greater(t, stores.PPPwax, 0.0)
+		assert.Less(t, treated, control)
+
+		fate, stores, _ = run(v, fumigation)
+		assert.InDelta(t, 3*fumigation.Dose, fate.PPPtreatmentInput, 1e-9) // pulses on days 220, 227 and 234
+		assert.Equal(t, 0.0, fate.PPPtreatmentToWax)
+		assert.Equal(t, 0.0, stores.PPPtreatment) // vented
+		assert.Greater(t, fate.PPPtreatmentDegraded, 0.0)
+	}
+}
+
+// the treatment contact dose of foragers must not be averaged with the contact doses of foraging trips
+func TestTreatmentContactForagers(t *testing.T) {
+	a := newTestApp(t, beecsEtox, func(p *testParams) {
+		p.base.Termination.MaxTicks = 230
+		p.etox.PPPApplication.ContactSum = false
+		p.etox.PPPApplication.ContactExposureOneDay = false
+		p.etox.InHiveTreatments.Treatments = []params.Treatment{{
+			Name: "Strip", Method: treatment.Strip, Start: 220, End: 262, Dose: 20000,
+			ReleaseRate: 0.05, ShareHoney: 0.1, ContactRate: 0.5, DT50: 2,
+		}}
+	})
+	a.Initialize()
+	filter := ecs.NewFilter1[comp.PPPExpo](&a.World)
+
+	treated := map[ecs.Entity]float64{}
+	for tick := 0; a.Update(); tick++ {
+		if tick == 220 {
+			query := filter.Query()
+			for query.Next() {
+				expo := query.Get()
+				assert.Equal(t, 0.0, expo.ContactDose)
+				assert.Greater(t, expo.TreatmentDose, 0.0)
+				treated[query.Entity()] = expo.CumContactDose + expo.TreatmentDose
+			}
+		}
+		if tick == 221 {
+			checked := 0
+			query := filter.Query()
+			for query.Next() {
+				if cum, ok := treated[query.Entity()]; ok {
+					assert.InDelta(t, cum, query.Get().CumContactDose, 1e-12)
+					checked++
+				}
+			}
+			assert.Greater(t, checked, 0)
+		}
+	}
+	a.Finalize()
+}
+
+// the contact dose of a treatment with its own contact LD50 is scaled to the toxicity of the simulated PPP
+func TestTreatmentContactLD50(t *testing.T) {
+	run := func(ld50 float64) float64 {
+		a := newTestApp(t, beecsEtox, func(p *testParams) {
+			p.base.Termination.MaxTicks = 221
+			p.etox.InHiveTreatments.Treatments = []params.Treatment{{
+				Name: "Strip", Method: treatment.Strip, Start: 220, End: 262, Dose: 20000,
+				ReleaseRate: 0.05, ContactRate: 0.5, ContactLD50: ld50,
+			}}
+		})
+		a.Run()
+		inHive := ecs.GetResource[globals.InHiveEtox](&a.World)
+		return inHive.WorkerContactDose[0]
+	}
+
+	ppp := run(0)
+	assert.Greater(t, ppp, 0.0)
+	assert.InDelta(t, 0.1*ppp, run(10*params.DefaultEtox().PPPToxicity.ForagerContactLD50), 1e-12)
+}
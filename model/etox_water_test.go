@@ -0,0 +1,104 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/obs"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaterForaging(t *testing.T) {
+	run := func(concentration float64, application bool) (fate *globals.PPPFate, collected float64, dilution float64) {
+		a := newTestApp(t, nbeecsEtox, func(p *testParams) {
+			p.base.InitialPatches.Patches = append(p.base.InitialPatches.Patches, comp.PatchConfig{
+				DistToColony: 200,
+				WaterPatch: &comp.WaterPatch{
+					DetectionProbability: 0.5,
+					PPPconcentration:     concentration,
+				},
+			})
+			p.etox.PPPApplication.Application = application
+			p.etox.WaterForaging.WaterForaging = true
+		})
+		a.Initialize()
+
+		observer := obs.WaterForaging{}
+		observer.Initialize(&a.World)
+		for a.Update() {
+			values := observer.Values(&a.World)
+			need := values[0] + values[1]
+			assert.LessOrEqual(t, values[2], need+1e-9)
+			collected += values[2]
+			dilution += values[1]
+		}
+		a.Finalize()
+
+		assert.Nil(t, ecs.GetResource[globals.AccountingEvents](&a.World).Err())
+
+		fate = ecs.GetResource[globals.PPPFate](&a.World)
+		stores := ecs.GetResource[globals.StoragesEtox](&a.World)
+		balance := fate.MassBalance(stores)
+		assert.InDelta(t, 0, balance[len(balance)-1].PPP, 1e-6*max(fate.TotalPPPforaged, 1))
+		return
+	}
+
+	fate, collected, dilution := run(0, false)
+	assert.Greater(t, collected, 0.0)
+	assert.Greater(t, dilution, 0.0)
+	assert.Equal(t, 0.0, fate.TotalPPPforaged)
+
+	fate, _, _ = run(1000, false)
+	assert.Greater(t, fate.PPPwaterForaged, 0.0)
+	assert.Equal(t, fate.PPPwaterForaged, fate.TotalPPPforaged)
+	assert.Greater(t, fate.PPPIHbees+fate.PPPNurses+fate.PPPlarvae, 0.0)
+	assert.Greater(t, fate.PPPwaterEvaporated, 0.0)
+
+	fate, _, _ = run(1000, true)
+	assert.Greater(t, fate.PPPwaterForaged, 0.0)
+	assert.Greater(t, fate.TotalPPPforaged, fate.PPPwaterForaged)
+}
+
+func TestWaterPatchConcentration(t *testing.T) {
+	patch := comp.WaterPatch{PPPconcentration: 5}
+	assert.Equal(t, 5.0, patch.Concentration(100))
+
+	patch.PPPseries = [][2]float64{{100, 20}, {130, 0}}
+	assert.Equal(t, 0.0, patch.Concentration(50))
+	assert.Equal(t, 20.0, patch.Concentration(100))
+	assert.Equal(t, 20.0, patch.Concentration(129))
+	assert.Equal(t, 0.0, patch.Concentration(200))
+}
+
+// squadrons that find no water source are replaced by others, so the need is met on every foraging day
+func TestWaterForagingSearch(t *testing.T) {
+	a := newTestApp(t, nbeecsEtox, func(p *testParams) {
+		p.base.InitialPatches.Patches = append(p.base.InitialPatches.Patches, comp.PatchConfig{
+			DistToColony: 200,
+			WaterPatch:   &comp.WaterPatch{DetectionProbability: 0.5},
+		})
+		p.etox.WaterForaging.WaterForaging = true
+	})
+	a.Initialize()
+
+	observer := obs.WaterForaging{}
+	observer.Initialize(&a.World)
+	period := ecs.GetResource[globals.ForagingPeriod](&a.World)
+	days := 0
+	for a.Update() {
+		if period.SecondsToday == 0 {
+			continue
+		}
+		values := observer.Values(&a.World)
+		need := values[0] + values[1]
+		if need > 0 {
+			days++
+		}
+		assert.InDelta(t, need, values[2], 1e-9)
+	}
+	a.Finalize()
+
+	assert.Greater(t, days, 0)
+}
@@ -0,0 +1,47 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaxCompartment(t *testing.T) {
+	run := func(v variant, residue float64, application bool) (*globals.PPPFate, *globals.StoragesEtox) {
+		a := newTestApp(t, v, func(p *testParams) {
+			p.etox.PPPApplication.Application = application
+			p.etox.PPPApplication.PollenCompartments = v == nbeecsEtox
+			p.etox.Wax.WaxCompartment = true
+			p.etox.Wax.InitialResidue = residue
+		})
+		a.Run()
+
+		fate := ecs.GetResource[globals.PPPFate](&a.World)
+		stores := ecs.GetResource[globals.StoragesEtox](&a.World)
+		assert.Nil(t, ecs.GetResource[globals.AccountingEvents](&a.World).Err())
+
+		balance := fate.MassBalance(stores)
+		assert.InDelta(t, 0.0, balance[len(balance)-1].PPP, 1e-6*(fate.TotalPPPforaged+fate.PPPwaxInput))
+		return fate, stores
+	}
+
+	for _, v := range []variant{beecsEtox, nbeecsEtox} {
+		fate, stores := run(v, 1000, false)
+		assert.InDelta(t, 1000.0, fate.PPPwaxInput, 1e-9) // 1 kg wax at 1 mg/kg
+		assert.Equal(t, 0.0, fate.TotalPPPforaged)
+		assert.Greater(t, fate.PPPwaxToHoney, 0.0)
+		assert.Greater(t, fate.PPPwaxToPollen, 0.0)
+		assert.Greater(t, fate.PPPwaxToLarvae, 0.0)
+		assert.Greater(t, fate.PPPwaxReplaced, 0.0)
+		assert.Greater(t, fate.PPPlarvae, 0.0)
+		assert.Greater(t, stores.PPPwax, 0.0)
+		assert.Less(t, stores.PPPwax, fate.PPPwaxInput)
+
+		fate, stores = run(v, 0, true)
+		assert.Equal(t, 0.0, fate.PPPwaxInput)
+		assert.Less(t, fate.PPPwaxToHoney+fate.PPPwaxToPollen, 0.0) // clean wax takes up PPP from the stores
+		assert.Greater(t, stores.PPPwax, 0.0)
+	}
+}
@@ -0,0 +1,113 @@
+package model_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/management"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/treatment"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/obs"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagement(t *testing.T) {
+	dir := t.TempDir()
+	file := `[
+		{"Name": "Harvest", "Action": 0, "Day": 200, "Amount": 5},
+		{"Name": "Autumn feeding", "Action": 1, "Day": 250, "Amount": 10}
+	]`
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "events.json"), []byte(file), 0644))
+
+	events := []params.ManagementEvent{
+		{Name: "Pollen patty", Action: management.PollenSubstitute, Day: 90, Amount: 500},
+		{Name: "Brood frames in", Action: management.BroodExchange, Day: 150, Amount: 5000},
+		{Name: "Brood frames out", Action: management.BroodExchange, Day: 160, Amount: -3000},
+		{Name: "Split", Action: management.Split, Day: 170, Amount: 0.3},
+		{Name: "Next year", Action: management.Split, Day: 171, Years: []int{1}, Amount: 0.3},
+	}
+
+	type state struct {
+		honey, pollen float64
+		brood, adults int
+	}
+
+	run := func(v variant) (a *app.App, before map[int64]state) {
+		a = newTestApp(t, v, func(p *testParams) {
+			p.base.WorkingDirectory.Path = dir
+			p.base.Management.Events = events
+			p.base.Management.File = "events.json"
+			p.base.Varroa.Enabled = true
+			p.etox.PPPApplication.PollenCompartments = v == nbeecsEtox
+			p.etox.InHiveTreatments.Treatments = []params.Treatment{{
+				Name: "Fumigation", Method: treatment.Fumigation, Start: 100, End: 100, Dose: 1000, ShareHoney: 0.5,
+			}}
+		})
+
+		before = map[int64]state{}
+		a.Initialize()
+		stores := ecs.GetResource[globals.Stores](&a.World)
+		pop := ecs.GetResource[globals.PopulationStats](&a.World)
+		for tick := int64(0); ; tick++ {
+			before[tick] = state{stores.Honey, stores.Pollen, pop.TotalBrood, pop.TotalAdults}
+			if !a.Update() {
+				break
+			}
+		}
+		a.Finalize()
+		return
+	}
+
+	energy := params.Default().EnergyContent
+	for _, v := range variants {
+		a, before := run(v)
+		records := ecs.GetResource[globals.ManagementRecords](&a.World).Records
+		assert.Len(t, records, 6)
+
+		byName := map[string]globals.ManagementRecord{}
+		for _, rec := range records {
+			byName[rec.Name] = rec
+		}
+
+		pollen := byName["Pollen patty"]
+		assert.Equal(t, int64(90), pollen.Tick)
+		assert.Equal(t, 500.0, pollen.Pollen)
+
+		in, out := byName["Brood frames in"], byName["Brood frames out"]
+		assert.InDelta(t, 5000, in.Brood, 50)
+		assert.InDelta(t, -3000, out.Brood, 50)
+
+		split := byName["Split"]
+		assert.Equal(t, int64(170), split.Tick)
+		assert.InDelta(t, -0.3*before[170].honey, split.Honey, 1e-6)
+		assert.InDelta(t, -0.3*before[170].pollen, split.Pollen, 1e-6)
+		assert.InDelta(t, -0.3*float64(before[170].adults), split.Adults, 0.1*float64(before[170].adults))
+		assert.Less(t, split.Brood, 0)
+
+		harvest := byName["Harvest"]
+		assert.Equal(t, management.HoneyHarvest, harvest.Action)
+		assert.InDelta(t, -(before[200].honey - 5*1000*energy.Honey), harvest.Honey, 1e-6)
+
+		feeding := byName["Autumn feeding"]
+		assert.Equal(t, int64(250), feeding.Tick)
+		assert.InDelta(t, 10*1000/342.3*1e6*energy.Sucrose, feeding.Honey, 1e-6)
+
+		o := obs.ManagementEvents{}
+		o.Initialize(&a.World)
+		rows := o.Values(&a.World)
+		assert.Len(t, rows, len(records))
+		assert.Equal(t, []float64{200, float64(management.HoneyHarvest), harvest.Honey, 0, 0, 0}, rows[4])
+
+		if v.etox() {
+			assert.Nil(t, ecs.GetResource[globals.AccountingEvents](&a.World).Err())
+			fate := ecs.GetResource[globals.PPPFate](&a.World)
+			balance := fate.MassBalance(ecs.GetResource[globals.StoragesEtox](&a.World))
+			assert.Greater(t, fate.PPPmanagement, 0.0)
+			assert.InDelta(t, 0.0, balance[len(balance)-1].PPP, 1e-6*fate.PPPtreatmentInput)
+		}
+	}
+}
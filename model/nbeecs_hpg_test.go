@@ -0,0 +1,51 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHPGState(t *testing.T) {
+	run := func(application bool, ticks int) (hpg *globals.HPG, capability []float64, events *globals.AccountingEvents) {
+		a := newTestApp(t, nbeecsEtox, func(p *testParams) {
+			p.base.Termination.MaxTicks = ticks
+			p.etox.PPPApplication.Application = application
+			p.nurse.NursingRework.HGEffects = true
+		})
+		a.Run()
+
+		nurse := ecs.GetResource[globals.NursingGlobals](&a.World)
+		return ecs.GetResource[globals.HPG](&a.World), nurse.Capability, ecs.GetResource[globals.AccountingEvents](&a.World)
+	}
+
+	// the initial glands are developed according to the age of the cohorts at the first evaluation
+	initial, _, _ := run(false, 1)
+	hpgParams := params.DefaultNursebeecs().HPGDevelopment
+	for i := 1; i < len(initial.Size); i++ {
+		assert.InDelta(t, hpgParams.Potential(i), initial.Size[i], 1e-12)
+	}
+
+	control, capability, events := run(false, 200) // shortly after the application
+	assert.Nil(t, events.Err())
+	for i := 4; i <= 12; i++ {
+		assert.Equal(t, control.Size[i], capability[i])
+		assert.Greater(t, control.Size[i], 0.5)
+		assert.LessOrEqual(t, control.Size[i], 1.0)
+		assert.Equal(t, 0.0, control.Dose[i])
+	}
+
+	exposed, _, events := run(true, 200)
+	assert.Nil(t, events.Err())
+	reduced := 0
+	for i := 4; i <= 12; i++ {
+		assert.Greater(t, exposed.Dose[i], 0.0)
+		if exposed.Size[i] < control.Size[i] {
+			reduced++
+		}
+	}
+	assert.Greater(t, reduced, 0)
+}
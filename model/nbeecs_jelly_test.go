@@ -0,0 +1,46 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/obs"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJellyResidues(t *testing.T) {
+	run := func(application bool, v variant) (maxWorker, maxDrone float64) {
+		a := newTestApp(t, v, func(p *testParams) {
+			p.base.Termination.MaxTicks = 200 // shortly after the application
+			p.etox.PPPApplication.Application = application
+		})
+		a.Initialize()
+
+		observer := obs.JellyResidues{}
+		observer.Initialize(&a.World)
+		assert.Equal(t, []string{"WorkerJelly", "DroneJelly"}, observer.Header())
+
+		for a.Update() {
+			values := observer.Values(&a.World)
+			assert.GreaterOrEqual(t, values[0], 0.0)
+			assert.GreaterOrEqual(t, values[1], 0.0)
+			maxWorker = max(maxWorker, values[0])
+			maxDrone = max(maxDrone, values[1])
+		}
+		a.Finalize()
+
+		assert.Nil(t, ecs.GetResource[globals.AccountingEvents](&a.World).Err())
+		return
+	}
+
+	for _, v := range []variant{beecsEtox, nbeecsEtox} {
+		worker, drone := run(false, v)
+		assert.Equal(t, 0.0, worker)
+		assert.Equal(t, 0.0, drone)
+
+		worker, drone = run(true, v)
+		assert.Greater(t, worker, 0.0)
+		assert.Greater(t, drone, 0.0)
+	}
+}
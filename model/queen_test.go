@@ -0,0 +1,93 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/queen"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueenLoss(t *testing.T) {
+	run := func(lossDay int) (states []queen.State, eggs []int, q *globals.Queen) {
+		a := newTestApp(t, beecs, func(p *testParams) {
+			p.base.Queen.MatingSuccess = 1
+		})
+		a.Initialize()
+		q = ecs.GetResource[globals.Queen](&a.World)
+		e := ecs.GetResource[globals.Eggs](&a.World)
+		for tick := 0; a.Update(); tick++ {
+			if tick == lossDay {
+				q.Lose()
+			}
+			states = append(states, q.State)
+			eggs = append(eggs, e.Workers[0]+e.Drones[0])
+		}
+		a.Finalize()
+		return
+	}
+
+	// emergency rearing in summer
+	states, eggs, q := run(150)
+	assert.Equal(t, 1, q.Replacements)
+	assert.Equal(t, queen.Laying, q.State)
+	assert.Equal(t, queen.Queenless, states[150])
+	broodless := 0
+	for tick := 151; tick < 200; tick++ {
+		if states[tick] != queen.Laying {
+			assert.Equal(t, 0, eggs[tick])
+			broodless++
+		}
+	}
+	p := params.Default().Queen
+	assert.Equal(t, p.RearingTime+p.MatingTime, broodless)
+	assert.Greater(t, eggs[200], 0)
+
+	// no young brood to rear a queen from in winter
+	_, _, q = run(340)
+	assert.Equal(t, queen.Hopeless, q.State)
+	assert.Equal(t, 0, q.Replacements)
+}
+
+func TestQueenSupersedure(t *testing.T) {
+	a := newTestApp(t, beecs, func(p *testParams) {
+		p.base.Queen.Ageing = true
+		p.base.Queen.SupersedureAge = 500
+	})
+	a.Run()
+	q := ecs.GetResource[globals.Queen](&a.World)
+	assert.Equal(t, 1, q.Replacements)
+	assert.Equal(t, queen.Laying, q.State)
+	assert.Less(t, q.Age, 365)
+}
+
+func TestQueenEffects(t *testing.T) {
+	run := func(effects bool) (maxDose float64, minFecundity float64, q *globals.Queen) {
+		a := newTestApp(t, beecsEtox, func(p *testParams) {
+			p.etox.PPPApplication.Application = true
+			p.etox.PPPToxicity.QueenEffects = effects
+		})
+		a.Initialize()
+		q = ecs.GetResource[globals.Queen](&a.World)
+		minFecundity = 1
+		for a.Update() {
+			maxDose = max(maxDose, q.Dose)
+			minFecundity = min(minFecundity, q.Fecundity)
+		}
+		a.Finalize()
+		return
+	}
+
+	dose, fecundity, q := run(false)
+	assert.Equal(t, 0.0, dose)
+	assert.Equal(t, 1.0, fecundity)
+	assert.Equal(t, 0, q.Replacements)
+	assert.Equal(t, queen.Laying, q.State)
+
+	dose, fecundity, q = run(true)
+	assert.Greater(t, dose, 0.0)
+	assert.Less(t, fecundity, 1.0)
+	assert.NotEqual(t, queen.Laying, q.State) // the default application is far above the illustrative queen LD50
+}
@@ -0,0 +1,45 @@
+package model_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/obs"
+	"github.com/mlange-42/ark-tools/reporter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandStreams(t *testing.T) {
+	a := globals.NewRandStreams(1)
+	b := globals.NewRandStreams(1)
+	b.Get("Other")
+
+	assert.Equal(t, rand.New(a.Get("Foraging")).Float64(), rand.New(b.Get("Foraging")).Float64())
+	assert.NotEqual(t, rand.New(a.Get("Foraging")).Float64(), rand.New(a.Get("Other")).Float64())
+	assert.Same(t, a.Get("Foraging"), a.Get("Foraging"))
+}
+
+func TestRandStreamsToggle(t *testing.T) {
+	run := func(water bool, homing bool) [][]float64 {
+		a := newTestApp(t, beecsEtox, func(p *testParams) {
+			p.base.RandomSeed.Seed = 3
+			p.etox.WaterForaging.WaterForaging = water
+			p.etox.PPPToxicity.SublethalForagers = homing
+		})
+		rows := [][]float64{}
+		a.AddSystem(&reporter.RowCallback{
+			Observer: &obs.Debug{},
+			Callback: func(step int, row []float64) {
+				rows = append(rows, append([]float64{}, row...))
+			},
+		})
+		a.Run()
+		return rows
+	}
+
+	// sub-models without effects on the colony in the absence of PPP must not change the colony dynamics
+	reference := run(false, false)
+	assert.Equal(t, reference, run(true, false))
+	assert.Equal(t, reference, run(false, true))
+}
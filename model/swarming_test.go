@@ -0,0 +1,102 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/queen"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/treatment"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/obs"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark-tools/app"
+	"github.com/mlange-42/ark-tools/reporter"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwarming(t *testing.T) {
+	type swarm struct {
+		tick   int
+		row    []float64
+		adults int
+		honey  float64
+	}
+
+	run := func(v variant, enabled bool) (swarms []swarm, a *app.App) {
+		a = newTestApp(t, v, func(p *testParams) {
+			p.base.Swarming.Enabled = enabled
+			p.base.Swarming.Probability = 1
+			p.base.Swarming.BroodCongestion = 0.05 // colonies limited by nurses stay smaller
+			p.base.Swarming.AdultDensity = 0.05
+			p.base.Queen.MatingSuccess = 1
+			p.base.Varroa.Enabled = true
+			p.etox.InHiveTreatments.Treatments = []params.Treatment{{
+				Name: "Fumigation", Method: treatment.Fumigation, Start: 100, End: 100, Dose: 1000, ShareHoney: 0.5,
+			}}
+		})
+
+		o := &obs.Swarming{}
+		a.AddSystem(&reporter.RowCallback{
+			Observer: o,
+			Callback: func(step int, row []float64) {
+				if row[1] > 0 {
+					swarms = append(swarms, swarm{tick: step, row: append([]float64{}, row...)})
+				}
+			},
+		})
+		a.Initialize()
+		pop := ecs.GetResource[globals.PopulationStats](&a.World)
+		stores := ecs.GetResource[globals.Stores](&a.World)
+		for tick := 0; ; tick++ {
+			adults, honey := pop.TotalAdults, stores.Honey
+			if !a.Update() {
+				break
+			}
+			if len(swarms) > 0 && swarms[len(swarms)-1].tick == tick {
+				swarms[len(swarms)-1].adults = adults
+				swarms[len(swarms)-1].honey = honey
+			}
+		}
+		a.Finalize()
+		return
+	}
+
+	sw := params.Default().Swarming
+	for _, v := range variants {
+		swarms, a := run(v, false)
+		assert.Empty(t, swarms)
+		assert.Equal(t, 0, ecs.GetResource[globals.Swarming](&a.World).Swarms)
+
+		swarms, a = run(v, true)
+		assert.Len(t, swarms, 1) // at most once per year
+		s := swarms[0]
+		assert.GreaterOrEqual(t, s.tick, sw.SeasonStart)
+		assert.LessOrEqual(t, s.tick, sw.SeasonEnd)
+		assert.Equal(t, 1.0, s.row[0])
+		assert.InDelta(t, sw.Fraction*float64(s.adults), s.row[1], 0.2*float64(s.adults))
+		assert.InDelta(t, s.row[1]*sw.HoneyLoad*0.001*params.Default().EnergyContent.Honey, s.row[2], 1e-6)
+		assert.Less(t, s.row[2], s.honey)
+		assert.Greater(t, s.row[3], 0.0)
+
+		q := ecs.GetResource[globals.Queen](&a.World)
+		assert.Equal(t, queen.Laying, q.State)
+		assert.Equal(t, 1, q.Replacements)
+		assert.Less(t, q.Age, 365-s.tick)
+
+		if v.etox() {
+			assert.Nil(t, ecs.GetResource[globals.AccountingEvents](&a.World).Err())
+			fate := ecs.GetResource[globals.PPPFate](&a.World)
+			balance := fate.MassBalance(ecs.GetResource[globals.StoragesEtox](&a.World))
+			assert.Greater(t, fate.PPPswarm, 0.0)
+
+			left := 0 // squadrons leaving with the swarm keep their exposure records
+			for _, rec := range ecs.GetResource[globals.ForagerExposureRecords](&a.World).Dead {
+				if rec.Tick == int64(s.tick) {
+					left++
+				}
+			}
+			assert.Greater(t, left, 0)
+			assert.InDelta(t, 0.0, balance[len(balance)-1].PPP, 1e-6*fate.PPPtreatmentInput)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package model_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/obs"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark-tools/reporter"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVarroa(t *testing.T) {
+	run := func(v variant, enabled bool, treatments ...params.MiteTreatment) (peak []float64, mites *globals.Mites, adults int) {
+		a := newTestApp(t, v, func(p *testParams) {
+			p.base.RandomSeed.Seed = 3
+			p.base.Varroa.Enabled = enabled
+			p.base.Varroa.Treatments = treatments
+		})
+
+		o := &obs.Mites{}
+		peak = make([]float64, len(o.Header()))
+		a.AddSystem(&reporter.RowCallback{
+			Observer: o,
+			Callback: func(step int, row []float64) {
+				for i, v := range row {
+					peak[i] = math.Max(peak[i], v)
+				}
+			},
+		})
+		a.Run()
+
+		mites = ecs.GetResource[globals.Mites](&a.World)
+		return peak, mites, ecs.GetResource[globals.PopulationStats](&a.World).TotalAdults
+	}
+
+	for _, v := range variants {
+		peak, mites, _ := run(v, false)
+		assert.Equal(t, make([]float64, len(peak)), peak)
+		assert.Equal(t, 0, mites.Total())
+
+		peak, mites, adults := run(v, true)
+		assert.Greater(t, peak[0], 500.0)   // mites multiply
+		assert.Greater(t, peak[2], 0.0)     // mites in cells
+		assert.Greater(t, peak[4], 0.0)     // infected pupae
+		assert.Greater(t, peak[5], 0.0)     // infected in-hive bees
+		assert.Greater(t, peak[6], 0.0)     // infected foragers
+		assert.Greater(t, peak[8], peak[7]) // offspring are released with emerging brood
+		assert.Equal(t, 0.0, peak[10])      // no treatments
+		assert.LessOrEqual(t, mites.Infected(), mites.Total())
+
+		treatment := params.MiteTreatment{Name: "Oxalic acid", Start: 220, End: 260, Efficacy: 0.1, CellEfficacy: 0.02}
+		treatedPeak, treated, treatedAdults := run(v, true, treatment)
+		assert.Greater(t, treatedPeak[10], 0.0)
+		assert.Less(t, treated.Total(), mites.Total())
+		assert.GreaterOrEqual(t, treatedAdults, adults/2)
+	}
+}
@@ -0,0 +1,32 @@
+package obs
+
+import (
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// AccountingEvents is a row observer for the imbalances detected by the accounting checks of the _ecotox models,
+// see [globals.AccountingEvents].
+//
+// Columns are "Events" (since the start of the simulation) and "NewEvents" (detected today).
+type AccountingEvents struct {
+	events *globals.AccountingEvents
+	last   int
+	data   []float64
+}
+
+func (o *AccountingEvents) Initialize(w *ecs.World) {
+	o.events = ecs.GetResource[globals.AccountingEvents](w)
+	o.last = 0
+	o.data = make([]float64, len(o.Header()))
+}
+func (o *AccountingEvents) Update(w *ecs.World) {}
+func (o *AccountingEvents) Header() []string {
+	return []string{"Events", "NewEvents"}
+}
+func (o *AccountingEvents) Values(w *ecs.World) []float64 {
+	o.data[0] = float64(o.events.Count)
+	o.data[1] = float64(o.events.Count - o.last)
+	o.last = o.events.Count
+	return o.data
+}
@@ -0,0 +1,58 @@
+package obs
+
+import (
+	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// ForagerExposure is a table observer for the lifetime exposure of forager squadrons to PPP.
+//
+// There is one row for every squadron that died so far, followed by one row for every living squadron.
+// Use it with a final snapshot to get the exposure distribution at death or at the end of the run.
+//
+// Columns are "Tick" (of death or observation), "Alive" and "Reverted" (0 or 1), "CumOralDose" [µg],
+// "CumContactDose" [µg], "DaysExposed", "PeakOralDose" [µg] and "PeakContactDose" [µg].
+type ForagerExposure struct {
+	records *globals.ForagerExposureRecords
+	time    *resource.Tick
+	filter  *ecs.Filter2[comp.PPPExpo, comp.ActivityEtox]
+	data    [][]float64
+}
+
+func (o *ForagerExposure) Initialize(w *ecs.World) {
+	o.records = ecs.GetResource[globals.ForagerExposureRecords](w)
+	o.time = ecs.GetResource[resource.Tick](w)
+	o.filter = o.filter.New(w)
+}
+func (o *ForagerExposure) Update(w *ecs.World) {}
+func (o *ForagerExposure) Header() []string {
+	return []string{"Tick", "Alive", "Reverted", "CumOralDose", "CumContactDose", "DaysExposed", "PeakOralDose", "PeakContactDose"}
+}
+func (o *ForagerExposure) Values(w *ecs.World) [][]float64 {
+	o.data = o.data[:0]
+	for _, rec := range o.records.Dead {
+		o.data = append(o.data, o.row(rec))
+	}
+	query := o.filter.Query()
+	for query.Next() {
+		expo, act := query.Get()
+		o.data = append(o.data, o.row(globals.NewForagerExposure(o.time.Tick, expo, act.Reverted, true)))
+	}
+	return o.data
+}
+
+func (o *ForagerExposure) row(rec globals.ForagerExposure) []float64 {
+	return []float64{
+		float64(rec.Tick), boolToFloat(rec.Alive), boolToFloat(rec.Reverted),
+		rec.CumOralDose, rec.CumContactDose, float64(rec.DaysExposed), rec.PeakOralDose, rec.PeakContactDose,
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
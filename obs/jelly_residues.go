@@ -0,0 +1,30 @@
+package obs
+
+import (
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// JellyResidues is a row observer for the daily PPP concentrations in the food nurses produce for larvae,
+// for comparison with residues measured in worker and drone jelly.
+//
+// Columns are "WorkerJelly" and "DroneJelly" [µg/kg].
+// Concentrations are 0 on days without larvae of the respective type.
+type JellyResidues struct {
+	stats *globals.PopulationStatsEtox
+	data  []float64
+}
+
+func (o *JellyResidues) Initialize(w *ecs.World) {
+	o.stats = ecs.GetResource[globals.PopulationStatsEtox](w)
+	o.data = make([]float64, len(o.Header()))
+}
+func (o *JellyResidues) Update(w *ecs.World) {}
+func (o *JellyResidues) Header() []string {
+	return []string{"WorkerJelly", "DroneJelly"}
+}
+func (o *JellyResidues) Values(w *ecs.World) []float64 {
+	o.data[0] = o.stats.WorkerJellyConc
+	o.data[1] = o.stats.DroneJellyConc
+	return o.data
+}
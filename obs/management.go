@@ -0,0 +1,32 @@
+package obs
+
+import (
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// ManagementEvents is a table observer for the beekeeper management actions that took place so far.
+//
+// There is one row per action. Columns are "Tick", "Action" (see [github.com/fzeitner/Nursebeecs-master-thesis/enum/management.Action]),
+// and the changes caused by the action: "Honey" [kJ], "Pollen" [g], "Brood" (cells) and "Adults".
+type ManagementEvents struct {
+	records *globals.ManagementRecords
+	data    [][]float64
+}
+
+func (o *ManagementEvents) Initialize(w *ecs.World) {
+	o.records = ecs.GetResource[globals.ManagementRecords](w)
+}
+func (o *ManagementEvents) Update(w *ecs.World) {}
+func (o *ManagementEvents) Header() []string {
+	return []string{"Tick", "Action", "Honey", "Pollen", "Brood", "Adults"}
+}
+func (o *ManagementEvents) Values(w *ecs.World) [][]float64 {
+	o.data = o.data[:0]
+	for _, rec := range o.records.Records {
+		o.data = append(o.data, []float64{
+			float64(rec.Tick), float64(rec.Action), rec.Honey, rec.Pollen, float64(rec.Brood), float64(rec.Adults),
+		})
+	}
+	return o.data
+}
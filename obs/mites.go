@@ -0,0 +1,57 @@
+package obs
+
+import (
+	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/virus"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Mites is a row observer for the Varroa mite population and the virus infections of the colony.
+//
+// Columns are "Mites" (total), "PhoreticMites", "MitesInCells", "InfectedMites", "InfectedPupae",
+// "InfectedInHive" (workers and drones), "InfectedForagers" (individuals of infected squadrons),
+// and the daily flows "MitesInvaded", "MitesEmerged", "MitesDied" and "MitesTreated".
+type Mites struct {
+	mites    *globals.Mites
+	infected *globals.InfectedCohorts
+	foragers *params.Foragers
+	filter   *ecs.Filter1[comp.Virus]
+	data     []float64
+}
+
+func (o *Mites) Initialize(w *ecs.World) {
+	o.mites = ecs.GetResource[globals.Mites](w)
+	o.infected = ecs.GetResource[globals.InfectedCohorts](w)
+	o.foragers = ecs.GetResource[params.Foragers](w)
+	o.filter = o.filter.New(w)
+	o.data = make([]float64, len(o.Header()))
+}
+func (o *Mites) Update(w *ecs.World) {}
+func (o *Mites) Header() []string {
+	return []string{"Mites", "PhoreticMites", "MitesInCells", "InfectedMites", "InfectedPupae", "InfectedInHive", "InfectedForagers",
+		"MitesInvaded", "MitesEmerged", "MitesDied", "MitesTreated"}
+}
+func (o *Mites) Values(w *ecs.World) []float64 {
+	squadrons := 0
+	query := o.filter.Query()
+	for query.Next() {
+		if query.Get().State != virus.Healthy {
+			squadrons++
+		}
+	}
+
+	o.data[0] = float64(o.mites.Total())
+	o.data[1] = float64(o.mites.Phoretic())
+	o.data[2] = float64(o.mites.InCells())
+	o.data[3] = float64(o.mites.Infected())
+	o.data[4] = float64(o.infected.Pupae())
+	o.data[5] = float64(o.infected.InHive())
+	o.data[6] = float64(squadrons * o.foragers.SquadronSize)
+	o.data[7] = float64(o.mites.Invaded)
+	o.data[8] = float64(o.mites.Emerged)
+	o.data[9] = float64(o.mites.Died)
+	o.data[10] = float64(o.mites.Treated)
+	return o.data
+}
@@ -0,0 +1,75 @@
+package obs
+
+import (
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// PollenStoresEtox is a row observer for the compartmentalised pollen stores of beecs_ecotox.
+//
+// Columns are "Pollen" [g], "PPPpollen" [mug], "MeanConc" [mug/g], "ConsumedConc" [mug/g] and "MeanAge" [d].
+// ConsumedConc is the concentration of the pollen eaten during the last consumption.
+type PollenStoresEtox struct {
+	pollenStores *globals.PollenStoresEtox
+	stores       *globals.StoragesEtox
+	data         []float64
+}
+
+func (o *PollenStoresEtox) Initialize(w *ecs.World) {
+	o.pollenStores = ecs.GetResource[globals.PollenStoresEtox](w)
+	o.stores = ecs.GetResource[globals.StoragesEtox](w)
+	o.data = make([]float64, len(o.Header()))
+}
+func (o *PollenStoresEtox) Update(w *ecs.World) {}
+func (o *PollenStoresEtox) Header() []string {
+	return []string{"Pollen", "PPPpollen", "MeanConc", "ConsumedConc", "MeanAge"}
+}
+func (o *PollenStoresEtox) Values(w *ecs.World) []float64 {
+	mass, ppp := o.pollenStores.Total()
+	age := 0.
+	for i, m := range o.pollenStores.Mass {
+		age += float64(i) * m
+	}
+
+	o.data[0] = mass
+	o.data[1] = ppp
+	o.data[2] = 0
+	o.data[3] = o.stores.Pollenconcbeforeeating
+	o.data[4] = 0
+	if mass > 0 {
+		o.data[2] = ppp / mass
+		o.data[4] = age / mass
+	}
+
+	return o.data
+}
+
+// PollenCompartments is a table observer for the age compartments of the pollen stores.
+//
+// Rows are compartment ages in days, with the last row pooling all older pollen.
+// Columns are "Pollen" [g], "Conc" [mug/g] and "PPP" [mug].
+type PollenCompartments struct {
+	pollenStores *globals.PollenStoresEtox
+	data         [][]float64
+}
+
+func (o *PollenCompartments) Initialize(w *ecs.World) {
+	o.pollenStores = ecs.GetResource[globals.PollenStoresEtox](w)
+
+	o.data = make([][]float64, len(o.pollenStores.Mass))
+	for i := range o.data {
+		o.data[i] = make([]float64, len(o.Header()))
+	}
+}
+func (o *PollenCompartments) Update(w *ecs.World) {}
+func (o *PollenCompartments) Header() []string {
+	return []string{"Pollen", "Conc", "PPP"}
+}
+func (o *PollenCompartments) Values(w *ecs.World) [][]float64 {
+	for i, m := range o.pollenStores.Mass {
+		o.data[i][0] = m
+		o.data[i][1] = o.pollenStores.Conc[i]
+		o.data[i][2] = m * o.pollenStores.Conc[i]
+	}
+	return o.data
+}
@@ -7,6 +7,10 @@ import (
 
 // PPPFateObs is an observer that notes any and all flows of PPP once it has been picked up by
 // foragers. It was primarily used to create mass balances of PPP inside and outside of the hive.
+// Together with the degradation, loss and dissipation sinks and the PPP left in the stores, wax, treatments
+// and dilution water, the columns close the mass balance of the PPP input:
+// TotalPPForaged, PPPwaxInput, PPPtreatmentInput and PPPpollenExcess.
+// PPPpatchDegraded and PPPpatchCleared describe the residues on patches and are not part of it.
 //
 // For example use see _examples/beecs_ecotox/PPP_massbalance
 type PPPFateObs struct {
@@ -22,7 +26,12 @@ func (o *PPPFateObs) Initialize(w *ecs.World) {
 }
 func (o *PPPFateObs) Update(w *ecs.World) {}
 func (o *PPPFateObs) Header() []string {
-	return []string{"TotalPPForaged", "PPPhoneyStores", "PPPpollenStores", "PPPforagersImmediate", "PPPforagerDiedinFlight", "PPPforagersinHive", "PPPforagersTotal", "PPPIHbees", "PPPnurses", "PPPlarvae", "PPPdrones", "PPPdlarvae", "PPPpollenTotal", "PPPhoneyTotal", "PPPIHTotal"}
+	return []string{"TotalPPForaged", "PPPhoneyStores", "PPPpollenStores", "PPPforagersImmediate", "PPPforagerDiedinFlight", "PPPforagersinHive", "PPPforagersTotal", "PPPIHbees", "PPPnurses", "PPPlarvae", "PPPdrones", "PPPdlarvae", "PPPpollenTotal", "PPPhoneyTotal", "PPPIHTotal",
+		"PPPhoneyDegraded", "PPPpollenDegraded", "PPPstoresLost", "PPPdissipatedHSU", "PPPdissipatedNurses", "PPPpatchDegraded", "PPPpatchCleared",
+		"PPPwaterForaged", "PPPwaterEvaporated",
+		"PPPwaxInput", "PPPwaxToHoney", "PPPwaxToPollen", "PPPwaxToLarvae", "PPPwaxDegraded", "PPPwaxReplaced", "PPPwaxTotal",
+		"PPPtreatmentInput", "PPPtreatmentToHoney", "PPPtreatmentToWax", "PPPtreatmentContact", "PPPtreatmentDegraded", "PPPtreatmentTotal",
+		"PPPswarm", "PPPmanagement", "PPPpollenExcess", "PPPwaterTotal"}
 }
 func (o *PPPFateObs) Values(w *ecs.World) []float64 {
 	o.data[0] = o.pppfate.TotalPPPforaged
@@ -43,5 +52,32 @@ func (o *PPPFateObs) Values(w *ecs.World) []float64 {
 	o.data[13] = o.stores.PPPhoneyTotal
 	o.data[14] = o.stores.PPPTotal
 
+	o.data[15] = o.pppfate.PPPhoneyDegraded
+	o.data[16] = o.pppfate.PPPpollenDegraded
+	o.data[17] = o.pppfate.PPPstoresLost
+	o.data[18] = o.pppfate.PPPdissipatedHSU
+	o.data[19] = o.pppfate.PPPdissipatedNurses
+	o.data[20] = o.pppfate.PPPpatchDegraded
+	o.data[21] = o.pppfate.PPPpatchCleared
+	o.data[22] = o.pppfate.PPPwaterForaged
+	o.data[23] = o.pppfate.PPPwaterEvaporated
+	o.data[24] = o.pppfate.PPPwaxInput
+	o.data[25] = o.pppfate.PPPwaxToHoney
+	o.data[26] = o.pppfate.PPPwaxToPollen
+	o.data[27] = o.pppfate.PPPwaxToLarvae
+	o.data[28] = o.pppfate.PPPwaxDegraded
+	o.data[29] = o.pppfate.PPPwaxReplaced
+	o.data[30] = o.stores.PPPwax
+	o.data[31] = o.pppfate.PPPtreatmentInput
+	o.data[32] = o.pppfate.PPPtreatmentToHoney
+	o.data[33] = o.pppfate.PPPtreatmentToWax
+	o.data[34] = o.pppfate.PPPtreatmentContact
+	o.data[35] = o.pppfate.PPPtreatmentDegraded
+	o.data[36] = o.stores.PPPtreatment
+	o.data[37] = o.pppfate.PPPswarm
+	o.data[38] = o.pppfate.PPPmanagement
+	o.data[39] = o.pppfate.PPPpollenExcess
+	o.data[40] = o.stores.PPPwater
+
 	return o.data
 }
@@ -0,0 +1,35 @@
+package obs
+
+import (
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Queen is a row observer for the state of the queen.
+//
+// Columns are "QueenState" (see [github.com/fzeitner/Nursebeecs-master-thesis/enum/queen.State]), "QueenAge",
+// "QueenDose", "QueenFecundity", "QueenReplacements" and "EggsLaid" (worker and drone eggs laid today).
+type Queen struct {
+	queen *globals.Queen
+	eggs  *globals.Eggs
+	data  []float64
+}
+
+func (o *Queen) Initialize(w *ecs.World) {
+	o.queen = ecs.GetResource[globals.Queen](w)
+	o.eggs = ecs.GetResource[globals.Eggs](w)
+	o.data = make([]float64, len(o.Header()))
+}
+func (o *Queen) Update(w *ecs.World) {}
+func (o *Queen) Header() []string {
+	return []string{"QueenState", "QueenAge", "QueenDose", "QueenFecundity", "QueenReplacements", "EggsLaid"}
+}
+func (o *Queen) Values(w *ecs.World) []float64 {
+	o.data[0] = float64(o.queen.State)
+	o.data[1] = float64(o.queen.Age)
+	o.data[2] = o.queen.Dose
+	o.data[3] = o.queen.Fecundity
+	o.data[4] = float64(o.queen.Replacements)
+	o.data[5] = float64(o.eggs.Workers[0] + o.eggs.Drones[0])
+	return o.data
+}
@@ -0,0 +1,30 @@
+package obs
+
+import (
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// RiskEndpoints is a row observer for the colony endpoints of regulatory risk assessments,
+// for use with package [github.com/fzeitner/Nursebeecs-master-thesis/assessment].
+//
+// Columns are "ColonySize" (adult workers, in-hive and foragers), "Foragers" and "Brood" (worker and drone eggs, larvae and pupae).
+type RiskEndpoints struct {
+	stats *globals.PopulationStats
+	data  []float64
+}
+
+func (o *RiskEndpoints) Initialize(w *ecs.World) {
+	o.stats = ecs.GetResource[globals.PopulationStats](w)
+	o.data = make([]float64, len(o.Header()))
+}
+func (o *RiskEndpoints) Update(w *ecs.World) {}
+func (o *RiskEndpoints) Header() []string {
+	return []string{"ColonySize", "Foragers", "Brood"}
+}
+func (o *RiskEndpoints) Values(w *ecs.World) []float64 {
+	o.data[0] = float64(o.stats.WorkersInHive + o.stats.WorkersForagers)
+	o.data[1] = float64(o.stats.WorkersForagers)
+	o.data[2] = float64(o.stats.TotalBrood)
+	return o.data
+}
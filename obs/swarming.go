@@ -0,0 +1,31 @@
+package obs
+
+import (
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Swarming is a row observer for the swarming of the colony.
+//
+// Columns are "Swarms" (since the start of the simulation), and "SwarmBees", "SwarmHoney" [kJ] and "SwarmMites"
+// for the workers, the honey and the phoretic mites that left with a swarm today.
+type Swarming struct {
+	swarming *globals.Swarming
+	data     []float64
+}
+
+func (o *Swarming) Initialize(w *ecs.World) {
+	o.swarming = ecs.GetResource[globals.Swarming](w)
+	o.data = make([]float64, len(o.Header()))
+}
+func (o *Swarming) Update(w *ecs.World) {}
+func (o *Swarming) Header() []string {
+	return []string{"Swarms", "SwarmBees", "SwarmHoney", "SwarmMites"}
+}
+func (o *Swarming) Values(w *ecs.World) []float64 {
+	o.data[0] = float64(o.swarming.Swarms)
+	o.data[1] = float64(o.swarming.Bees)
+	o.data[2] = o.swarming.Honey
+	o.data[3] = float64(o.swarming.Mites)
+	return o.data
+}
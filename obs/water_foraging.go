@@ -0,0 +1,32 @@
+package obs
+
+import (
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// WaterForaging is a row observer for the daily water foraging of beecs_ecotox/nursebeecs_ecotox.
+//
+// Columns are "NeedCooling", "NeedDilution" and "Collected" [g], "Squadrons" and "PPPwater" [µg].
+// Requires water foraging to be enabled in the WaterForaging parameters.
+type WaterForaging struct {
+	stats *globals.WaterForagingStats
+	data  []float64
+}
+
+func (o *WaterForaging) Initialize(w *ecs.World) {
+	o.stats = ecs.GetResource[globals.WaterForagingStats](w)
+	o.data = make([]float64, len(o.Header()))
+}
+func (o *WaterForaging) Update(w *ecs.World) {}
+func (o *WaterForaging) Header() []string {
+	return []string{"NeedCooling", "NeedDilution", "Collected", "Squadrons", "PPPwater"}
+}
+func (o *WaterForaging) Values(w *ecs.World) []float64 {
+	o.data[0] = o.stats.NeedCooling
+	o.data[1] = o.stats.NeedDilution
+	o.data[2] = o.stats.Collected
+	o.data[3] = float64(o.stats.Squadrons)
+	o.data[4] = o.stats.PPP
+	return o.data
+}
@@ -46,6 +46,10 @@ type DefaultParams struct {
 	EnergyContent     EnergyContent
 	InitialStores     InitialStores
 	RandomSeed        RandomSeed
+	Queen             Queen
+	Swarming          Swarming
+	Management        Management
+	Varroa            Varroa
 }
 
 // Default returns the complete default parameter set of BEEHAVE.
@@ -206,6 +210,61 @@ func Default() DefaultParams {
 				},
 			},
 		},
+		Queen: Queen{
+			Ageing:     false,
+			InitialAge: 365, // [d]
+
+			LossProbability: 0.0,
+			SupersedureAge:  0,  // [d]
+			RearingTime:     12, // [d]
+			MaxLarvaAge:     3,  // [d]
+			MatingTime:      10, // [d]
+			MatingSuccess:   0.9,
+		},
+		Swarming: Swarming{
+			Enabled: false,
+
+			SeasonStart: 120, // [d]
+			SeasonEnd:   200, // [d]
+
+			BroodCongestion: 0.1,
+			AdultDensity:    0.1,
+			Probability:     0.2, // [1/d]
+
+			Fraction:       0.6,
+			HoneyLoad:      35.0, // [mg]
+			QueenEmergence: 7,    // [d]
+		},
+		Management: Management{
+			Events: []ManagementEvent{}, // No management by default
+			File:   "",
+		},
+		Varroa: Varroa{
+			Enabled:         false,
+			InitialMites:    100,
+			InitialInfected: 0.2,
+
+			MortalityBrood:     0.006, // [1/d]
+			MortalityBroodless: 0.002, // [1/d]
+
+			InvasionWorkers:  0.00007, // [1/cell]
+			InvasionDrones:   0.0008,  // [1/cell]
+			MaxPerWorkerCell: 4,
+			MaxPerDroneCell:  7,
+			OffspringWorkers: 1.0,
+			OffspringDrones:  2.2,
+
+			MiteToPupa:  0.9,
+			PupaToMite:  1.0,
+			MiteToAdult: 0.1, // [1/d]
+			AdultToMite: 1.0, // [1/d]
+
+			BroodMortality:   0.0,
+			MortalityAsPupa:  0.08, // [1/d]
+			MortalityAsAdult: 0.0,  // [1/d]
+
+			Treatments: []MiteTreatment{}, // No mite treatments by default
+		},
 	}
 }
 
@@ -267,4 +326,8 @@ func (p *DefaultParams) Apply(world *ecs.World) {
 	ecs.AddResource(world, &pCopy.InitialPopulation)
 	ecs.AddResource(world, &pCopy.InitialStores)
 	ecs.AddResource(world, &pCopy.InitialPatches)
+	ecs.AddResource(world, &pCopy.Queen)
+	ecs.AddResource(world, &pCopy.Swarming)
+	ecs.AddResource(world, &pCopy.Management)
+	ecs.AddResource(world, &pCopy.Varroa)
 }
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"os"
 
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/capping"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/dosedist"
 	"github.com/mlange-42/ark/ecs"
 )
 
@@ -26,6 +28,9 @@ type DefaultParamsEtox struct {
 	WaterForaging       WaterForaging
 	WaterForagingPeriod WaterForagingPeriod
 	PPPToxicity         PPPToxicity
+	MassBalance         MassBalance
+	Wax                 Wax
+	InHiveTreatments    InHiveTreatments
 }
 
 // DefaultEtox returns the complete default parameter set for beecs_ecotox. ReworkedThermoEtox, RealisticStoch and the two fixes are additions created by me.
@@ -42,6 +47,7 @@ func DefaultEtox() DefaultParamsEtox {
 			ReworkedThermoETOX: false, // Determines whether thermoregulation energy shall be taken in equally by all adult bees (True, new version) or if one cohort/squad shall take it all (false; Netlogo version)
 			Nursebeefix:        true,  // Determines whether the nurse bee intake from BEEHAVE_ecotox's nursebeefactors shall be added to IHbees instead of dissipating
 			HSUfix:             true,  // Determines if the PPP lost to the second call of HSuptake when unloading nectar shall be redirected to IHbees (true) insted of dissipating
+			PollenEmptyfix:     false, // Determines if bees can only eat the PPP left in the pollen store on the day it runs empty; off to keep the results of BEEHAVE_ecotox
 
 			PPPname:                "No applications", // Identifier for the PPP used.
 			PPPconcentrationNectar: 990,
@@ -57,6 +63,15 @@ func DefaultEtox() DefaultParamsEtox {
 			DT50honey:      60.,   // Honey DT50 [d]
 
 			ETOXDensityOfHoney: 1.4, // [kg/l]
+
+			HoneyUncappedDays: 5,                 // Number of daily compartments of uncapped honey before it gets capped [d]
+			HoneyCapping:      capping.AfterDays, // Uncapped honey is capped as a whole after HoneyUncappedDays
+			HoneyOldestFirst:  false,             // Uncapped honey is eaten newest first, as in BEEHAVE_ecotox
+
+			AccountingTolerance: 0.001, // Relative deviation of the etox stores from the beecs stores that is reconciled silently
+
+			PollenCompartments: false, // Determines whether pollen stores are tracked in daily age compartments
+			PollenStoreDays:    14,    // Number of age compartments of the pollen stores [d]
 		},
 		PPPToxicity: PPPToxicity{
 			ForagerOralLD50:  1000., // Lethal oral dose for 50% mortality of foragers [µg/bee].
@@ -67,15 +82,55 @@ func DefaultEtox() DefaultParamsEtox {
 			ForagerContactSlope: 1.08, // Slope of the dose-response relationship (forager, contact) [ ]
 
 			LarvaeOralLD50:  0.0014, // Lethal oral dose for 50% mortality of larvae [µg/larvae]
-			LarvaeOralSlope: 1.6,    // Slope of the dose-response relationship (larvae, oral) [ ]; A log-normal dose-response curve is implemented
+			LarvaeOralSlope: 1.6,    // Slope of the dose-response relationship (larvae, oral) [ ]
+
+			CohortLethalCutoff: 0.99, // Cohort mortality fraction above which the whole cohort dies [ ]
+
+			DoseDistribution: dosedist.Mean, // All individuals of a cohort get the mean cohort dose, as in BEEHAVE_ecotox
+			DoseCV:           0.5,           // Coefficient of variation of individual doses, only used with the LogNormal dose distribution [ ]
+			DoseBins:         20,            // Number of bins the dose distribution is integrated over
+
+			ChronicMortality:    false,  // Determines whether chronic oral endpoints are applied to larvae and in-hive cohorts
+			ChronicOnly:         false,  // Determines whether chronic endpoints replace the acute oral check
+			AdultChronicLDD50:   1000.,  // Placeholder far above any realistic daily dose, so chronic adult mortality is effectively disabled until a substance-specific LDD50 is set [µg/bee/d]
+			AdultChronicSlope:   100.,   // Placeholder; the steep slope keeps the chronic adult mortality at zero below the placeholder LDD50 [ ]
+			ChronicWindowAdults: 10,     // Duration of the OECD 245 chronic adult test [d]
+			LarvaeChronicLD50:   0.0014, // Placeholder copied from the acute LarvaeOralLD50, not a 22-day endpoint; set a substance-specific cumulative LD50 before using chronic larval mortality [µg/larva]
+			LarvaeChronicSlope:  1.6,    // Placeholder copied from the acute LarvaeOralSlope [ ]
+			ChronicWindowLarvae: 7,      // Covers the larval feeding period of workers (6 d) and drones (7 d) [d]
+
+			SublethalForagers:   false,  // Determines whether sublethal effects on foragers are applied
+			SublethalHalfLife:   1.,     // Half-life of the recent dose of forager squadrons [d]
+			HomingFailureED50:   0.0045, // Illustrative value in the range of neonicotinoid homing studies, e.g. Henry et al. 2012 [µg/bee]
+			HomingFailureSlope:  1.,     // Slope of the homing failure dose-response relationship [ ]
+			HandlingED50:        0.0045, // Recent dose at which handling times are prolonged by half of HandlingMaxIncrease [µg/bee]
+			HandlingSlope:       1.,     // Slope of the handling time dose-response relationship [ ]
+			HandlingMaxIncrease: 1.,     // Handling times double at full effect [ ]
+
+			SublethalLarvae:       false,  // Determines whether sublethal effects on larvae are applied
+			DevelopmentDelayED50:  0.0014, // Illustrative value at the larval LD50 [µg/larva]
+			DevelopmentDelaySlope: 1.6,    // Slope of the development delay dose-response relationship [ ]
+			DevelopmentDelayMax:   3.,     // Mean delay of emergence at full effect [d]
+			PupalFailureED50:      0.0014, // Illustrative value at the larval LD50 [µg/larva]
+			PupalFailureSlope:     1.6,    // Slope of the pupal failure dose-response relationship [ ]
+			LongevityED50:         0.0014, // Illustrative value at the larval LD50 [µg/larva]
+			LongevitySlope:        1.6,    // Slope of the longevity dose-response relationship [ ]
+			LongevityMaxReduction: 0.5,    // Adult lifespan is halved at full effect [ ]
 
 			NursebeesNectar: 0.25, // Factor describing the filter effect of nurse bees for nectar [ ]
 			NursebeesPollen: 1.,   // Factor describing the filter effect of nurse bees for pollen [ ]
 
-			HGthreshold: []float64{0.000727 / 12.78, 0.000727 / 12.78 * 10, 0.000727 / 12.78 * 100}, // authors used 1 mug/L Clothianidin in 400 ml of Apiinvert; Apiinvert has 1 kg of sugar per Liter and a concentration of 72.7% (https://www.beefeed.com/en/apiinvert/) --> 1 l of Apiinvert should be equivalkent to 1/0.727 = 1.376 kg
-			// therefore 1 mug/L (w/v) should be equivalent to 0.727 mug/kg (w/w) in apiinvert; 0.727 mug/kg = 0.727 ng/g = 0.000727 mug/g; BEEHAVE needs values in weight per kJ, therefore we need to consider honey energy content of 12.78 kJ/g
-			ProteinFactorNurseExposed: []float64{0.82, 0.77, 0.}, // very much experimental; straight up taken from Schott et al. 2021
-			MaxPollenRed:              []float64{0.9, 0.8, 0.25}, // very much experimental and just a guess for now, needs to be calibrated probperly for each PPP
+			HPGInhibitionED50:  0.0002, // roughly 10 µg/L clothianidin in the diet of nurses eating about 30 mg per day, fitted loosely to Schott et al. 2021 [µg/bee/d]
+			HPGInhibitionSlope: 1.,     // Slope of the HPG inhibition dose-response relationship [ ]
+			HPGMaxInhibition:   1.,     // Glands can be reduced completely at very high doses, as observed at 100 µg/L by Schott et al. 2021 [ ]
+
+			QueenEffects:         false, // Determines whether PPP effects on the queen are applied
+			QueenFoodIntake:      150.,  // Illustrative intake of royal jelly by a laying queen [mg/d]
+			LayingReductionED50:  0.003, // Illustrative value, roughly 20 µg/kg imidacloprid in the food of the queen, in the range of Wu-Smart & Spivak 2016 [µg/d]
+			LayingReductionSlope: 1.,    // Slope of the egg laying dose-response relationship [ ]
+			LayingMaxReduction:   0.8,   // Egg laying is reduced by 80% at full effect [ ]
+			QueenOralLD50:        0.1,   // Illustrative value well above the egg laying effect [µg/d]
+			QueenOralSlope:       2.,    // Slope of the queen mortality dose-response relationship [ ]
 		},
 		WaterForaging: WaterForaging{
 			WaterForaging:             false,       // Determines whether water foraging takes place or not.
@@ -88,6 +143,28 @@ func DefaultEtox() DefaultParamsEtox {
 			Builtin:     true,
 			RandomYears: false,
 		},
+		MassBalance: MassBalance{
+			File:    "",   // No mass balance tables are written by default
+			Epsilon: 1e-6, // Far below any relevant dose, but above the floating point error of the PPP sums [µg]
+		},
+		Wax: Wax{
+			WaxCompartment: false, // Determines whether the comb wax is simulated as a PPP compartment
+			Mass:           1000., // Roughly the wax of ten drawn brood combs [g]
+			InitialResidue: 0.,    // Clean wax at the start of the run [µg/kg]
+
+			PartitionHoney:  100., // Lipophilic actives strongly prefer wax over honey [ ]
+			PartitionPollen: 10.,  // Pollen holds more lipids than honey [ ]
+			PartitionLarvae: 10.,  // Larvae are exposed via the cell walls [ ]
+			ExchangeRate:    0.01, // Slow leaching, equilibrium is reached within months [1/d]
+			LarvalMass:      80.,  // Mean mass of worker larvae over their development [mg]
+
+			DT50:              0.,  // Residues in wax are very persistent [d]
+			CombReplacement:   0.3, // Beekeepers replace about a third of the combs per year [1/y]
+			FoundationResidue: 0.,  // Clean foundation wax [µg/kg]
+		},
+		InHiveTreatments: InHiveTreatments{
+			Treatments: []Treatment{}, // No in-hive treatments by default
+		},
 	}
 }
 
@@ -124,4 +201,7 @@ func (p *DefaultParamsEtox) Apply(world *ecs.World) {
 	ecs.AddResource(world, &pCopy.PPPApplication)
 	ecs.AddResource(world, &pCopy.PPPToxicity)
 	ecs.AddResource(world, &pCopy.WaterForaging)
+	ecs.AddResource(world, &pCopy.MassBalance)
+	ecs.AddResource(world, &pCopy.Wax)
+	ecs.AddResource(world, &pCopy.InHiveTreatments)
 }
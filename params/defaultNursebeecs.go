@@ -24,6 +24,7 @@ type ParamsNursebeecs interface {
 type DefaultParamsNursebeecs struct {
 	ConsumptionRework ConsumptionRework
 	NursingRework     NursingRework
+	HPGDevelopment    HPGDevelopment
 }
 
 // DefaultNursebeecs returns the complete default parameter set of nursebeecs.
@@ -71,8 +72,16 @@ func DefaultNursebeecs() DefaultParamsNursebeecs {
 			Nursebeecsv1:           true,
 			ForesightedCannibalism: false,
 
-			HGEffects:    false,
-			HGFoodIntake: false,
+			HGEffects: false,
+		},
+		HPGDevelopment: HPGDevelopment{
+			PeakStart:          6,   // glands are fully developed after about 6 days (Hrassnigg & Crailsheim 1998)
+			PeakEnd:            12,  // and remain fully developed during the nursing period
+			AtrophyDays:        10,  // before they shrink when workers shift to other tasks
+			Residual:           0.3, // older IHbees keep small glands
+			GrowthRate:         0.5, // daily relaxation towards the target size when growing or recovering
+			AtrophyRate:        0.3, // daily relaxation towards the target size when shrinking
+			NutritionThreshold: 0.5, // ProteinFactorNurses above which nutrition does not limit gland size
 		},
 	}
 }
@@ -108,4 +117,5 @@ func (p *DefaultParamsNursebeecs) Apply(world *ecs.World) {
 	// Resources
 	ecs.AddResource(world, &pCopy.ConsumptionRework)
 	ecs.AddResource(world, &pCopy.NursingRework)
+	ecs.AddResource(world, &pCopy.HPGDevelopment)
 }
@@ -3,8 +3,12 @@ package params
 import (
 	"bytes"
 	"encoding/json"
+	"math"
+	"os"
+	"slices"
 
 	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/management"
 )
 
 // WorkingDirectory for file I/O.
@@ -191,6 +195,146 @@ type InitialPatches struct {
 	File    string             // File to read patches from. Applied after creating Patches.
 }
 
+// Queen parameters for queen ageing, queen loss and replacement.
+//
+// By default, the queen neither ages nor gets lost, as in BEEHAVE.
+// After the loss of the queen, the colony rears an emergency queen if it has young worker brood.
+// The colony is broodless until the new queen has emerged, mated and started laying.
+type Queen struct {
+	Ageing     bool // Whether the maximum egg laying rate depends on the age of the queen, as with QueenAgeing in BEEHAVE.
+	InitialAge int  // Age of the queen at the start of the simulation [d].
+
+	LossProbability float64 // Daily probability of losing the laying queen from background causes [1/d].
+	SupersedureAge  int     // Age of the queen from which the colony rears a new queen to supersede her; 0 = no supersedure [d].
+	RearingTime     int     // Time from the start of rearing until the new queen emerges [d].
+	MaxLarvaAge     int     // Maximum age of worker larvae an emergency queen can be reared from [d].
+	MatingTime      int     // Time from the emergence of a new queen until she is mated and starts laying [d].
+	MatingSuccess   float64 // Probability that a new queen mates successfully if there are drones; the colony becomes hopelessly queenless otherwise [ ].
+}
+
+// MaxEggs returns the maximum egg laying rate of a queen of the given age [eggs/d].
+// With ageing, the rate changes with the age of the queen as in BEEHAVE.
+func (q *Queen) MaxEggs(maxEggsPerDay int, age int) float64 {
+	if !q.Ageing {
+		return float64(maxEggsPerDay)
+	}
+	a := float64(age)
+	return math.Max(float64(maxEggsPerDay)-0.0027*a*a+0.395*a, 0)
+}
+
+// Swarming parameters for the reproductive swarming of the colony.
+//
+// The colony swarms during the swarming season if the brood nest is congested, i.e. if both the brood
+// and the adult population reach the given fractions of [Nursing] MaxBroodCells, and the queen is laying.
+// The old queen leaves with the swarm, together with a fraction of the worker population and the honey they carry.
+// The parent colony is left with the queen cells started before swarming, see [Queen].
+type Swarming struct {
+	Enabled bool // Whether colonies can swarm.
+
+	SeasonStart int // First day of the year on which the colony can swarm [d].
+	SeasonEnd   int // Last day of the year on which the colony can swarm [d].
+
+	BroodCongestion float64 // Minimum number of brood cells for swarming, as a fraction of MaxBroodCells [ ].
+	AdultDensity    float64 // Minimum number of adult bees for swarming, as a fraction of MaxBroodCells [ ].
+	Probability     float64 // Daily probability of swarming while the colony is congested [1/d].
+
+	Fraction       float64 // Fraction of in-hive workers and foragers leaving with the swarm [ ].
+	HoneyLoad      float64 // Honey taken along per swarming bee [mg].
+	QueenEmergence int     // Time from swarming until the first new queen emerges in the parent colony [d].
+}
+
+// Management parameters for beekeeper management actions on a schedule, like honey harvest, feeding or colony splits.
+//
+// Events read from File are appended to the events given directly. Feed is free of PPP.
+type Management struct {
+	Events []ManagementEvent // Management events; none by default.
+	File   string            // JSON file with a list of further management events, relative to the working directory. Optional.
+}
+
+// ManagementEvent parameters of a single beekeeper management action.
+type ManagementEvent struct {
+	Name   string            // Name of the event, for output only.
+	Action management.Action // Type of the action.
+	Day    int               // Day of the year on which the action takes place [d].
+	Years  []int             // Simulation years in which the action takes place, starting at 0; every year if empty.
+	Amount float64           // Amount of the action; the meaning and unit depend on the action, see [management.Action].
+}
+
+// Due returns whether the event takes place in the given tick.
+func (e *ManagementEvent) Due(tick int64) bool {
+	if int(tick%365) != e.Day {
+		return false
+	}
+	if len(e.Years) == 0 {
+		return true
+	}
+	return slices.Contains(e.Years, int(tick/365))
+}
+
+// ManagementEventsFromFile reads a list of management events from a JSON file.
+func ManagementEventsFromFile(path string) ([]ManagementEvent, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []ManagementEvent
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Varroa parameters of the mite and virus module, after BEEHAVE.
+//
+// Phoretic mites invade worker and drone cells about to be capped, reproduce in the capped brood
+// and are released with their offspring when the brood emerges. Mites transmit a virus to the pupae they feed on
+// and to adult bees; the virus causes the mite-induced brood and adult mortality.
+// Defaults are for deformed wing virus (DWV). For acute paralysis virus (APV), use
+// BroodMortality 1 and MortalityAsAdult of about 0.2.
+type Varroa struct {
+	Enabled         bool    // Whether to simulate Varroa mites and virus transmission.
+	InitialMites    int     // Initial number of phoretic mites.
+	InitialInfected float64 // Initial fraction of phoretic mites carrying the virus [ ].
+
+	MortalityBrood     float64 // Daily mortality of phoretic mites while there is brood [1/d].
+	MortalityBroodless float64 // Daily mortality of phoretic mites in broodless periods [1/d].
+
+	InvasionWorkers  float64 // Invasion rate of phoretic mites into worker cells, per cell capped today [1/cell].
+	InvasionDrones   float64 // Invasion rate of phoretic mites into drone cells, per cell capped today [1/cell].
+	MaxPerWorkerCell int     // Maximum mites per worker cell; surplus invaders remain phoretic.
+	MaxPerDroneCell  int     // Maximum mites per drone cell; surplus invaders remain phoretic.
+	OffspringWorkers float64 // Mean number of mated daughters per mother mite in a worker cell.
+	OffspringDrones  float64 // Mean number of mated daughters per mother mite in a drone cell.
+
+	MiteToPupa  float64 // Probability that a mite carrying the virus infects the pupa of its cell [ ].
+	PupaToMite  float64 // Probability that a mite in the cell of an infected pupa acquires the virus [ ].
+	MiteToAdult float64 // Daily probability that a phoretic mite carrying the virus infects a healthy in-hive bee [1/d].
+	AdultToMite float64 // Daily probability that a healthy phoretic mite on an infected in-hive bee acquires the virus [1/d].
+
+	BroodMortality   float64 // Probability that an infected pupa dies before emergence [ ].
+	MortalityAsPupa  float64 // Additional daily mortality of adults infected as pupae [1/d].
+	MortalityAsAdult float64 // Additional daily mortality of adults infected as adults [1/d].
+
+	Treatments []MiteTreatment // Mite treatments by the beekeeper, applied every year; none by default.
+}
+
+// MiteTreatment parameters of a treatment against Varroa mites.
+type MiteTreatment struct {
+	Name         string  // Name of the treatment, for output only.
+	Start        int     // First day of the treatment [day of year].
+	End          int     // Last day of the treatment [day of year].
+	Efficacy     float64 // Fraction of the phoretic mites killed per day of the treatment [1/d].
+	CellEfficacy float64 // Fraction of the mites in capped brood cells killed per day, e.g. for formic acid [1/d].
+}
+
+// Active returns whether the treatment is applied on the given day of the year.
+func (t *MiteTreatment) Active(dayOfYear int) bool {
+	return dayOfYear >= t.Start && dayOfYear <= t.End
+}
+
 // initialPatchesHelper is used to unmarshal the InitialPatches struct from JSON,
 // properly overwriting the default patches.
 type initialPatchesHelper struct {
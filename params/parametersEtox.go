@@ -1,5 +1,17 @@
 package params
 
+import (
+	"math"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/capping"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/dosedist"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/doseresponse"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/drift"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/kinetics"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/treatment"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
+)
+
 // parameters for the application of pesticides.
 type PPPApplication struct {
 	Application               bool // Determines if there is an application at all at any point in the model and if the _ecotox-module should be turned on for all purposes
@@ -11,6 +23,7 @@ type PPPApplication struct {
 	ReworkedThermoETOX        bool // Determines whether thermoregulation energy shall be taken in equally by all adult bees (True, new version) or if one cohort/squad shall take it all (false; Netlogo version)
 	Nursebeefix               bool // Determines if the nurse bee intake from BEEHAVE_ecotox's nursebeefactors shall be added to IHbees instead of dissipating
 	HSUfix                    bool // Determines if the PPP lost to the second call of HSuptake when unloading nectar shall be redirected to IHbees (true) insted of dissipating
+	PollenEmptyfix            bool // Determines if bees can only eat the PPP left in the single pollen store on the day it runs empty (true) instead of the concentration of the day before (false, as in BEEHAVE_ecotox)
 
 	PPPname                string  // Identifier for the PPP used.
 	PPPconcentrationNectar float64 // PPP concentration in nectar [mug/kg]
@@ -26,29 +39,300 @@ type PPPApplication struct {
 	DT50honey      float64 // Honey DT50 [d].
 
 	ETOXDensityOfHoney float64 // The density of honey is 1.4 [kg/l].
+
+	HoneyUncappedDays int          // Number of daily compartments of uncapped honey before it gets capped; values below 1 use 5, as in BEEHAVE_ecotox [d].
+	HoneyCapping      capping.Rule // Rule for moving uncapped honey into the capped cells; capping after HoneyUncappedDays by default.
+	HoneyCappingRate  float64      // Fraction of each uncapped honey compartment that gets capped per day, with the DailyFraction capping rule [1/d].
+	HoneyOldestFirst  bool         // Determines whether uncapped honey is eaten oldest first instead of newest first (BEEHAVE_ecotox); capped honey is always eaten last.
+
+	AccountingTolerance float64 // Relative deviation of the etox stores from the beecs stores that is reconciled silently; larger deviations raise an accounting event. Values <= 0 use 0.001 [ ].
+
+	PollenCompartments bool // Determines whether pollen stores are tracked in daily age compartments with oldest-first consumption instead of one well-mixed store.
+	PollenStoreDays    int  // Number of age compartments of the pollen stores; older pollen is pooled in the last compartment [d].
+
+	NectarKinetics      DegradationKinetics // Degradation of residues in nectar on patches; the zero value falls back to SFO with DT50.
+	PollenKinetics      DegradationKinetics // Degradation of residues in pollen on patches; the zero value falls back to SFO with DT50.
+	ContactKinetics     DegradationKinetics // Degradation of contact residues on patches; the zero value falls back to SFO with DT50.
+	HoneyKinetics       DegradationKinetics // Degradation of residues in honey stores if DegradationHoney is true; the zero value falls back to SFO with DT50honey.
+	PollenStoreKinetics DegradationKinetics // Degradation of residues in pollen stores (bee bread); the zero value means no degradation, as in BEEHAVE_ecotox.
+
+	DriftCurve drift.Curve  // Drift curve scaling the residues and contact exposure of patches by their distance to the treated field; no drift gradient by default.
+	DriftTable [][2]float64 // Drift deposition for the Table drift curve; rows of distance [m] and fraction of the in-field deposition [ ], in strictly ascending distance order.
+}
+
+// UncappedDays returns the number of daily compartments of uncapped honey.
+func (a *PPPApplication) UncappedDays() int {
+	if a.HoneyUncappedDays < 1 {
+		return 5
+	}
+	return a.HoneyUncappedDays
+}
+
+// CappingFraction returns the fraction of each uncapped honey compartment that gets capped per day.
+func (a *PPPApplication) CappingFraction() float64 {
+	if a.HoneyCapping == capping.DailyFraction {
+		return a.HoneyCappingRate
+	}
+	return 0
+}
+
+// Drift returns the drift curve for patches outside of the treated field.
+func (a *PPPApplication) Drift() util.DriftCurve {
+	return util.NewDriftCurve(a.DriftCurve, a.DriftTable)
+}
+
+// Tolerance returns the relative tolerance of the store accounting.
+func (a *PPPApplication) Tolerance() float64 {
+	if a.AccountingTolerance <= 0 {
+		return 0.001
+	}
+	return a.AccountingTolerance
+}
+
+// DegradationKinetics parameters of the residue degradation in one matrix.
+type DegradationKinetics struct {
+	Model kinetics.Model // Kinetics model family; SFO by default.
+	DT50  float64        // Half-life for SFO kinetics; 0 = use the fallback of the matrix [d].
+	Alpha float64        // Shape parameter alpha for FOMC kinetics [ ].
+	Beta  float64        // Location parameter beta for FOMC kinetics [d].
+	G     float64        // Fraction of the residue in the fast compartment for DFOP kinetics [ ].
+	K1    float64        // Rate constant of the fast compartment for DFOP kinetics [1/d].
+	K2    float64        // Rate constant of the slow compartment for DFOP kinetics [1/d].
+}
+
+// Kinetics returns the degradation model.
+// For SFO without a DT50, the given fallback DT50 is used; a fallback of 0 means no degradation.
+func (k *DegradationKinetics) Kinetics(fallbackDT50 float64) util.Kinetics {
+	DT50 := k.DT50
+	if k.Model == kinetics.SFO && DT50 <= 0 {
+		DT50 = fallbackDT50
+	}
+	return util.NewKinetics(k.Model, DT50, k.Alpha, k.Beta, k.G, k.K1, k.K2)
 }
 
 // parameters for uptake and toxicity of the applied pesticide to foragers and cohorts.
 type PPPToxicity struct {
-	ForagerOralLD50  float64 // Lethal oral dose for 50% mortality of foragers [µg/bee].
-	ForagerOralSlope float64 // Slope of the dose-response relationship (forager, oral) [ ].
-	HSuptake         float64 // Uptake of a given percentage of ai in the honey stomach by the forager bees
+	ForagerOralLD50  float64            // Lethal oral dose for 50% mortality of foragers [µg/bee].
+	ForagerOralSlope float64            // Slope of the dose-response relationship (forager, oral) [ ].
+	ForagerOralModel doseresponse.Model // Dose-response model family (forager, oral); log-logistic by default.
+	ForagerOralNOEL  float64            // No observed effect level (forager, oral); doses at or below cause no mortality, 0 = no threshold [µg/bee].
+	HSuptake         float64            // Uptake of a given percentage of ai in the honey stomach by the forager bees
+
+	ForagerContactLD50  float64            // Lethal dose for 50% of foragers via contact exposure [µg/bee].
+	ForagerContactSlope float64            // Slope of the dose-response relationship (forager, contact) [ ].
+	ForagerContactModel doseresponse.Model // Dose-response model family (forager, contact); log-logistic by default.
+	ForagerContactNOEL  float64            // No observed effect level (forager, contact); 0 = no threshold [µg/bee].
+
+	LarvaeOralLD50  float64            // Lethal oral dose for 50% mortality of larvae [µg/larvae].
+	LarvaeOralSlope float64            // Slope of the dose-response relationship (larvae, oral) [ ].
+	LarvaeOralModel doseresponse.Model // Dose-response model family (larvae, oral); log-logistic by default.
+	LarvaeOralNOEL  float64            // No observed effect level (larvae, oral); 0 = no threshold [µg/larvae].
+
+	InHiveOralLD50  float64            // Lethal oral dose for 50% mortality of in-hive adults [µg/bee]; 0 = use the forager oral endpoint, as in BEEHAVE_ecotox.
+	InHiveOralSlope float64            // Slope of the dose-response relationship (in-hive adults, oral) [ ].
+	InHiveOralModel doseresponse.Model // Dose-response model family (in-hive adults, oral); log-logistic by default.
+	InHiveOralNOEL  float64            // No observed effect level (in-hive adults, oral); 0 = no threshold [µg/bee].
 
-	ForagerContactLD50  float64 // Lethal dose for 50% of foragers via contact exposure [µg/bee].
-	ForagerContactSlope float64 // Slope of the dose-response relationship (forager, contact) [ ].
+	CohortLethalCutoff float64 // Cohort mortality fraction above which the whole cohort dies, as in the NetLogo version; 0 = 0.99 [ ].
 
-	LarvaeOralLD50  float64 // Lethal oral dose for 50% mortality of larvae [µg/larvae].
-	LarvaeOralSlope float64 // Slope of the dose-response relationship (larvae, oral) [ ]; A log-normal dose-response curve is implemented.
+	DoseDistribution dosedist.Distribution // Distribution of individual doses within larvae and in-hive cohorts (incl. nurses); all individuals get the mean cohort dose by default, as in BEEHAVE_ecotox.
+	DoseCV           float64               // Coefficient of variation of individual doses within a cohort, for the LogNormal dose distribution [ ].
+	DoseBins         int                   // Number of equiprobable bins the dose distribution is integrated over; values < 1 use 20.
+
+	ChronicMortality bool // Determines whether chronic oral endpoints are applied to larvae and in-hive cohorts.
+	ChronicOnly      bool // Determines whether chronic endpoints replace the acute oral check for larvae and in-hive cohorts (true) or are applied in addition to it (false).
+
+	AdultChronicLDD50   float64            // Lethal daily dose for 50% mortality of adults in a chronic test, e.g. the 10-day test [µg/bee/d].
+	AdultChronicSlope   float64            // Slope of the chronic dose-response relationship (adults) [ ].
+	AdultChronicModel   doseresponse.Model // Dose-response model family (adults, chronic); log-logistic by default.
+	AdultChronicNOEDD   float64            // No observed effect daily dose (adults, chronic); 0 = no threshold [µg/bee/d].
+	ChronicWindowAdults int                // Sliding window over which the mean daily dose of in-hive adults is calculated; also the test duration the LDD50 refers to [d].
+
+	LarvaeChronicLD50   float64            // Lethal cumulative dose for 50% mortality of larvae in the 22-day repeated exposure test [µg/larva].
+	LarvaeChronicSlope  float64            // Slope of the chronic dose-response relationship (larvae) [ ].
+	LarvaeChronicModel  doseresponse.Model // Dose-response model family (larvae, chronic); log-logistic by default.
+	LarvaeChronicNOEDD  float64            // No observed effect dose of the 22-day larval test; 0 = no threshold [µg/larva].
+	ChronicWindowLarvae int                // Sliding window over which the cumulative dose of larvae is calculated; should cover the larval feeding period [d].
+
+	SublethalForagers   bool               // Determines whether sublethal effects of the recent dose (homing failure, slower handling) are applied to foragers.
+	SublethalHalfLife   float64            // Half-life of the recent dose of forager squadrons; the doses of past days decay with it, 0 = only the current day counts [d].
+	HomingFailureED50   float64            // Recent dose at which 50% of foraging trips end in homing failure; 0 = no homing failure [µg/bee].
+	HomingFailureSlope  float64            // Slope of the homing failure dose-response relationship [ ].
+	HomingFailureModel  doseresponse.Model // Dose-response model family of homing failure; log-logistic by default.
+	HandlingED50        float64            // Recent dose at which handling times are prolonged by half of HandlingMaxIncrease; 0 = no effect [µg/bee].
+	HandlingSlope       float64            // Slope of the handling time dose-response relationship [ ].
+	HandlingModel       doseresponse.Model // Dose-response model family of the handling time effect; log-logistic by default.
+	HandlingMaxIncrease float64            // Relative increase of handling times at full effect; 1 doubles them [ ].
+
+	SublethalLarvae       bool               // Determines whether sublethal effects of the cumulative larval dose (development delay, pupal failure, reduced longevity) are applied.
+	DevelopmentDelayED50  float64            // Cumulative larval dose at which emergence is delayed by half of DevelopmentDelayMax; 0 = no delay [µg/larva].
+	DevelopmentDelaySlope float64            // Slope of the development delay dose-response relationship [ ].
+	DevelopmentDelayModel doseresponse.Model // Dose-response model family of the development delay; log-logistic by default.
+	DevelopmentDelayMax   float64            // Mean delay of emergence at full effect [d].
+	PupalFailureED50      float64            // Cumulative larval dose at which 50% of pupae fail to develop into adults; 0 = no pupal failure [µg/larva].
+	PupalFailureSlope     float64            // Slope of the pupal failure dose-response relationship [ ].
+	PupalFailureModel     doseresponse.Model // Dose-response model family of pupal failure; log-logistic by default.
+	LongevityED50         float64            // Cumulative larval dose at which the adult lifespan is reduced by half of LongevityMaxReduction; 0 = no effect [µg/larva].
+	LongevitySlope        float64            // Slope of the longevity dose-response relationship [ ].
+	LongevityModel        doseresponse.Model // Dose-response model family of the longevity effect; log-logistic by default.
+	LongevityMaxReduction float64            // Relative reduction of the adult lifespan at full effect, in [0, 1) [ ].
 
 	NursebeesNectar float64 // Factor describing the filter effect of nurse bees for nectar [ ].
 	NursebeesPollen float64 // Factor describing the filter effect of nurse bees for pollen [ ].
 
-	HGthreshold               []float64 // threshold value of PPP necessary in honey to activate the HPG effects
-	ProteinFactorNurseExposed []float64 // new ceiling for ProteinFactorNurses in case HPG effects are turned on and the threshold is exceeded}
-	MaxPollenRed              []float64 // factors to reduce MaxPollenIntake by depending on HPGthresholds; needs to be calibrated
+	HPGInhibitionED50  float64            // Daily oral dose of IHbees at which their target HPG size is reduced by half of HPGMaxInhibition; 0 = no effect [µg/bee/d].
+	HPGInhibitionSlope float64            // Slope of the HPG inhibition dose-response relationship [ ].
+	HPGInhibitionModel doseresponse.Model // Dose-response model family of HPG inhibition; log-logistic by default.
+	HPGMaxInhibition   float64            // Relative reduction of the target HPG size at full effect, in [0, 1] [ ].
+
+	QueenEffects         bool               // Determines whether PPP effects on the queen (reduced egg laying, queen loss) are applied; see [Queen].
+	QueenFoodIntake      float64            // Daily intake of food processed by nurses by the laying queen; the food is composed like the food for worker larvae [mg/d].
+	LayingReductionED50  float64            // Daily oral dose of the queen at which her egg laying is reduced by half of LayingMaxReduction; 0 = no effect [µg/d].
+	LayingReductionSlope float64            // Slope of the egg laying dose-response relationship [ ].
+	LayingReductionModel doseresponse.Model // Dose-response model family of the egg laying effect; log-logistic by default.
+	LayingMaxReduction   float64            // Relative reduction of egg laying at full effect, in [0, 1] [ ].
+	QueenOralLD50        float64            // Daily oral dose of the queen for a 50% probability of losing her that day; 0 = no queen mortality [µg/d].
+	QueenOralSlope       float64            // Slope of the queen mortality dose-response relationship [ ].
+	QueenOralModel       doseresponse.Model // Dose-response model family of queen mortality; log-logistic by default.
+}
+
+// ForagerOralResponse returns the dose-response relationship for oral exposure of foragers.
+func (t *PPPToxicity) ForagerOralResponse() util.DoseResponse {
+	return util.NewDoseResponse(t.ForagerOralModel, t.ForagerOralLD50, t.ForagerOralSlope, t.ForagerOralNOEL)
+}
+
+// ForagerContactResponse returns the dose-response relationship for contact exposure of foragers.
+func (t *PPPToxicity) ForagerContactResponse() util.DoseResponse {
+	return util.NewDoseResponse(t.ForagerContactModel, t.ForagerContactLD50, t.ForagerContactSlope, t.ForagerContactNOEL)
+}
+
+// LarvaeOralResponse returns the dose-response relationship for oral exposure of larvae.
+func (t *PPPToxicity) LarvaeOralResponse() util.DoseResponse {
+	return util.NewDoseResponse(t.LarvaeOralModel, t.LarvaeOralLD50, t.LarvaeOralSlope, t.LarvaeOralNOEL)
+}
+
+// InHiveOralResponse returns the dose-response relationship for oral exposure of in-hive adults.
+// Falls back to the forager oral endpoint if no separate in-hive LD50 is given.
+func (t *PPPToxicity) InHiveOralResponse() util.DoseResponse {
+	if t.InHiveOralLD50 <= 0 {
+		return t.ForagerOralResponse()
+	}
+	return util.NewDoseResponse(t.InHiveOralModel, t.InHiveOralLD50, t.InHiveOralSlope, t.InHiveOralNOEL)
+}
+
+// AdultChronicResponse returns the chronic dose-response relationship for in-hive adults,
+// relating the mean daily dose to mortality over the test duration.
+func (t *PPPToxicity) AdultChronicResponse() util.DoseResponse {
+	return util.NewDoseResponse(t.AdultChronicModel, t.AdultChronicLDD50, t.AdultChronicSlope, t.AdultChronicNOEDD)
+}
+
+// LarvaeChronicResponse returns the chronic dose-response relationship for larvae,
+// relating the cumulative dose to mortality over the test duration.
+func (t *PPPToxicity) LarvaeChronicResponse() util.DoseResponse {
+	return util.NewDoseResponse(t.LarvaeChronicModel, t.LarvaeChronicLD50, t.LarvaeChronicSlope, t.LarvaeChronicNOEDD)
+}
+
+// HomingFailureResponse returns the probability of a forager squadron to fail to return from a trip,
+// depending on its recent dose.
+func (t *PPPToxicity) HomingFailureResponse() util.DoseResponse {
+	if !t.SublethalForagers || t.HomingFailureED50 <= 0 {
+		return util.NoEffect{}
+	}
+	return util.NewDoseResponse(t.HomingFailureModel, t.HomingFailureED50, t.HomingFailureSlope, 0)
+}
+
+// HandlingResponse returns the fraction of HandlingMaxIncrease by which the handling times of a forager squadron
+// are prolonged, depending on its recent dose.
+func (t *PPPToxicity) HandlingResponse() util.DoseResponse {
+	if !t.SublethalForagers || t.HandlingED50 <= 0 || t.HandlingMaxIncrease <= 0 {
+		return util.NoEffect{}
+	}
+	return util.NewDoseResponse(t.HandlingModel, t.HandlingED50, t.HandlingSlope, 0)
 }
 
-// WaterForaging parameters. Not used in the current state of the model.
+// DevelopmentDelayResponse returns the fraction of DevelopmentDelayMax by which the emergence of a cohort is delayed,
+// depending on its cumulative larval dose.
+func (t *PPPToxicity) DevelopmentDelayResponse() util.DoseResponse {
+	if !t.SublethalLarvae || t.DevelopmentDelayED50 <= 0 || t.DevelopmentDelayMax <= 0 {
+		return util.NoEffect{}
+	}
+	return util.NewDoseResponse(t.DevelopmentDelayModel, t.DevelopmentDelayED50, t.DevelopmentDelaySlope, 0)
+}
+
+// PupalFailureResponse returns the fraction of a cohort that fails to develop from pupae into adults,
+// depending on its cumulative larval dose.
+func (t *PPPToxicity) PupalFailureResponse() util.DoseResponse {
+	if !t.SublethalLarvae || t.PupalFailureED50 <= 0 {
+		return util.NoEffect{}
+	}
+	return util.NewDoseResponse(t.PupalFailureModel, t.PupalFailureED50, t.PupalFailureSlope, 0)
+}
+
+// LongevityResponse returns the fraction of LongevityMaxReduction by which the adult lifespan of a cohort is reduced,
+// depending on its cumulative larval dose.
+func (t *PPPToxicity) LongevityResponse() util.DoseResponse {
+	if !t.SublethalLarvae || t.LongevityED50 <= 0 || t.LongevityMaxReduction <= 0 {
+		return util.NoEffect{}
+	}
+	return util.NewDoseResponse(t.LongevityModel, t.LongevityED50, t.LongevitySlope, 0)
+}
+
+// HPGInhibitionResponse returns the fraction of HPGMaxInhibition by which the target gland size of an IHbee cohort is reduced,
+// depending on its daily oral dose.
+func (t *PPPToxicity) HPGInhibitionResponse() util.DoseResponse {
+	if t.HPGInhibitionED50 <= 0 || t.HPGMaxInhibition <= 0 {
+		return util.NoEffect{}
+	}
+	return util.NewDoseResponse(t.HPGInhibitionModel, t.HPGInhibitionED50, t.HPGInhibitionSlope, 0)
+}
+
+// LayingReductionResponse returns the fraction of LayingMaxReduction by which the egg laying of the queen is reduced,
+// depending on her daily oral dose.
+func (t *PPPToxicity) LayingReductionResponse() util.DoseResponse {
+	if !t.QueenEffects || t.LayingReductionED50 <= 0 || t.LayingMaxReduction <= 0 {
+		return util.NoEffect{}
+	}
+	return util.NewDoseResponse(t.LayingReductionModel, t.LayingReductionED50, t.LayingReductionSlope, 0)
+}
+
+// QueenOralResponse returns the daily probability of losing the queen, depending on her daily oral dose.
+func (t *PPPToxicity) QueenOralResponse() util.DoseResponse {
+	if !t.QueenEffects || t.QueenOralLD50 <= 0 {
+		return util.NoEffect{}
+	}
+	return util.NewDoseResponse(t.QueenOralModel, t.QueenOralLD50, t.QueenOralSlope, 0)
+}
+
+// SublethalCarryOver returns the fraction of the recent dose of forager squadrons that is carried over to the next day.
+func (t *PPPToxicity) SublethalCarryOver() float64 {
+	if t.SublethalHalfLife <= 0 {
+		return 0
+	}
+	return math.Pow(0.5, 1/t.SublethalHalfLife)
+}
+
+// WithinCohort returns the dose-response relationship for the mean dose of a cohort,
+// integrated over the within-cohort dose distribution.
+func (t *PPPToxicity) WithinCohort(dr util.DoseResponse) util.DoseResponse {
+	if t.DoseDistribution != dosedist.LogNormal || t.DoseCV <= 0 {
+		return dr
+	}
+	bins := t.DoseBins
+	if bins < 1 {
+		bins = 20
+	}
+	return util.NewLogNormalDose(dr, t.DoseCV, bins)
+}
+
+// LethalCutoff returns the cohort mortality fraction above which the whole cohort dies.
+func (t *PPPToxicity) LethalCutoff() float64 {
+	if t.CohortLethalCutoff <= 0 {
+		return 0.99
+	}
+	return t.CohortLethalCutoff
+}
+
+// WaterForaging parameters for the collection of water for cooling and honey dilution in beecs_ecotox/nursebeecs_ecotox.
 type WaterForaging struct {
 	WaterForaging             bool    // Determines whether water foraging takes place or not for the ecotox processes involved.
 	ETOX_cropvolume_water     float64 // The amount of water in [g] which is carried by one forager, calculated from 44 mg (Visscher et al. 1996)
@@ -67,3 +351,109 @@ type WaterForagingPeriod struct {
 	Builtin     bool        // Whether the used files are built-in. Use local files otherwise.
 	RandomYears bool        // Whether to randomize years.
 }
+
+// Wax parameters for the comb wax as a PPP compartment.
+//
+// PPP in the wax exchanges with the honey and pollen stores towards the partitioning equilibrium,
+// and larvae take up PPP from the wax of their cells.
+// Partition coefficients are the ratio of the concentration in wax to the concentration in the other matrix at equilibrium.
+type Wax struct {
+	WaxCompartment bool    // Determines whether the comb wax is simulated as a PPP compartment.
+	Mass           float64 // Mass of the comb wax [g].
+	InitialResidue float64 // PPP concentration in the comb wax at the start of the run [µg/kg].
+
+	PartitionHoney  float64 // Partition coefficient between wax and honey [ ].
+	PartitionPollen float64 // Partition coefficient between wax and pollen [ ].
+	PartitionLarvae float64 // Partition coefficient between wax and larvae [ ].
+	ExchangeRate    float64 // Fraction of the distance to the partitioning equilibrium that is exchanged per day [1/d].
+	LarvalMass      float64 // Mean mass of a larva for the uptake from the wax [mg].
+
+	DT50              float64 // Half-life of PPP in the comb wax; 0 = no degradation [d].
+	CombReplacement   float64 // Fraction of the comb wax replaced per year, spread evenly over the days [1/y].
+	FoundationResidue float64 // PPP concentration in the new wax of replaced combs [µg/kg].
+}
+
+// Decay returns the fraction of the PPP in the wax remaining after one day.
+func (w *Wax) Decay() float64 {
+	if w.DT50 <= 0 {
+		return 1
+	}
+	return math.Exp(-math.Log(2) / w.DT50)
+}
+
+// InHiveTreatments parameters for veterinary treatments applied inside the hive by the beekeeper, like varroacides.
+//
+// Treatments apply the simulated PPP, i.e. they share its degradation in the stores and, except for
+// the contact toxicity of the in-hive residue, its toxicity. Released active ingredient is split between the honey stores, the comb wax and an in-hive residue,
+// from which adult bees pick up contact doses.
+type InHiveTreatments struct {
+	Treatments []Treatment // Treatments applied every year; none by default.
+}
+
+// Enabled returns whether any in-hive treatment is applied.
+func (t *InHiveTreatments) Enabled() bool {
+	return len(t.Treatments) > 0
+}
+
+// Treatment parameters of a single in-hive treatment product.
+//
+// The treatment shares the store kinetics of the simulated PPP; only the contact toxicity and the DT50
+// of the in-hive residue are its own.
+type Treatment struct {
+	Name     string           // Name of the product, for output only.
+	Method   treatment.Method // Application method; strips release continuously, trickling and fumigation in pulses.
+	Start    int              // First day of the treatment window [day of year].
+	End      int              // Last day of the treatment window; strips are removed at the end of this day [day of year].
+	Interval int              // Days between pulses of trickling and fumigation within the window; 0 = a single pulse on Start [d].
+	Dose     float64          // Active ingredient per strip set or pulse [µg/colony].
+
+	ReleaseRate float64 // Fraction of the active ingredient remaining in the strips released per day [1/d].
+	ShareHoney  float64 // Fraction of the released active ingredient that ends up in the honey stores [ ].
+	ShareWax    float64 // Fraction of the released active ingredient that ends up in the comb wax, if it is simulated [ ].
+	ContactRate float64 // Fraction of the in-hive residue picked up by the adult bees per day [1/d].
+	DT50        float64 // Half-life of the in-hive residue; 0 = no degradation [d].
+	ContactLD50 float64 // Lethal dose for 50% of adult bees via contact with the in-hive residue; 0 = that of the simulated PPP [µg/bee].
+}
+
+// Active returns whether the treatment is in place on the given day of the year.
+func (t *Treatment) Active(dayOfYear int) bool {
+	return dayOfYear >= t.Start && dayOfYear <= t.End
+}
+
+// Release returns the active ingredient released on the given day of the year from the given amount remaining in the strips.
+func (t *Treatment) Release(dayOfYear int, device float64) float64 {
+	if !t.Active(dayOfYear) {
+		return 0
+	}
+	if t.Method == treatment.Strip {
+		return device * t.ReleaseRate
+	}
+	day := dayOfYear - t.Start
+	if day == 0 || (t.Interval > 0 && day%t.Interval == 0) {
+		return t.Dose
+	}
+	return 0
+}
+
+// ContactEquivalent converts a contact dose of the treatment into the dose of the simulated PPP with the same toxicity,
+// given the contact LD50 of the simulated PPP. The dose-response slope of the simulated PPP applies to the result.
+func (t *Treatment) ContactEquivalent(dose float64, pppLD50 float64) float64 {
+	if t.ContactLD50 <= 0 {
+		return dose
+	}
+	return dose * pppLD50 / t.ContactLD50
+}
+
+// Decay returns the fraction of the in-hive residue remaining after one day.
+func (t *Treatment) Decay() float64 {
+	if t.DT50 <= 0 {
+		return 1
+	}
+	return math.Exp(-math.Log(2) / t.DT50)
+}
+
+// MassBalance parameters for the PPP mass balance check.
+type MassBalance struct {
+	File    string  // Base path of the mass balance tables written at the end of a run, without extension; writes File.csv and File.md. No tables are written if empty.
+	Epsilon float64 // Absolute amount of unaccounted PPP that is always accepted, in addition to the relative tolerance of [PPPApplication] [µg].
+}
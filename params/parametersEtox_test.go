@@ -0,0 +1,43 @@
+package params_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/treatment"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaxDecay(t *testing.T) {
+	wax := params.DefaultEtox().Wax
+	assert.Equal(t, 1.0, wax.Decay())
+
+	wax.DT50 = 80
+	assert.InDelta(t, 0.5, math.Pow(wax.Decay(), wax.DT50), 1e-12)
+}
+
+func TestTreatmentRelease(t *testing.T) {
+	strip := params.Treatment{Method: treatment.Strip, Start: 10, End: 20, Dose: 100, ReleaseRate: 0.1}
+	assert.Equal(t, 0.0, strip.Release(9, 100))
+	assert.InDelta(t, 10.0, strip.Release(10, 100), 1e-12)
+	assert.InDelta(t, 5.0, strip.Release(20, 50), 1e-12)
+	assert.Equal(t, 0.0, strip.Release(21, 50))
+
+	pulse := params.Treatment{Method: treatment.Trickling, Start: 10, End: 20, Interval: 5, Dose: 100}
+	assert.Equal(t, 100.0, pulse.Release(10, 0))
+	assert.Equal(t, 0.0, pulse.Release(11, 0))
+	assert.Equal(t, 100.0, pulse.Release(15, 0))
+	assert.Equal(t, 100.0, pulse.Release(20, 0))
+	assert.Equal(t, 0.0, pulse.Release(25, 0))
+
+	assert.Equal(t, 1.0, pulse.Decay())
+}
+
+func TestTreatmentContactEquivalent(t *testing.T) {
+	strip := params.Treatment{Method: treatment.Strip}
+	assert.Equal(t, 0.5, strip.ContactEquivalent(0.5, 0.6))
+
+	strip.ContactLD50 = 6
+	assert.InDelta(t, 0.05, strip.ContactEquivalent(0.5, 0.6), 1e-12)
+}
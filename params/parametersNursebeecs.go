@@ -41,6 +41,62 @@ type NursingRework struct {
 	Nursebeecsv1           bool // switch to turn on Nbeecs v.1 --> coupling of nurseworkload to ProteinFactorNurses
 	ForesightedCannibalism bool // switch to turn on ForesightedCannibalism based on Schmickl&Crailsheim 2001&2002--> cannibalization depending on time passed since last pollen influx
 
-	HGEffects    bool // switch to turn on reduced brood care capabilies from PPP induced reduced HPG activity
-	HGFoodIntake bool // switch to turn on reduced maximum food intake capability as a PPP induced sublethal effect
+	HGEffects bool // switch to turn on the dynamic HPG state model (see HPGDevelopment); nursing capabilities of IHbee cohorts then follow their gland size, including PPP induced atrophy
+}
+
+// Development and atrophy of the hypopharyngeal glands (HPG) of in-hive workers, used if NursingRework.HGEffects is turned on.
+// Gland size is relative to the maximum and moves towards a target given by age, nutrition and PPP exposure.
+type HPGDevelopment struct {
+	PeakStart          int     // age at which glands reach full size; they grow linearly from emergence until then; 0 = 6 [d]
+	PeakEnd            int     // age until which glands stay at full size before they atrophy; 0 = 12 [d]
+	AtrophyDays        int     // days over which glands atrophy to Residual after PeakEnd; 0 = 10 [d]
+	Residual           float64 // relative gland size of older IHbees after atrophy [-]
+	GrowthRate         float64 // daily fraction of the difference to the target size closed when glands grow or recover; 0 = 0.5 [1/d]
+	AtrophyRate        float64 // daily fraction of the difference to the target size closed when glands shrink; 0 = 0.3 [1/d]
+	NutritionThreshold float64 // ProteinFactorNurses above which nutrition does not limit gland size; 0 = 0.5 [-]
+}
+
+// Potential returns the relative gland size of well fed, unexposed workers of the given age.
+func (h *HPGDevelopment) Potential(age int) float64 {
+	peakStart := h.PeakStart
+	if peakStart <= 0 {
+		peakStart = 6
+	}
+	peakEnd := h.PeakEnd
+	if peakEnd <= 0 {
+		peakEnd = 12
+	}
+	atrophyDays := h.AtrophyDays
+	if atrophyDays <= 0 {
+		atrophyDays = 10
+	}
+
+	if age < peakStart {
+		return float64(age) / float64(peakStart)
+	}
+	if age <= peakEnd {
+		return 1
+	}
+	return max(h.Residual, 1-(1-h.Residual)*float64(age-peakEnd)/float64(atrophyDays))
+}
+
+// Rates returns the daily relaxation rates of gland growth and atrophy.
+func (h *HPGDevelopment) Rates() (growth float64, atrophy float64) {
+	growth, atrophy = h.GrowthRate, h.AtrophyRate
+	if growth <= 0 {
+		growth = 0.5
+	}
+	if atrophy <= 0 {
+		atrophy = 0.3
+	}
+	return min(growth, 1), min(atrophy, 1)
+}
+
+// Nutrition returns the factor by which nutrition limits the gland size, given the current ProteinFactorNurses.
+func (h *HPGDevelopment) Nutrition(proteinFactor float64) float64 {
+	threshold := h.NutritionThreshold
+	if threshold <= 0 {
+		threshold = 0.5
+	}
+	return min(max(proteinFactor/threshold, 0), 1)
 }
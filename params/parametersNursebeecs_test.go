@@ -0,0 +1,28 @@
+package params_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHPGDevelopment(t *testing.T) {
+	h := params.DefaultNursebeecs().HPGDevelopment
+
+	assert.Equal(t, 0.0, h.Potential(0))
+	assert.InDelta(t, 0.5, h.Potential(3), 1e-12)
+	assert.Equal(t, 1.0, h.Potential(6))
+	assert.Equal(t, 1.0, h.Potential(12))
+	assert.Less(t, h.Potential(15), 1.0)
+	assert.Equal(t, h.Residual, h.Potential(40))
+
+	assert.Equal(t, 1.0, h.Nutrition(0.8))
+	assert.InDelta(t, 0.5, h.Nutrition(0.25), 1e-12)
+
+	zero := params.HPGDevelopment{}
+	growth, atrophy := zero.Rates()
+	assert.Equal(t, 0.5, growth)
+	assert.Equal(t, 0.3, atrophy)
+	assert.Equal(t, h.Potential(8), zero.Potential(8))
+}
@@ -0,0 +1,31 @@
+package params_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueenMaxEggs(t *testing.T) {
+	q := params.Default().Queen
+	assert.Equal(t, 1600.0, q.MaxEggs(1600, 1000))
+
+	q.Ageing = true
+	assert.Equal(t, 1600.0, q.MaxEggs(1600, 0))
+	assert.Greater(t, q.MaxEggs(1600, 73), 1600.0)
+	assert.InDelta(t, 1600-0.0027*730*730+0.395*730, q.MaxEggs(1600, 730), 1e-9)
+	assert.Equal(t, 0.0, q.MaxEggs(1600, 1000))
+}
+
+func TestManagementEventDue(t *testing.T) {
+	every := params.ManagementEvent{Day: 200}
+	assert.True(t, every.Due(200))
+	assert.True(t, every.Due(365+200))
+	assert.False(t, every.Due(201))
+
+	second := params.ManagementEvent{Day: 200, Years: []int{1}}
+	assert.False(t, second.Due(200))
+	assert.True(t, second.Due(365+200))
+	assert.False(t, second.Due(2*365+200))
+}
@@ -0,0 +1,26 @@
+package sys
+
+import (
+	"math"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+)
+
+// checkAccounting compares an accounted quantity against its reference and records
+// a [globals.AccountingEvent] if the deviation exceeds the relative tolerance.
+// Deviations below 0.5 units are always accepted, like the rounding checks of BEEHAVE_ecotox.
+// This floor is sized for the stores in kJ and mg; use [checkAccountingEps] for quantities of other magnitude.
+// Returns whether the check passed.
+func checkAccounting(events *globals.AccountingEvents, tick int64, check string, expected float64, actual float64, tolerance float64) bool {
+	return checkAccountingEps(events, tick, check, expected, actual, tolerance, 0.5)
+}
+
+// checkAccountingEps works like [checkAccounting], but always accepts deviations up to the given absolute epsilon instead of 0.5 units.
+func checkAccountingEps(events *globals.AccountingEvents, tick int64, check string, expected float64, actual float64, tolerance float64, epsilon float64) bool {
+	dev := math.Abs(actual - expected)
+	if dev <= epsilon || dev <= tolerance*math.Abs(expected) {
+		return true
+	}
+	events.Add(tick, check, expected, actual)
+	return false
+}
@@ -0,0 +1,161 @@
+package sys
+
+import (
+	"math/rand/v2"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
+	"github.com/mlange-42/ark/ecs"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// DevelopmentEtox applies sublethal effects of the larval dose to brood and young adults:
+//   - pupal failure of larvae entering the pupal stage
+//   - delayed emergence of pupae
+//   - reduced longevity of in-hive bees
+//
+// The cumulative larval dose is carried along with the cohorts as an exposure marker.
+// The marker of the workers turning into foragers is stored in [globals.InHiveEtox] ForagerLarvalDose,
+// from where [ForagingEtox] hands it on to the new squadrons; [MortalityForagersEtox] applies their reduced longevity.
+// Must run directly before [AgeCohorts], as it shifts the markers in anticipation of the cohorts' shift.
+// This is part of beecs_ecotox/nursebeecs_ecotox only.
+type DevelopmentEtox struct {
+	workerMort *params.WorkerMortality
+	droneMort  *params.DroneMortality
+	toxic      *params.PPPToxicity
+	rng        rand.Source
+
+	larvae     *globals.Larvae
+	pupae      *globals.Pupae
+	inHive     *globals.InHive
+	larvaeEtox *globals.LarvaeEtox
+	pupaeEtox  *globals.PupaeEtox
+	inHiveEtox *globals.InHiveEtox
+	popStats   *globals.PopulationStatsEtox
+	aff        *globals.AgeFirstForaging
+
+	delayResponse     util.DoseResponse
+	failureResponse   util.DoseResponse
+	longevityResponse util.DoseResponse
+}
+
+func (s *DevelopmentEtox) Initialize(w *ecs.World) {
+	s.workerMort = ecs.GetResource[params.WorkerMortality](w)
+	s.droneMort = ecs.GetResource[params.DroneMortality](w)
+	s.toxic = ecs.GetResource[params.PPPToxicity](w)
+	s.rng = ecs.GetResource[globals.RandStreams](w).Get(randLarvalEffects)
+
+	s.larvae = ecs.GetResource[globals.Larvae](w)
+	s.pupae = ecs.GetResource[globals.Pupae](w)
+	s.inHive = ecs.GetResource[globals.InHive](w)
+	s.larvaeEtox = ecs.GetResource[globals.LarvaeEtox](w)
+	s.pupaeEtox = ecs.GetResource[globals.PupaeEtox](w)
+	s.inHiveEtox = ecs.GetResource[globals.InHiveEtox](w)
+	s.popStats = ecs.GetResource[globals.PopulationStatsEtox](w)
+	s.aff = ecs.GetResource[globals.AgeFirstForaging](w)
+
+	s.delayResponse = s.toxic.DevelopmentDelayResponse()
+	s.failureResponse = s.toxic.PupalFailureResponse()
+	s.longevityResponse = s.toxic.LongevityResponse()
+}
+
+func (s *DevelopmentEtox) Update(w *ecs.World) {
+	if s.toxic.SublethalLarvae {
+		s.popStats.PupalFailures += s.pupalFailure(s.larvae.Workers, s.larvaeEtox.WorkerLarvalDose)
+		s.popStats.PupalFailures += s.pupalFailure(s.larvae.Drones, s.larvaeEtox.DroneLarvalDose)
+
+		s.popStats.DelayedPupae += s.delayEmergence(s.pupae.Workers, s.pupaeEtox.WorkerLarvalDose)
+		s.popStats.DelayedPupae += s.delayEmergence(s.pupae.Drones, s.pupaeEtox.DroneLarvalDose)
+
+		s.popStats.LongevityDeaths += s.reduceLongevity(s.inHive.Workers, s.inHiveEtox.WorkerLarvalDose, s.workerMort.InHive)
+		s.popStats.LongevityDeaths += s.reduceLongevity(s.inHive.Drones, s.inHiveEtox.DroneLarvalDose, s.droneMort.InHive)
+	}
+
+	// after the shift, the cohorts from index Aff-1 on are transitioned to foragers by [TransitionForagers]
+	s.inHiveEtox.ForagerLarvalDose = meanMarker(s.inHive.Workers[s.aff.Aff-1:], s.inHiveEtox.WorkerLarvalDose[s.aff.Aff-1:])
+	shiftMarker(s.inHiveEtox.WorkerLarvalDose, s.pupaeEtox.WorkerLarvalDose[len(s.pupaeEtox.WorkerLarvalDose)-1])
+	shiftMarker(s.inHiveEtox.DroneLarvalDose, s.pupaeEtox.DroneLarvalDose[len(s.pupaeEtox.DroneLarvalDose)-1])
+
+	shiftMarker(s.pupaeEtox.WorkerLarvalDose, s.larvaeEtox.WorkerLarvalDose[len(s.larvaeEtox.WorkerLarvalDose)-1])
+	shiftMarker(s.pupaeEtox.DroneLarvalDose, s.larvaeEtox.DroneLarvalDose[len(s.larvaeEtox.DroneLarvalDose)-1])
+
+	shiftMarker(s.larvaeEtox.WorkerLarvalDose, 0)
+	shiftMarker(s.larvaeEtox.DroneLarvalDose, 0)
+}
+
+func (s *DevelopmentEtox) Finalize(w *ecs.World) {}
+
+// pupalFailure removes the larvae of the oldest cohort that will not develop into adults after pupation.
+func (s *DevelopmentEtox) pupalFailure(larvae []int, marker []float64) int {
+	last := len(larvae) - 1
+	failed := int(float64(larvae[last]) * s.failureResponse.Mortality(marker[last]))
+	larvae[last] -= failed
+	return failed
+}
+
+// delayEmergence holds back a part of the oldest pupae cohort for another day.
+// Held back pupae are moved to the second oldest cohort, so that they end up in the oldest cohort again after aging.
+// With a mean delay of D days, the daily probability to be held back is D/(1+D).
+func (s *DevelopmentEtox) delayEmergence(pupae []int, marker []float64) int {
+	last := len(pupae) - 1
+	if last < 1 || pupae[last] == 0 {
+		return 0
+	}
+	delay := s.toxic.DevelopmentDelayMax * s.delayResponse.Mortality(marker[last])
+	held := int(float64(pupae[last]) * delay / (1 + delay))
+	if held == 0 {
+		return 0
+	}
+
+	total := pupae[last-1] + held
+	marker[last-1] = (marker[last-1]*float64(pupae[last-1]) + marker[last]*float64(held)) / float64(total)
+	pupae[last-1] = total
+	pupae[last] -= held
+	return held
+}
+
+// reduceLongevity applies additional mortality to in-hive cohorts.
+// A lifespan reduced by the fraction r corresponds to the background mortality raised by the factor 1/(1-r).
+func (s *DevelopmentEtox) reduceLongevity(coh []int, marker []float64, baseMortality float64) int {
+	died := 0
+	for i, num := range coh {
+		if num == 0 || marker[i] <= 0 {
+			continue
+		}
+		reduction := min(s.toxic.LongevityMaxReduction*s.longevityResponse.Mortality(marker[i]), 0.99)
+		m := baseMortality * (1/(1-reduction) - 1)
+		if m <= 0 {
+			continue
+		}
+		rng := distuv.Poisson{
+			Src:    &util.RandWrapper{Src: s.rng},
+			Lambda: m * float64(num),
+		}
+		toDie := util.MinInt(int(rng.Rand()), num)
+		coh[i] = num - toDie
+		died += toDie
+	}
+	return died
+}
+
+// meanMarker returns the mean of the cohort markers, weighted by the number of individuals per cohort.
+func meanMarker(coh []int, marker []float64) float64 {
+	total, sum := 0, 0.0
+	for i, num := range coh {
+		total += num
+		sum += marker[i] * float64(num)
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / float64(total)
+}
+
+// shiftMarker moves cohort markers to the next day's cohort, analogous to the cohorts in [AgeCohorts].
+func shiftMarker(marker []float64, add float64) {
+	for i := len(marker) - 1; i > 0; i-- {
+		marker[i] = marker[i-1]
+	}
+	marker[0] = add
+}
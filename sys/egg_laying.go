@@ -12,12 +12,15 @@ import (
 
 // EggLaying produces new worker and drone eggs,
 // based on seasonal egg laying capacity and available nurse bees.
+// With [QueenDynamics], the capacity depends on the state and age of the queen, see [globals.Queen].
 type EggLaying struct {
 	time        *resource.Tick
 	eggs        *globals.Eggs
 	pop         *globals.PopulationStats
 	nurseParams *params.Nursing
 	workerDev   *params.WorkerDevelopment
+	queenParams *params.Queen
+	queen       *globals.Queen
 }
 
 func (s *EggLaying) Initialize(w *ecs.World) {
@@ -26,10 +29,25 @@ func (s *EggLaying) Initialize(w *ecs.World) {
 	s.pop = ecs.GetResource[globals.PopulationStats](w)
 	s.nurseParams = ecs.GetResource[params.Nursing](w)
 	s.workerDev = ecs.GetResource[params.WorkerDevelopment](w)
+
+	// the queen state is optional, to keep models without QueenDynamics working
+	if queen := ecs.NewResource[globals.Queen](w); queen.Has() {
+		s.queen = queen.Get()
+		s.queenParams = ecs.GetResource[params.Queen](w)
+	}
 }
 
 func (s *EggLaying) Update(w *ecs.World) {
-	elr := float64(s.nurseParams.MaxEggsPerDay) * util.Season(s.time.Tick)
+	maxEggs := float64(s.nurseParams.MaxEggsPerDay)
+	if s.queen != nil {
+		if !s.queen.IsLaying() {
+			s.eggs.Workers[0] = 0
+			s.eggs.Drones[0] = 0
+			return
+		}
+		maxEggs = s.queenParams.MaxEggs(s.nurseParams.MaxEggsPerDay, s.queen.Age) * s.queen.Fecundity
+	}
+	elr := maxEggs * util.Season(s.time.Tick)
 
 	if s.nurseParams.EggNursingLimit { // this does not need to be reworked because the idea behind this is a max capacity for brood cells based on overall colony size
 		emergingAge := float64(s.workerDev.EggTime + s.workerDev.LarvaeTime + s.workerDev.PupaeTime)
@@ -55,8 +73,6 @@ func (s *EggLaying) Update(w *ecs.World) {
 	}
 	eggs = util.MaxInt(eggs-droneEggs, 0)
 
-	// TODO: queen age
-
 	s.eggs.Workers[0] = eggs
 	s.eggs.Drones[0] = droneEggs
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
 	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
 	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
 	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
 )
@@ -29,17 +30,21 @@ type EtoxStorages struct {
 	toxic          *params.PPPToxicity
 	waterParams    *params.WaterForaging
 
-	beecsStores *globals.Stores
-	stores      *globals.StoragesEtox
-	pppFate     *globals.PPPFate
-	pop         *globals.PopulationStats
-	etoxStats   *globals.PopulationStatsEtox
-	inHive      *globals.InHive
-	inHiveEtox  *globals.InHiveEtox
-	Larvae      *globals.Larvae
-	LarvaeEtox  *globals.LarvaeEtox
-	cons        *globals.ConsumptionStats
-	nstats      *globals.NursingStats
+	beecsStores  *globals.Stores
+	stores       *globals.StoragesEtox
+	honeyStores  *globals.HoneyStoresEtox
+	events       *globals.AccountingEvents
+	time         *resource.Tick
+	pollenStores *globals.PollenStoresEtox
+	pppFate      *globals.PPPFate
+	pop          *globals.PopulationStats
+	etoxStats    *globals.PopulationStatsEtox
+	inHive       *globals.InHive
+	inHiveEtox   *globals.InHiveEtox
+	Larvae       *globals.Larvae
+	LarvaeEtox   *globals.LarvaeEtox
+	cons         *globals.ConsumptionStats
+	nstats       *globals.NursingStats
 
 	foragerExpoMapper     *ecs.Map1[comp.PPPExpo]
 	foragerActivityMapper *ecs.Map1[comp.ActivityEtox]
@@ -47,6 +52,11 @@ type EtoxStorages struct {
 	foragerShuffle        []ecs.Entity
 
 	rng *rand.Rand
+
+	honeyKinetics       util.Kinetics
+	pollenStoreKinetics util.Kinetics
+
+	pollenForaged float64 // PPP foraged into the pollen stores until the last update
 }
 
 func (s *EtoxStorages) Initialize(w *ecs.World) {
@@ -58,11 +68,17 @@ func (s *EtoxStorages) Initialize(w *ecs.World) {
 	s.storesParams = ecs.GetResource[params.Stores](w)
 	s.foragerParams = ecs.GetResource[params.Foragers](w)
 	s.etox = ecs.GetResource[params.PPPApplication](w)
+	s.honeyKinetics = s.etox.HoneyKinetics.Kinetics(s.etox.DT50honey)
+	s.pollenStoreKinetics = s.etox.PollenStoreKinetics.Kinetics(0)
 	s.toxic = ecs.GetResource[params.PPPToxicity](w)
 	s.waterParams = ecs.GetResource[params.WaterForaging](w)
 
 	s.beecsStores = ecs.GetResource[globals.Stores](w)
 	s.stores = ecs.GetResource[globals.StoragesEtox](w)
+	s.honeyStores = ecs.GetResource[globals.HoneyStoresEtox](w)
+	s.events = ecs.GetResource[globals.AccountingEvents](w)
+	s.time = ecs.GetResource[resource.Tick](w)
+	s.pollenStores = ecs.GetResource[globals.PollenStoresEtox](w)
 	s.pop = ecs.GetResource[globals.PopulationStats](w)
 	s.etoxStats = ecs.GetResource[globals.PopulationStatsEtox](w)
 	s.pppFate = ecs.GetResource[globals.PPPFate](w)
@@ -77,7 +93,8 @@ func (s *EtoxStorages) Initialize(w *ecs.World) {
 	s.foragerActivityMapper = s.foragerActivityMapper.New(w)
 	s.foragerFilter = s.foragerFilter.New(w)
 
-	s.rng = rand.New(ecs.GetResource[resource.Rand](w))
+	s.rng = rand.New(ecs.GetResource[globals.RandStreams](w).Get(randInHiveExposure))
+	s.pollenForaged = 0
 }
 
 func (s *EtoxStorages) Update(w *ecs.World) {
@@ -102,23 +119,34 @@ func (s *EtoxStorages) Update(w *ecs.World) {
 		s.stores.ETOX_EnergyThermo = float64(s.pop.TotalBrood) * thermoRegBrood * 0.001 * s.energyParams.Honey // or calculate the total necessary energy
 	}
 
+	if s.etox.PollenCompartments {
+		s.stores.PPPInHivePollenConc = s.pollenStores.ConsumptionConc(s.cons.PollenDaily) // pollen is eaten oldest first
+	} else if s.beecsStores.Pollen <= 0 && s.cons.PollenDaily > 0 {
+		// the pollen store ran empty today; the PPP eaten beyond the content of the store is either prevented or recorded
+		available := s.stores.PPPpollenTotal + s.pppFate.PPPpollenStores - s.pollenForaged
+		if s.etox.PollenEmptyfix {
+			s.stores.PPPInHivePollenConc = math.Min(s.stores.PPPInHivePollenConc, available/s.cons.PollenDaily)
+		} else {
+			s.pppFate.PPPpollenExcess += math.Max(s.stores.PPPInHivePollenConc*s.cons.PollenDaily-available, 0)
+		}
+	}
+	s.pollenForaged = s.pppFate.PPPpollenStores
+
 	// get values for some observing/debugging variables
 	s.stores.Pollenconcbeforeeating = s.stores.PPPInHivePollenConc // used in debugging and as a helpful metric
 
-	if s.stores.ETOX_HES_E_D0 != 0 {
-		s.stores.Nectarconcbeforeeating = s.stores.ETOX_HES_C_D0 // used in debugging and as a helpful metric
-	} else if s.stores.ETOX_HES_E_D1 != 0 {
-		s.stores.Nectarconcbeforeeating = s.stores.ETOX_HES_C_D1 // used in debugging and as a helpful metric
-	} else if s.stores.ETOX_HES_E_D2 != 0 {
-		s.stores.Nectarconcbeforeeating = s.stores.ETOX_HES_C_D2 // used in debugging and as a helpful metric
-	} else if s.stores.ETOX_HES_E_D3 != 0 {
-		s.stores.Nectarconcbeforeeating = s.stores.ETOX_HES_C_D3 // used in debugging and as a helpful metric
-	} else if s.stores.ETOX_HES_E_D4 != 0 {
-		s.stores.Nectarconcbeforeeating = s.stores.ETOX_HES_C_D4 // used in debugging and as a helpful metric
-	} else if s.stores.ETOX_HES_E_Capped != 0 {
-		s.stores.Nectarconcbeforeeating = s.stores.ETOX_HES_C_Capped // used in debugging and as a helpful metric
-	} else {
-		s.stores.Nectarconcbeforeeating = 0 // used in debugging and as a helpful metric
+	s.stores.Nectarconcbeforeeating = s.honeyStores.NextConc(s.etox.HoneyOldestFirst) // used in debugging and as a helpful metric
+
+	// PPP concentrations of the larval food, for comparison with residues in worker and drone jelly
+	honeyConc := s.stores.Nectarconcbeforeeating * 0.001 * s.energyParams.Honey
+	pollenConc := s.stores.PPPInHivePollenConc * 0.001
+	if s.pop.WorkerLarvae > 0 {
+		s.etoxStats.WorkerJellyConc = jellyConcentration(s.needs.WorkerLarvaTotal/float64(s.workerDev.LarvaeTime), s.needsPollen.WorkerLarvaTotal/float64(s.workerDev.LarvaeTime),
+			honeyConc, pollenConc, s.toxic.NursebeesNectar, s.toxic.NursebeesPollen)
+	}
+	if s.pop.DroneLarvae > 0 {
+		s.etoxStats.DroneJellyConc = jellyConcentration(s.needs.DroneLarva, s.needsPollen.DroneLarva,
+			honeyConc, pollenConc, s.toxic.NursebeesNectar, s.toxic.NursebeesPollen)
 	}
 
 	// foragers, pretty straigt forward and same for all model versions
@@ -223,17 +251,25 @@ func (s *EtoxStorages) Update(w *ecs.World) {
 	if s.etox.DegradationHoney {
 		s.DegradeHoney(w)
 	}
+	if s.etox.PollenCompartments {
+		s.UpdatePollenCompartments(w)
+	} else {
+		s.DegradePollen(w)
+	}
 
 	// leftovers from debugging
 	_ = s.pop.DroneLarvae + s.pop.DronesInHive + s.pop.WorkerLarvae + s.pop.WorkersForagers + s.pop.WorkersInHive + forcount
-	// checkpoint for bugfixing honey consumption in etox
-	if math.Round(consumed_honey-(s.cons.HoneyDaily*0.001*s.energyParams.Honey)) != 0 || math.Round(consumed_pollen/1000.0-s.cons.PollenDaily) != 0 {
-		panic("Fatal error in honey store dose calculations, model output will be wrong!")
-	}
+	// checkpoint for honey and pollen consumption in etox; deviations get reported instead of stopping the model
+	checkAccounting(s.events, s.time.Tick, "honey consumption", s.cons.HoneyDaily*0.001*s.energyParams.Honey, consumed_honey, s.etox.Tolerance())
+	checkAccounting(s.events, s.time.Tick, "pollen consumption", s.cons.PollenDaily, consumed_pollen/1000.0, s.etox.Tolerance())
 
 	s.ShiftHoney(w)
 
-	s.stores.PPPpollenTotal = s.stores.PPPInHivePollenConc * s.beecsStores.Pollen
+	if s.etox.PollenCompartments {
+		_, s.stores.PPPpollenTotal = s.pollenStores.Total()
+	} else {
+		s.stores.PPPpollenTotal = s.stores.PPPInHivePollenConc * s.beecsStores.Pollen
+	}
 	s.stores.PPPhoneyTotal = s.calcPPPhoneytotal(w)
 	s.stores.PPPTotal = s.stores.PPPpollenTotal + s.stores.PPPhoneyTotal
 }
@@ -248,21 +284,11 @@ func (s *EtoxStorages) addNurseExptoIHbees(w *ecs.World, PPPnurses float64, NumI
 			PPPnurses -= AddOralDose * float64(coh[i])
 		}
 	}
-	if math.Round(PPPnurses) != 0 {
-		panic("PPP should be 0 by now, there must be a bug somewhere!")
-	}
+	checkAccounting(s.events, s.time.Tick, "nurse PPP redistribution", 0, PPPnurses, 0) // PPP should be 0 by now
 }
 
 func (s *EtoxStorages) calcPPPhoneytotal(w *ecs.World) (totalPPP float64) {
-	totalPPP = 0.
-	totalPPP += s.stores.ETOX_HES_C_D0 * s.stores.ETOX_HES_E_D0
-	totalPPP += s.stores.ETOX_HES_C_D1 * s.stores.ETOX_HES_E_D1
-	totalPPP += s.stores.ETOX_HES_C_D2 * s.stores.ETOX_HES_E_D2
-	totalPPP += s.stores.ETOX_HES_C_D3 * s.stores.ETOX_HES_E_D3
-	totalPPP += s.stores.ETOX_HES_C_D4 * s.stores.ETOX_HES_E_D4
-	totalPPP += s.stores.ETOX_HES_C_Capped * s.stores.ETOX_HES_E_Capped
-
-	return
+	return s.honeyStores.TotalPPP()
 }
 
 func (s *EtoxStorages) CalcDosePerCohort(w *ecs.World, coh []int, dose []float64, init_honeyenergy float64, honey_need float64, pollen_need float64, nursebeefactorHoney float64, nursebeefactorPollen float64) (CumDose float64, cohortcounter int, consumed float64, pconsumed float64, num int) {
@@ -273,7 +299,7 @@ func (s *EtoxStorages) CalcDosePerCohort(w *ecs.World, coh []int, dose []float64
 	num = 0
 	pconsumed = 0.
 
-	order := rand.Perm(len(coh)) // randomize order to further emulate NetLogo ask function
+	order := s.rng.Perm(len(coh)) // randomize order to further emulate NetLogo ask function
 	for _, i := range order {
 		ETOX_PPPOralDose := 0.
 		ETOX_Consumed_Honey := init_honeyenergy
@@ -289,6 +315,9 @@ func (s *EtoxStorages) CalcDosePerCohort(w *ecs.World, coh []int, dose []float64
 			if s.etox.Nursebeefix {
 				s.etoxStats.PPPNursebees += ETOX_PPPOralDose * (1 - nursebeefactorHoney) * float64(coh[i])
 				s.etoxStats.PPPNursebees += s.stores.PPPInHivePollenConc * pollen_need * 0.001 * (1 - nursebeefactorPollen) * float64(coh[i])
+			} else { // the PPP filtered by the nurse bees dissipates, as in BEEHAVE_ecotox
+				s.pppFate.PPPdissipatedNurses += ETOX_PPPOralDose * (1 - nursebeefactorHoney) * float64(coh[i])
+				s.pppFate.PPPdissipatedNurses += s.stores.PPPInHivePollenConc * pollen_need * 0.001 * (1 - nursebeefactorPollen) * float64(coh[i])
 			}
 			ETOX_PPPOralDose = ETOX_PPPOralDose * nursebeefactorHoney
 			ETOX_PPPOralDose += s.stores.PPPInHivePollenConc * pollen_need * 0.001 * nursebeefactorPollen // intake from pollen
@@ -306,118 +335,102 @@ func (s *EtoxStorages) CalcDosePerCohort(w *ecs.World, coh []int, dose []float64
 }
 
 func (s *EtoxStorages) FeedOnHoneyStores(w *ecs.World, cons float64, number float64, honeydilution bool) (OralDose float64) {
-	OralDose = 0.
-	if cons < s.stores.ETOX_HES_E_D0 {
-		OralDose += cons * s.stores.ETOX_HES_C_D0 / number
-		s.stores.ETOX_HES_E_D0 -= cons
-	} else {
-		OralDose += s.stores.ETOX_HES_E_D0 * s.stores.ETOX_HES_C_D0 / number
-		cons -= s.stores.ETOX_HES_E_D0
-		s.stores.ETOX_HES_E_D0 = 0
-
-		if cons < s.stores.ETOX_HES_E_D1 {
-			OralDose += cons * s.stores.ETOX_HES_C_D1 / number
-			s.stores.ETOX_HES_E_D1 -= cons
-		} else {
-			OralDose += s.stores.ETOX_HES_E_D1 * s.stores.ETOX_HES_C_D1 / number
-			cons -= s.stores.ETOX_HES_E_D1
-			s.stores.ETOX_HES_E_D1 = 0
-
-			if cons < s.stores.ETOX_HES_E_D2 {
-				OralDose += cons * s.stores.ETOX_HES_C_D2 / number
-				s.stores.ETOX_HES_E_D2 -= cons
-			} else {
-				OralDose += s.stores.ETOX_HES_E_D1 * s.stores.ETOX_HES_C_D2 / number
-				cons -= s.stores.ETOX_HES_E_D2
-				s.stores.ETOX_HES_E_D2 = 0
-
-				if cons < s.stores.ETOX_HES_E_D3 {
-					OralDose += cons * s.stores.ETOX_HES_C_D3 / number
-					s.stores.ETOX_HES_E_D3 -= cons
-				} else {
-					OralDose += s.stores.ETOX_HES_E_D3 * s.stores.ETOX_HES_C_D3 / number
-					cons -= s.stores.ETOX_HES_E_D3
-					s.stores.ETOX_HES_E_D3 = 0
-
-					if cons < s.stores.ETOX_HES_E_D4 {
-						OralDose += cons * s.stores.ETOX_HES_C_D4 / number
-						s.stores.ETOX_HES_E_D4 -= cons
-					} else {
-						OralDose += s.stores.ETOX_HES_E_D4 * s.stores.ETOX_HES_C_D4 / number
-						cons -= s.stores.ETOX_HES_E_D4
-						s.stores.ETOX_HES_E_D4 = 0
-
-						if cons < s.stores.ETOX_HES_E_Capped {
-							OralDose += cons * s.stores.ETOX_HES_C_Capped / number
-							s.stores.ETOX_HES_E_Capped -= cons
-							if honeydilution {
-								s.stores.ETOX_Waterneedfordilution += cons / s.energyParams.Honey / s.etox.ETOXDensityOfHoney * 0.6
-							}
-						} else {
-							OralDose += s.stores.ETOX_HES_E_Capped * s.stores.ETOX_HES_C_Capped / number
-							cons -= s.stores.ETOX_HES_E_Capped
-							if honeydilution {
-								s.stores.ETOX_Waterneedfordilution += s.stores.ETOX_HES_E_Capped / s.energyParams.Honey / s.etox.ETOXDensityOfHoney * 0.6
-							}
-							s.stores.ETOX_HES_E_Capped = 0
-						}
-					}
-				}
-			}
-		}
+	ppp, capped := s.honeyStores.Feed(cons, s.etox.HoneyOldestFirst)
+	if honeydilution { // capped honey gets diluted with water, which may carry PPP from contaminated water sources
+		water := capped / s.energyParams.Honey / s.etox.ETOXDensityOfHoney * 0.6
+		s.stores.ETOX_Waterneedfordilution += water
+		ppp += s.stores.UseWater(water)
 	}
-	return
+	return ppp / number
 }
 
 func (s *EtoxStorages) DegradeHoney(w *ecs.World) {
-	// if this ever is to be turned on PPPfate oberver should be introduced here if someone wanted to create a mass balance again
-	DT50honey := s.etox.DT50honey
-	s.stores.ETOX_HES_C_D0 = s.stores.ETOX_HES_C_D0 * math.Exp(-math.Log(2)/DT50honey)         // Dissappearance of the pesticide in the honey following a single first-order kinetic
-	s.stores.ETOX_HES_C_D1 = s.stores.ETOX_HES_C_D1 * math.Exp(-math.Log(2)/DT50honey)         // Dissappearance of the pesticide in the honey following a single first-order kinetic
-	s.stores.ETOX_HES_C_D2 = s.stores.ETOX_HES_C_D2 * math.Exp(-math.Log(2)/DT50honey)         // Dissappearance of the pesticide in the honey following a single first-order kinetic
-	s.stores.ETOX_HES_C_D3 = s.stores.ETOX_HES_C_D3 * math.Exp(-math.Log(2)/DT50honey)         // Dissappearance of the pesticide in the honey following a single first-order kinetic
-	s.stores.ETOX_HES_C_D4 = s.stores.ETOX_HES_C_D4 * math.Exp(-math.Log(2)/DT50honey)         // Dissappearance of the pesticide in the honey following a single first-order kinetic
-	s.stores.ETOX_HES_C_Capped = s.stores.ETOX_HES_C_Capped * math.Exp(-math.Log(2)/DT50honey) // Dissappearance of the pesticide in the honey following a single first-order kinetic
+	ppp := s.calcPPPhoneytotal(w)
+	s.stores.HoneyResidueAge = residueAge(s.stores.HoneyResidueAge, s.stores.HoneyResidue, ppp)
+	if ppp <= 0 {
+		s.stores.HoneyResidue = 0
+		return
+	}
+	decay := util.DecayFactor(s.honeyKinetics, s.stores.HoneyResidueAge) // Dissappearance of the pesticide in the honey following the configured kinetics
+	s.stores.HoneyResidueAge++
+	s.stores.HoneyResidue = ppp * decay
+	s.pppFate.PPPhoneyDegraded += ppp * (1 - decay)
+
+	for i := range s.honeyStores.Conc {
+		s.honeyStores.Conc[i] *= decay
+	}
+	s.honeyStores.CappedConc *= decay
 }
 
-func (s *EtoxStorages) ShiftHoney(w *ecs.World) {
-	if (s.stores.ETOX_HES_E_Capped + s.stores.ETOX_HES_E_D4) > 0 {
-		s.stores.ETOX_HES_C_Capped = ((s.stores.ETOX_HES_C_Capped * s.stores.ETOX_HES_E_Capped) + (s.stores.ETOX_HES_C_D4 * s.stores.ETOX_HES_E_D4)) / (s.stores.ETOX_HES_E_Capped + s.stores.ETOX_HES_E_D4)
+func (s *EtoxStorages) DegradePollen(w *ecs.World) {
+	ppp := math.Max(s.stores.PPPInHivePollenConc*s.beecsStores.Pollen, 0)
+	s.stores.PollenResidueAge = residueAge(s.stores.PollenResidueAge, s.stores.PollenResidue, ppp)
+	if ppp <= 0 {
+		s.stores.PollenResidue = 0
+		return
 	}
-	s.stores.ETOX_HES_E_Capped = s.stores.ETOX_HES_E_D4 + s.stores.ETOX_HES_E_Capped
-	s.stores.ETOX_HES_C_D4 = s.stores.ETOX_HES_C_D3
-	s.stores.ETOX_HES_E_D4 = s.stores.ETOX_HES_E_D3
-	s.stores.ETOX_HES_C_D3 = s.stores.ETOX_HES_C_D2
-	s.stores.ETOX_HES_E_D3 = s.stores.ETOX_HES_E_D2
-	s.stores.ETOX_HES_C_D2 = s.stores.ETOX_HES_C_D1
-	s.stores.ETOX_HES_E_D2 = s.stores.ETOX_HES_E_D1
-	s.stores.ETOX_HES_C_D1 = s.stores.ETOX_HES_C_D0
-	s.stores.ETOX_HES_E_D1 = s.stores.ETOX_HES_E_D0
-	s.stores.ETOX_HES_E_D0 = 0
-	s.stores.ETOX_HES_C_D0 = 0
-
-	if s.beecsStores.Honey <= 0 || s.stores.ETOX_HES_E_Capped < 0 {
-		s.beecsStores.Honey = 0
-		s.stores.ETOX_HES_E_Capped = 0
-		s.stores.ETOX_HES_E_D0 = 0
-		s.stores.ETOX_HES_E_D1 = 0
-		s.stores.ETOX_HES_E_D2 = 0
-		s.stores.ETOX_HES_E_D3 = 0
-		s.stores.ETOX_HES_E_D4 = 0
-		s.stores.ETOX_HES_C_Capped = 0
-		s.stores.ETOX_HES_C_D0 = 0
-		s.stores.ETOX_HES_C_D1 = 0
-		s.stores.ETOX_HES_C_D2 = 0
-		s.stores.ETOX_HES_C_D3 = 0
-		s.stores.ETOX_HES_C_D4 = 0
+	decay := util.DecayFactor(s.pollenStoreKinetics, s.stores.PollenResidueAge)
+	s.pppFate.PPPpollenDegraded += ppp * (1 - decay)
+	s.stores.PPPInHivePollenConc *= decay
+	s.stores.PollenResidueAge++
+	s.stores.PollenResidue = ppp * decay
+}
+
+// residueAge returns the mean age of the PPP in a store, weighted by the deposits.
+// The increase over the residue left after the last degradation counts as a fresh deposit of age zero;
+// without any PPP left, the age is reset.
+func residueAge(age, residue, ppp float64) float64 {
+	if ppp <= 0 {
+		return 0
+	}
+	if ppp > residue {
+		return age * residue / ppp
 	}
+	return age
+}
+
+// UpdatePollenCompartments removes the pollen consumed today oldest first, degrades the PPP
+// in each compartment according to its age and ages the compartments by one day.
+func (s *EtoxStorages) UpdatePollenCompartments(w *ecs.World) {
+	s.pollenStores.Consume(s.cons.PollenDaily)
 
-	// adjusted this panic to 0.1% acceptable deviation from the honey store in each timestep; 0.1% deemed acceptable because of floating point error
-	if math.Round((s.stores.ETOX_HES_E_Capped+s.stores.ETOX_HES_E_D4+s.stores.ETOX_HES_E_D3+s.stores.ETOX_HES_E_D2+s.stores.ETOX_HES_E_D1+s.stores.ETOX_HES_E_D0))*1.001 < math.Round(s.beecsStores.Honey) ||
-		math.Round((s.stores.ETOX_HES_E_Capped+s.stores.ETOX_HES_E_D4+s.stores.ETOX_HES_E_D3+s.stores.ETOX_HES_E_D2+s.stores.ETOX_HES_E_D1+s.stores.ETOX_HES_E_D0))*0.999 > math.Round(s.beecsStores.Honey) {
-		panic("Fatal error in honey store dose calculations, model output will be wrong!") // should debug why this triggers sometimes on very long simulation runs through small differences once there is time
+	// keep in line with the beecs pollen store, which may have been clamped or changed by other processes
+	mass, _ := s.pollenStores.Total()
+	checkAccounting(s.events, s.time.Tick, "pollen store mass", s.beecsStores.Pollen, mass, s.etox.Tolerance())
+	if mass > s.beecsStores.Pollen {
+		s.pppFate.PPPstoresLost += s.pollenStores.Consume(mass - s.beecsStores.Pollen)
+	} else {
+		s.pollenStores.AddOld(s.beecsStores.Pollen-mass, 0)
+	}
+
+	for i := range s.pollenStores.Conc {
+		decay := util.DecayFactor(s.pollenStoreKinetics, float64(i))
+		s.pppFate.PPPpollenDegraded += s.pollenStores.Mass[i] * s.pollenStores.Conc[i] * (1 - decay)
+		s.pollenStores.Conc[i] *= decay
+	}
+	s.pollenStores.Shift()
+
+	mass, ppp := s.pollenStores.Total()
+	if mass > 0 {
+		s.stores.PPPInHivePollenConc = ppp / mass
+	} else {
+		s.stores.PPPInHivePollenConc = 0
+	}
+}
+
+func (s *EtoxStorages) ShiftHoney(w *ecs.World) {
+	s.honeyStores.Shift(s.etox.CappingFraction())
+
+	if s.beecsStores.Honey <= 0 || s.honeyStores.CappedEnergy < 0 {
+		s.beecsStores.Honey = 0
+		s.pppFate.PPPstoresLost += s.honeyStores.TotalPPP()
+		s.honeyStores.Clear()
 	}
 
+	// the beecs honey store is the reference for the energy in the compartments. Floating point drift gets reconciled in every timestep,
+	// while deviations beyond the tolerance (0.1% by default) are reported as accounting events instead of stopping the model
+	checkAccounting(s.events, s.time.Tick, "honey store energy", s.beecsStores.Honey, s.honeyStores.Total(), s.etox.Tolerance())
+	s.honeyStores.Reconcile(s.beecsStores.Honey)
 }
 
 func (s *EtoxStorages) Finalize(w *ecs.World) {}
@@ -12,6 +12,7 @@ import (
 	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
 	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
 	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
 	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
 )
@@ -30,18 +31,23 @@ type EtoxStoragesNbeecs struct {
 	newCons        *params.ConsumptionRework
 	nursing        *params.NursingRework
 
-	beecsStores *globals.Stores
-	stores      *globals.StoragesEtox
-	pppFate     *globals.PPPFate
-	pop         *globals.PopulationStats
-	etoxStats   *globals.PopulationStatsEtox
-	inHive      *globals.InHive
-	inHiveEtox  *globals.InHiveEtox
-	Larvae      *globals.Larvae
-	LarvaeEtox  *globals.LarvaeEtox
-	cons        *globals.ConsumptionStats
-	nglobals    *globals.NursingGlobals
-	nstats      *globals.NursingStats
+	beecsStores  *globals.Stores
+	stores       *globals.StoragesEtox
+	honeyStores  *globals.HoneyStoresEtox
+	events       *globals.AccountingEvents
+	time         *resource.Tick
+	pollenStores *globals.PollenStoresEtox
+	pppFate      *globals.PPPFate
+	pop          *globals.PopulationStats
+	etoxStats    *globals.PopulationStatsEtox
+	inHive       *globals.InHive
+	inHiveEtox   *globals.InHiveEtox
+	hpg          *globals.HPG
+	Larvae       *globals.Larvae
+	LarvaeEtox   *globals.LarvaeEtox
+	cons         *globals.ConsumptionStats
+	nglobals     *globals.NursingGlobals
+	nstats       *globals.NursingStats
 
 	foragerExpoMapper     *ecs.Map1[comp.PPPExpo]
 	foragerActivityMapper *ecs.Map1[comp.ActivityEtox]
@@ -49,6 +55,11 @@ type EtoxStoragesNbeecs struct {
 	foragerShuffle        []ecs.Entity
 
 	rng *rand.Rand
+
+	honeyKinetics       util.Kinetics
+	pollenStoreKinetics util.Kinetics
+
+	pollenForaged float64 // PPP foraged into the pollen stores until the last update
 }
 
 func (s *EtoxStoragesNbeecs) Initialize(w *ecs.World) {
@@ -60,6 +71,8 @@ func (s *EtoxStoragesNbeecs) Initialize(w *ecs.World) {
 	s.storesParams = ecs.GetResource[params.Stores](w)
 	s.foragerParams = ecs.GetResource[params.Foragers](w)
 	s.etox = ecs.GetResource[params.PPPApplication](w)
+	s.honeyKinetics = s.etox.HoneyKinetics.Kinetics(s.etox.DT50honey)
+	s.pollenStoreKinetics = s.etox.PollenStoreKinetics.Kinetics(0)
 	s.toxic = ecs.GetResource[params.PPPToxicity](w)
 	s.waterParams = ecs.GetResource[params.WaterForaging](w)
 	s.newCons = ecs.GetResource[params.ConsumptionRework](w)
@@ -67,11 +80,16 @@ func (s *EtoxStoragesNbeecs) Initialize(w *ecs.World) {
 
 	s.beecsStores = ecs.GetResource[globals.Stores](w)
 	s.stores = ecs.GetResource[globals.StoragesEtox](w)
+	s.honeyStores = ecs.GetResource[globals.HoneyStoresEtox](w)
+	s.events = ecs.GetResource[globals.AccountingEvents](w)
+	s.time = ecs.GetResource[resource.Tick](w)
+	s.pollenStores = ecs.GetResource[globals.PollenStoresEtox](w)
 	s.pop = ecs.GetResource[globals.PopulationStats](w)
 	s.etoxStats = ecs.GetResource[globals.PopulationStatsEtox](w)
 	s.pppFate = ecs.GetResource[globals.PPPFate](w)
 	s.inHive = ecs.GetResource[globals.InHive](w)
 	s.inHiveEtox = ecs.GetResource[globals.InHiveEtox](w)
+	s.hpg = ecs.GetResource[globals.HPG](w)
 	s.Larvae = ecs.GetResource[globals.Larvae](w)
 	s.LarvaeEtox = ecs.GetResource[globals.LarvaeEtox](w)
 	s.cons = ecs.GetResource[globals.ConsumptionStats](w)
@@ -82,7 +100,8 @@ func (s *EtoxStoragesNbeecs) Initialize(w *ecs.World) {
 	s.foragerActivityMapper = s.foragerActivityMapper.New(w)
 	s.foragerFilter = s.foragerFilter.New(w)
 
-	s.rng = rand.New(ecs.GetResource[resource.Rand](w))
+	s.rng = rand.New(ecs.GetResource[globals.RandStreams](w).Get(randInHiveExposure))
+	s.pollenForaged = 0
 }
 
 func (s *EtoxStoragesNbeecs) Update(w *ecs.World) {
@@ -107,24 +126,23 @@ func (s *EtoxStoragesNbeecs) Update(w *ecs.World) {
 		s.stores.ETOX_EnergyThermo = float64(s.pop.TotalBrood) * thermoRegBrood * 0.001 * s.energyParams.Honey // or calculate the total necessary energy
 	}
 
+	if s.etox.PollenCompartments {
+		s.stores.PPPInHivePollenConc = s.pollenStores.ConsumptionConc(s.cons.PollenDaily) // pollen is eaten oldest first
+	} else if s.beecsStores.Pollen <= 0 && s.cons.PollenDaily > 0 {
+		// the pollen store ran empty today; the PPP eaten beyond the content of the store is either prevented or recorded
+		available := s.stores.PPPpollenTotal + s.pppFate.PPPpollenStores - s.pollenForaged
+		if s.etox.PollenEmptyfix {
+			s.stores.PPPInHivePollenConc = math.Min(s.stores.PPPInHivePollenConc, available/s.cons.PollenDaily)
+		} else {
+			s.pppFate.PPPpollenExcess += math.Max(s.stores.PPPInHivePollenConc*s.cons.PollenDaily-available, 0)
+		}
+	}
+	s.pollenForaged = s.pppFate.PPPpollenStores
+
 	// get values for some observing/debugging variables
 	s.stores.Pollenconcbeforeeating = s.stores.PPPInHivePollenConc // used in debugging and as a helpful metric
 
-	if s.stores.ETOX_HES_E_D0 != 0 {
-		s.stores.Nectarconcbeforeeating = s.stores.ETOX_HES_C_D0 // used in debugging and as a helpful metric
-	} else if s.stores.ETOX_HES_E_D1 != 0 {
-		s.stores.Nectarconcbeforeeating = s.stores.ETOX_HES_C_D1 // used in debugging and as a helpful metric
-	} else if s.stores.ETOX_HES_E_D2 != 0 {
-		s.stores.Nectarconcbeforeeating = s.stores.ETOX_HES_C_D2 // used in debugging and as a helpful metric
-	} else if s.stores.ETOX_HES_E_D3 != 0 {
-		s.stores.Nectarconcbeforeeating = s.stores.ETOX_HES_C_D3 // used in debugging and as a helpful metric
-	} else if s.stores.ETOX_HES_E_D4 != 0 {
-		s.stores.Nectarconcbeforeeating = s.stores.ETOX_HES_C_D4 // used in debugging and as a helpful metric
-	} else if s.stores.ETOX_HES_E_Capped != 0 {
-		s.stores.Nectarconcbeforeeating = s.stores.ETOX_HES_C_Capped // used in debugging and as a helpful metric
-	} else {
-		s.stores.Nectarconcbeforeeating = 0 // used in debugging and as a helpful metric
-	}
+	s.stores.Nectarconcbeforeeating = s.honeyStores.NextConc(s.etox.HoneyOldestFirst) // used in debugging and as a helpful metric
 
 	// forager oral intake, pretty straigt forward and same for all model versions
 	forquery := s.foragerFilter.Query()
@@ -170,6 +188,7 @@ func (s *EtoxStoragesNbeecs) Update(w *ecs.World) {
 
 			pollentoeat := s.nglobals.CurrentMaxPollenNurse * s.nglobals.NurseWorkLoad * float64(s.foragerParams.SquadronSize)
 			s.etoxStats.CumDoseNurses += pollentoeat * s.stores.PPPInHivePollenConc * 0.001
+			s.pppFate.PPPNurses += pollentoeat * s.stores.PPPInHivePollenConc * 0.001
 
 			honeytoeat := (s.nglobals.Total_honey * (pollentoeat / s.nglobals.Total_pollen)) // consumed honey is calculated via the fraction of total pollen that this squadron ate because NurseWorkLoad is only coupled to consumed pollen
 
@@ -192,6 +211,7 @@ func (s *EtoxStoragesNbeecs) Update(w *ecs.World) {
 
 			pollentoeat := s.nglobals.CurrentMaxPollenNurse * s.nglobals.NurseWorkLoad * float64(s.foragerParams.SquadronSize)
 			s.etoxStats.CumDoseNurses += pollentoeat * s.stores.PPPInHivePollenConc * 0.001
+			s.pppFate.PPPNurses += pollentoeat * s.stores.PPPInHivePollenConc * 0.001
 
 			honeytoeat := (s.nglobals.Total_honey * (pollentoeat / s.nglobals.Total_pollen)) // consumed honey is calculated via the fraction of total pollen that this squadron ate because NurseWorkLoad is only coupled to consumed pollen
 
@@ -229,6 +249,7 @@ func (s *EtoxStoragesNbeecs) Update(w *ecs.World) {
 	consumed_pollen += p
 	s.etoxStats.CumDoseNurses += IHnurseIntake
 	s.pppFate.PPPNurses += IHnurseIntake
+	copy(s.hpg.Dose, s.inHiveEtox.WorkerCohortDose) // drives the HPG state in NursingNeeds next step
 
 	s.etoxStats.MeanDoseIHBees = 0.
 	s.etoxStats.MeanDoseNurses = 0.
@@ -240,6 +261,15 @@ func (s *EtoxStoragesNbeecs) Update(w *ecs.World) {
 	}
 
 	if !s.nglobals.AbortNursing { // no feeding anymore if there are no nurses
+		// PPP concentrations of the jelly nurses produce from honey and pollen, for comparison with residue studies.
+		// Honey and pollen that larvae take in directly from the stores are not part of the jelly.
+		honeyConc := s.stores.Nectarconcbeforeeating * 0.001 * s.energyParams.Honey
+		pollenConc := s.stores.PPPInHivePollenConc * 0.001
+		honey, pollen := nurseProcessedFood(s.Larvae.Workers, s.newCons.HoneyWorkerLarva, s.newCons.PollenWorkerLarva, s.newCons.HoneyDirect, s.newCons.PollenDirect)
+		s.etoxStats.WorkerJellyConc = jellyConcentration(honey, pollen, honeyConc, pollenConc, s.toxic.NursebeesNectar, s.toxic.NursebeesPollen)
+		honey, pollen = nurseProcessedFood(s.Larvae.Drones, s.newCons.HoneyDroneLarva, s.newCons.PollenDroneLarva, s.newCons.HoneyDirect, s.newCons.PollenDirect)
+		s.etoxStats.DroneJellyConc = jellyConcentration(honey, pollen, honeyConc, pollenConc, s.toxic.NursebeesNectar, s.toxic.NursebeesPollen)
+
 		// continue with larvae here
 		s.etoxStats.CumDoseLarvae, _, h, p, num = s.CalcDosePerCohortNursingWLarvae(w, s.Larvae.Workers, s.LarvaeEtox.WorkerCohortDose, s.nglobals.WLHoney, s.nglobals.WLPollen)
 		if s.pop.WorkerLarvae > 0 {
@@ -276,31 +306,31 @@ func (s *EtoxStoragesNbeecs) Update(w *ecs.World) {
 	if s.etox.DegradationHoney {
 		s.DegradeHoney(w)
 	}
+	if s.etox.PollenCompartments {
+		s.UpdatePollenCompartments(w)
+	} else {
+		s.DegradePollen(w)
+	}
 
 	// leftovers from debugging
 	_ = s.pop.DroneLarvae + s.pop.DronesInHive + s.pop.WorkerLarvae + s.pop.WorkersForagers + s.pop.WorkersInHive + forcount
-	// checkpoint for bugfixing honey consumption in etox
-	if math.Round(consumed_honey-(s.cons.HoneyDaily*0.001*s.energyParams.Honey)) != 0 || math.Round(consumed_pollen/1000.0-s.cons.PollenDaily) != 0 {
-		panic("Fatal error in honey store dose calculations, model output will be wrong!")
-	}
+	// checkpoint for honey and pollen consumption in etox; deviations get reported instead of stopping the model
+	checkAccounting(s.events, s.time.Tick, "honey consumption", s.cons.HoneyDaily*0.001*s.energyParams.Honey, consumed_honey, s.etox.Tolerance())
+	checkAccounting(s.events, s.time.Tick, "pollen consumption", s.cons.PollenDaily, consumed_pollen/1000.0, s.etox.Tolerance())
 
 	s.ShiftHoney(w)
 
-	s.stores.PPPpollenTotal = s.stores.PPPInHivePollenConc * s.beecsStores.Pollen
+	if s.etox.PollenCompartments {
+		_, s.stores.PPPpollenTotal = s.pollenStores.Total()
+	} else {
+		s.stores.PPPpollenTotal = s.stores.PPPInHivePollenConc * s.beecsStores.Pollen
+	}
 	s.stores.PPPhoneyTotal = s.calcPPPhoneytotal(w)
 	s.stores.PPPTotal = s.stores.PPPpollenTotal + s.stores.PPPhoneyTotal
 }
 
 func (s *EtoxStoragesNbeecs) calcPPPhoneytotal(w *ecs.World) (totalPPP float64) {
-	totalPPP = 0.
-	totalPPP += s.stores.ETOX_HES_C_D0 * s.stores.ETOX_HES_E_D0
-	totalPPP += s.stores.ETOX_HES_C_D1 * s.stores.ETOX_HES_E_D1
-	totalPPP += s.stores.ETOX_HES_C_D2 * s.stores.ETOX_HES_E_D2
-	totalPPP += s.stores.ETOX_HES_C_D3 * s.stores.ETOX_HES_E_D3
-	totalPPP += s.stores.ETOX_HES_C_D4 * s.stores.ETOX_HES_E_D4
-	totalPPP += s.stores.ETOX_HES_C_Capped * s.stores.ETOX_HES_E_Capped
-
-	return
+	return s.honeyStores.TotalPPP()
 }
 
 func (s *EtoxStoragesNbeecs) CalcDosePerCohortNursing(w *ecs.World, coh []int, dose []float64, init_honeyenergy float64, ownHoneyNeed float64, total_honey float64, total_pollen float64) (CumDose float64, Ncohortcounter int, consumed float64, pconsumedtotal float64, num int) {
@@ -320,8 +350,8 @@ func (s *EtoxStoragesNbeecs) CalcDosePerCohortNursing(w *ecs.World, coh []int, d
 			num += coh[i]
 			init_honeyenergy = 0.
 
-			pconsumed := s.nglobals.CurrentMaxPollenNurse * s.newCons.Nursingcapabiliies[i] * s.nglobals.NurseWorkLoad * float64(coh[i])
-			ETOX_PPPOralDose += s.stores.PPPInHivePollenConc * 0.001 * (s.nglobals.CurrentMaxPollenNurse*s.newCons.Nursingcapabiliies[i]*s.nglobals.NurseWorkLoad + s.newCons.PollenAdultWorker) // intake from pollen
+			pconsumed := s.nglobals.CurrentMaxPollenNurse * s.nglobals.Capability[i] * s.nglobals.NurseWorkLoad * float64(coh[i])
+			ETOX_PPPOralDose += s.stores.PPPInHivePollenConc * 0.001 * (s.nglobals.CurrentMaxPollenNurse*s.nglobals.Capability[i]*s.nglobals.NurseWorkLoad + s.newCons.PollenAdultWorker) // intake from pollen
 			pconsumedtotal += (pconsumed + s.newCons.PollenAdultWorker*float64(coh[i]))
 
 			fraction_consumed := 0.
@@ -341,9 +371,8 @@ func (s *EtoxStoragesNbeecs) CalcDosePerCohortNursing(w *ecs.World, coh []int, d
 		}
 	}
 	total_pollen += s.newCons.PollenAdultWorker * float64(num)
-	if math.Round(pconsumedtotal-total_pollen) != 0 || math.Round(hconsumed-total_honey) != 0 {
-		panic("Fatal error in dose calculations, model output will be wrong!")
-	}
+	checkAccounting(s.events, s.time.Tick, "nurse pollen consumption", total_pollen, pconsumedtotal, s.etox.Tolerance())
+	checkAccounting(s.events, s.time.Tick, "nurse honey consumption", total_honey, hconsumed, s.etox.Tolerance())
 	return
 }
 
@@ -355,7 +384,7 @@ func (s *EtoxStoragesNbeecs) CalcDosePerCohortHPGWorkers(w *ecs.World, coh []int
 	num = 0
 	pconsumed = 0.
 
-	order := rand.Perm(len(coh)) // randomize order to further emulate NetLogo ask function
+	order := s.rng.Perm(len(coh)) // randomize order to further emulate NetLogo ask function
 	for _, i := range order {
 		if i >= 4 && i <= s.nglobals.NurseAgeMax { // exclude nurses here
 			continue
@@ -395,8 +424,8 @@ func (s *EtoxStoragesNbeecs) CalcDosePerCohortNursingWLarvae(w *ecs.World, coh [
 	pconsumed = 0.
 	num = 0
 
-	order := rand.Perm(len(coh)) // randomize order to further emulate NetLogo ask function
-	for _, i := range order {    // range of a slice iterates over 2 values, first the index and then a copy of the actual val that we use as the index within the for loop
+	order := s.rng.Perm(len(coh)) // randomize order to further emulate NetLogo ask function
+	for _, i := range order {     // range of a slice iterates over 2 values, first the index and then a copy of the actual val that we use as the index within the for loop
 		ETOX_PPPOralDose := 0.
 		ETOX_Consumed_Honey := 0.
 		num += coh[i]
@@ -423,9 +452,8 @@ func (s *EtoxStoragesNbeecs) CalcDosePerCohortNursingWLarvae(w *ecs.World, coh [
 		}
 
 	}
-	if math.Round((consumed/(0.001*s.energyParams.Honey))-honey) != 0 || math.Round(pconsumed-pollen) != 0 {
-		panic("Fatal error in dose calculations, model output will be wrong!")
-	}
+	checkAccounting(s.events, s.time.Tick, "worker larvae honey consumption", honey, consumed/(0.001*s.energyParams.Honey), s.etox.Tolerance())
+	checkAccounting(s.events, s.time.Tick, "worker larvae pollen consumption", pollen, pconsumed, s.etox.Tolerance())
 	return
 }
 
@@ -436,8 +464,8 @@ func (s *EtoxStoragesNbeecs) CalcDosePerCohortNursingDLarvae(w *ecs.World, coh [
 	pconsumed = 0.
 	num = 0
 
-	order := rand.Perm(len(coh)) // randomize order to further emulate NetLogo ask function
-	for _, i := range order {    // range of a slice iterates over 2 values, first the index and then a copy of the actual val that we use as the index within the for loop
+	order := s.rng.Perm(len(coh)) // randomize order to further emulate NetLogo ask function
+	for _, i := range order {     // range of a slice iterates over 2 values, first the index and then a copy of the actual val that we use as the index within the for loop
 		ETOX_PPPOralDose := 0.
 		ETOX_Consumed_Honey := 0.
 		num += coh[i]
@@ -463,9 +491,8 @@ func (s *EtoxStoragesNbeecs) CalcDosePerCohortNursingDLarvae(w *ecs.World, coh [
 			dose[i] = 0.
 		}
 	}
-	if math.Round((consumed/(0.001*s.energyParams.Honey))-honey) != 0 || math.Round(pconsumed-pollen) != 0 {
-		panic("Fatal error in dose calculations, model output will be wrong!")
-	}
+	checkAccounting(s.events, s.time.Tick, "drone larvae honey consumption", honey, consumed/(0.001*s.energyParams.Honey), s.etox.Tolerance())
+	checkAccounting(s.events, s.time.Tick, "drone larvae pollen consumption", pollen, pconsumed, s.etox.Tolerance())
 	return
 }
 
@@ -477,7 +504,7 @@ func (s *EtoxStoragesNbeecs) CalcDosePerCohort(w *ecs.World, coh []int, dose []f
 	num = 0
 	pconsumed = 0.
 
-	order := rand.Perm(len(coh)) // randomize order to further emulate NetLogo ask function
+	order := s.rng.Perm(len(coh)) // randomize order to further emulate NetLogo ask function
 	for _, i := range order {
 		ETOX_PPPOralDose := 0.
 		ETOX_Consumed_Honey := init_honeyenergy
@@ -493,6 +520,9 @@ func (s *EtoxStoragesNbeecs) CalcDosePerCohort(w *ecs.World, coh []int, dose []f
 			if s.etox.Nursebeefix {
 				s.etoxStats.PPPNursebees += ETOX_PPPOralDose * (1 - nursebeefactorHoney) * float64(coh[i])
 				s.etoxStats.PPPNursebees += s.stores.PPPInHivePollenConc * pollen_need * 0.001 * (1 - nursebeefactorPollen) * float64(coh[i])
+			} else { // the PPP filtered by the nurse bees dissipates, as in BEEHAVE_ecotox
+				s.pppFate.PPPdissipatedNurses += ETOX_PPPOralDose * (1 - nursebeefactorHoney) * float64(coh[i])
+				s.pppFate.PPPdissipatedNurses += s.stores.PPPInHivePollenConc * pollen_need * 0.001 * (1 - nursebeefactorPollen) * float64(coh[i])
 			}
 			ETOX_PPPOralDose = ETOX_PPPOralDose * nursebeefactorHoney
 			ETOX_PPPOralDose += s.stores.PPPInHivePollenConc * pollen_need * 0.001 * nursebeefactorPollen // intake from pollen
@@ -510,118 +540,89 @@ func (s *EtoxStoragesNbeecs) CalcDosePerCohort(w *ecs.World, coh []int, dose []f
 }
 
 func (s *EtoxStoragesNbeecs) FeedOnHoneyStores(w *ecs.World, cons float64, number float64, honeydilution bool) (OralDose float64) {
-	OralDose = 0.
-	if cons < s.stores.ETOX_HES_E_D0 {
-		OralDose += cons * s.stores.ETOX_HES_C_D0 / number
-		s.stores.ETOX_HES_E_D0 -= cons
-	} else {
-		OralDose += s.stores.ETOX_HES_E_D0 * s.stores.ETOX_HES_C_D0 / number
-		cons -= s.stores.ETOX_HES_E_D0
-		s.stores.ETOX_HES_E_D0 = 0
+	ppp, capped := s.honeyStores.Feed(cons, s.etox.HoneyOldestFirst)
+	if honeydilution { // capped honey gets diluted with water, which may carry PPP from contaminated water sources
+		water := capped / s.energyParams.Honey / s.etox.ETOXDensityOfHoney * 0.6
+		s.stores.ETOX_Waterneedfordilution += water
+		ppp += s.stores.UseWater(water)
+	}
+	return ppp / number
+}
 
-		if cons < s.stores.ETOX_HES_E_D1 {
-			OralDose += cons * s.stores.ETOX_HES_C_D1 / number
-			s.stores.ETOX_HES_E_D1 -= cons
-		} else {
-			OralDose += s.stores.ETOX_HES_E_D1 * s.stores.ETOX_HES_C_D1 / number
-			cons -= s.stores.ETOX_HES_E_D1
-			s.stores.ETOX_HES_E_D1 = 0
+func (s *EtoxStoragesNbeecs) DegradeHoney(w *ecs.World) {
+	ppp := s.calcPPPhoneytotal(w)
+	s.stores.HoneyResidueAge = residueAge(s.stores.HoneyResidueAge, s.stores.HoneyResidue, ppp)
+	if ppp <= 0 {
+		s.stores.HoneyResidue = 0
+		return
+	}
+	decay := util.DecayFactor(s.honeyKinetics, s.stores.HoneyResidueAge) // Dissappearance of the pesticide in the honey following the configured kinetics
+	s.stores.HoneyResidueAge++
+	s.stores.HoneyResidue = ppp * decay
+	s.pppFate.PPPhoneyDegraded += ppp * (1 - decay)
 
-			if cons < s.stores.ETOX_HES_E_D2 {
-				OralDose += cons * s.stores.ETOX_HES_C_D2 / number
-				s.stores.ETOX_HES_E_D2 -= cons
-			} else {
-				OralDose += s.stores.ETOX_HES_E_D1 * s.stores.ETOX_HES_C_D2 / number
-				cons -= s.stores.ETOX_HES_E_D2
-				s.stores.ETOX_HES_E_D2 = 0
-
-				if cons < s.stores.ETOX_HES_E_D3 {
-					OralDose += cons * s.stores.ETOX_HES_C_D3 / number
-					s.stores.ETOX_HES_E_D3 -= cons
-				} else {
-					OralDose += s.stores.ETOX_HES_E_D3 * s.stores.ETOX_HES_C_D3 / number
-					cons -= s.stores.ETOX_HES_E_D3
-					s.stores.ETOX_HES_E_D3 = 0
-
-					if cons < s.stores.ETOX_HES_E_D4 {
-						OralDose += cons * s.stores.ETOX_HES_C_D4 / number
-						s.stores.ETOX_HES_E_D4 -= cons
-					} else {
-						OralDose += s.stores.ETOX_HES_E_D4 * s.stores.ETOX_HES_C_D4 / number
-						cons -= s.stores.ETOX_HES_E_D4
-						s.stores.ETOX_HES_E_D4 = 0
-
-						if cons < s.stores.ETOX_HES_E_Capped {
-							OralDose += cons * s.stores.ETOX_HES_C_Capped / number
-							s.stores.ETOX_HES_E_Capped -= cons
-							if honeydilution {
-								s.stores.ETOX_Waterneedfordilution += cons / s.energyParams.Honey / s.etox.ETOXDensityOfHoney * 0.6
-							}
-						} else {
-							OralDose += s.stores.ETOX_HES_E_Capped * s.stores.ETOX_HES_C_Capped / number
-							cons -= s.stores.ETOX_HES_E_Capped
-							if honeydilution {
-								s.stores.ETOX_Waterneedfordilution += s.stores.ETOX_HES_E_Capped / s.energyParams.Honey / s.etox.ETOXDensityOfHoney * 0.6
-							}
-							s.stores.ETOX_HES_E_Capped = 0
-						}
-					}
-				}
-			}
-		}
+	for i := range s.honeyStores.Conc {
+		s.honeyStores.Conc[i] *= decay
 	}
-	return
+	s.honeyStores.CappedConc *= decay
 }
 
-func (s *EtoxStoragesNbeecs) DegradeHoney(w *ecs.World) {
-	// if this ever is to be turned on PPPfate oberver should be introduced here if someone wanted to create a mass balance again
-	DT50honey := s.etox.DT50honey
-	s.stores.ETOX_HES_C_D0 = s.stores.ETOX_HES_C_D0 * math.Exp(-math.Log(2)/DT50honey)         // Dissappearance of the pesticide in the honey following a single first-order kinetic
-	s.stores.ETOX_HES_C_D1 = s.stores.ETOX_HES_C_D1 * math.Exp(-math.Log(2)/DT50honey)         // Dissappearance of the pesticide in the honey following a single first-order kinetic
-	s.stores.ETOX_HES_C_D2 = s.stores.ETOX_HES_C_D2 * math.Exp(-math.Log(2)/DT50honey)         // Dissappearance of the pesticide in the honey following a single first-order kinetic
-	s.stores.ETOX_HES_C_D3 = s.stores.ETOX_HES_C_D3 * math.Exp(-math.Log(2)/DT50honey)         // Dissappearance of the pesticide in the honey following a single first-order kinetic
-	s.stores.ETOX_HES_C_D4 = s.stores.ETOX_HES_C_D4 * math.Exp(-math.Log(2)/DT50honey)         // Dissappearance of the pesticide in the honey following a single first-order kinetic
-	s.stores.ETOX_HES_C_Capped = s.stores.ETOX_HES_C_Capped * math.Exp(-math.Log(2)/DT50honey) // Dissappearance of the pesticide in the honey following a single first-order kinetic
+func (s *EtoxStoragesNbeecs) DegradePollen(w *ecs.World) {
+	ppp := math.Max(s.stores.PPPInHivePollenConc*s.beecsStores.Pollen, 0)
+	s.stores.PollenResidueAge = residueAge(s.stores.PollenResidueAge, s.stores.PollenResidue, ppp)
+	if ppp <= 0 {
+		s.stores.PollenResidue = 0
+		return
+	}
+	decay := util.DecayFactor(s.pollenStoreKinetics, s.stores.PollenResidueAge)
+	s.pppFate.PPPpollenDegraded += ppp * (1 - decay)
+	s.stores.PPPInHivePollenConc *= decay
+	s.stores.PollenResidueAge++
+	s.stores.PollenResidue = ppp * decay
 }
 
-func (s *EtoxStoragesNbeecs) ShiftHoney(w *ecs.World) {
-	if (s.stores.ETOX_HES_E_Capped + s.stores.ETOX_HES_E_D4) > 0 {
-		s.stores.ETOX_HES_C_Capped = ((s.stores.ETOX_HES_C_Capped * s.stores.ETOX_HES_E_Capped) + (s.stores.ETOX_HES_C_D4 * s.stores.ETOX_HES_E_D4)) / (s.stores.ETOX_HES_E_Capped + s.stores.ETOX_HES_E_D4)
+// UpdatePollenCompartments removes the pollen consumed today oldest first, degrades the PPP
+// in each compartment according to its age and ages the compartments by one day.
+func (s *EtoxStoragesNbeecs) UpdatePollenCompartments(w *ecs.World) {
+	s.pollenStores.Consume(s.cons.PollenDaily)
+
+	// keep in line with the beecs pollen store, which may have been clamped or changed by other processes
+	mass, _ := s.pollenStores.Total()
+	checkAccounting(s.events, s.time.Tick, "pollen store mass", s.beecsStores.Pollen, mass, s.etox.Tolerance())
+	if mass > s.beecsStores.Pollen {
+		s.pppFate.PPPstoresLost += s.pollenStores.Consume(mass - s.beecsStores.Pollen)
+	} else {
+		s.pollenStores.AddOld(s.beecsStores.Pollen-mass, 0)
 	}
-	s.stores.ETOX_HES_E_Capped = s.stores.ETOX_HES_E_D4 + s.stores.ETOX_HES_E_Capped
-	s.stores.ETOX_HES_C_D4 = s.stores.ETOX_HES_C_D3
-	s.stores.ETOX_HES_E_D4 = s.stores.ETOX_HES_E_D3
-	s.stores.ETOX_HES_C_D3 = s.stores.ETOX_HES_C_D2
-	s.stores.ETOX_HES_E_D3 = s.stores.ETOX_HES_E_D2
-	s.stores.ETOX_HES_C_D2 = s.stores.ETOX_HES_C_D1
-	s.stores.ETOX_HES_E_D2 = s.stores.ETOX_HES_E_D1
-	s.stores.ETOX_HES_C_D1 = s.stores.ETOX_HES_C_D0
-	s.stores.ETOX_HES_E_D1 = s.stores.ETOX_HES_E_D0
-	s.stores.ETOX_HES_E_D0 = 0
-	s.stores.ETOX_HES_C_D0 = 0
-
-	if s.beecsStores.Honey <= 0 || s.stores.ETOX_HES_E_Capped < 0 {
-		s.beecsStores.Honey = 0
-		s.stores.ETOX_HES_E_Capped = 0
-		s.stores.ETOX_HES_E_D0 = 0
-		s.stores.ETOX_HES_E_D1 = 0
-		s.stores.ETOX_HES_E_D2 = 0
-		s.stores.ETOX_HES_E_D3 = 0
-		s.stores.ETOX_HES_E_D4 = 0
-		s.stores.ETOX_HES_C_Capped = 0
-		s.stores.ETOX_HES_C_D0 = 0
-		s.stores.ETOX_HES_C_D1 = 0
-		s.stores.ETOX_HES_C_D2 = 0
-		s.stores.ETOX_HES_C_D3 = 0
-		s.stores.ETOX_HES_C_D4 = 0
+
+	for i := range s.pollenStores.Conc {
+		decay := util.DecayFactor(s.pollenStoreKinetics, float64(i))
+		s.pppFate.PPPpollenDegraded += s.pollenStores.Mass[i] * s.pollenStores.Conc[i] * (1 - decay)
+		s.pollenStores.Conc[i] *= decay
+	}
+	s.pollenStores.Shift()
+
+	mass, ppp := s.pollenStores.Total()
+	if mass > 0 {
+		s.stores.PPPInHivePollenConc = ppp / mass
+	} else {
+		s.stores.PPPInHivePollenConc = 0
 	}
+}
 
-	// adjusted this panic to 0.1% acceptable deviation from the honey store in each timestep; 0.1% deemed acceptable because of floating point error
-	if math.Round((s.stores.ETOX_HES_E_Capped+s.stores.ETOX_HES_E_D4+s.stores.ETOX_HES_E_D3+s.stores.ETOX_HES_E_D2+s.stores.ETOX_HES_E_D1+s.stores.ETOX_HES_E_D0))*1.001 < math.Round(s.beecsStores.Honey) ||
-		math.Round((s.stores.ETOX_HES_E_Capped+s.stores.ETOX_HES_E_D4+s.stores.ETOX_HES_E_D3+s.stores.ETOX_HES_E_D2+s.stores.ETOX_HES_E_D1+s.stores.ETOX_HES_E_D0))*0.999 > math.Round(s.beecsStores.Honey) {
-		panic("Fatal error in honey store dose calculations, model output will be wrong!") // should debug why this triggers sometimes on very long simulation runs through small differences once there is time
+func (s *EtoxStoragesNbeecs) ShiftHoney(w *ecs.World) {
+	s.honeyStores.Shift(s.etox.CappingFraction())
+
+	if s.beecsStores.Honey <= 0 || s.honeyStores.CappedEnergy < 0 {
+		s.beecsStores.Honey = 0
+		s.pppFate.PPPstoresLost += s.honeyStores.TotalPPP()
+		s.honeyStores.Clear()
 	}
 
+	// the beecs honey store is the reference for the energy in the compartments. Floating point drift gets reconciled in every timestep,
+	// while deviations beyond the tolerance (0.1% by default) are reported as accounting events instead of stopping the model
+	checkAccounting(s.events, s.time.Tick, "honey store energy", s.beecsStores.Honey, s.honeyStores.Total(), s.etox.Tolerance())
+	s.honeyStores.Reconcile(s.beecsStores.Honey)
 }
 
 func (s *EtoxStoragesNbeecs) Finalize(w *ecs.World) {}
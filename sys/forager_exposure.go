@@ -0,0 +1,37 @@
+package sys
+
+import (
+	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// foragerExposureRecorder keeps the lifetime exposure of forager squadrons that are removed from the world.
+// It does nothing in models without the _ecotox resources.
+type foragerExposureRecorder struct {
+	records *globals.ForagerExposureRecords
+	time    *resource.Tick
+	mapper  *ecs.Map2[comp.PPPExpo, comp.ActivityEtox]
+}
+
+func newForagerExposureRecorder(w *ecs.World) foragerExposureRecorder {
+	res := ecs.NewResource[globals.ForagerExposureRecords](w)
+	if !res.Has() {
+		return foragerExposureRecorder{}
+	}
+	return foragerExposureRecorder{
+		records: res.Get(),
+		time:    ecs.GetResource[resource.Tick](w),
+		mapper:  ecs.NewMap2[comp.PPPExpo, comp.ActivityEtox](w),
+	}
+}
+
+// record the exposure of a squadron before it is removed.
+func (r *foragerExposureRecorder) record(e ecs.Entity) {
+	if r.records == nil || !r.mapper.HasAll(e) {
+		return
+	}
+	expo, act := r.mapper.Get(e)
+	r.records.Dead = append(r.records.Dead, globals.NewForagerExposure(r.time.Tick, expo, act.Reverted, false))
+}
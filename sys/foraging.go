@@ -109,7 +109,7 @@ func (s *Foraging) Initialize(w *ecs.World) {
 
 	s.WinterBeeAdder = s.WinterBeeAdder.New(w)
 	s.maxHoneyStore = storeParams.MaxHoneyStoreKg * 1000.0 * energyParams.Honey
-	s.rng = rand.New(ecs.GetResource[resource.Rand](w))
+	s.rng = rand.New(ecs.GetResource[globals.RandStreams](w).Get(randForaging))
 	s.time = ecs.GetResource[resource.Tick](w)
 }
 
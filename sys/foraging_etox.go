@@ -18,8 +18,11 @@ import (
 // Foraging performs the complete foraging process of each day.
 // It potentially performs multiple foraging rounds per day.
 type ForagingEtox struct {
-	rng  *rand.Rand
-	time *resource.Tick
+	rng         *rand.Rand
+	sensitivity *rand.Rand // individual sensitivity of new squadrons
+	homing      *rand.Rand // sublethal homing failures
+	time        *resource.Tick
+	exposure    foragerExposureRecorder
 
 	foragerParams      *params.Foragers
 	forageParams       *params.Foraging
@@ -35,10 +38,13 @@ type ForagingEtox struct {
 	foragePeriod  *globals.ForagingPeriod
 	stores        *globals.Stores
 	storesEtox    *globals.StoragesEtox
+	honeyStores   *globals.HoneyStoresEtox
+	pollenStores  *globals.PollenStoresEtox
 	foragingStats *globals.ForagingStatsEtox
 	pppfate       *globals.PPPFate
 	pop           *globals.PopulationStats
 	newCohorts    *globals.NewCohorts
+	inHiveEtox    *globals.InHiveEtox
 	aff           *globals.AgeFirstForaging
 	factory       *globals.ForagerFactory
 
@@ -56,6 +62,7 @@ type ForagingEtox struct {
 	patchVisitsMapper     *ecs.Map2[comp.Resource, comp.Visits]
 	patchDanceMapper      *ecs.Map2[comp.Resource, comp.Dance]
 	patchTripMapper       *ecs.Map1[comp.Trip]
+	patchHandlingMapper   *ecs.Map1[comp.HandlingTime]
 	patchMortalityMapper  *ecs.Map1[comp.Mortality]
 	patchConfigMapper     *ecs.Map2[comp.PatchProperties, comp.Trip]
 	patchConfigMapperEtox *ecs.Map3[comp.PatchProperties, comp.PatchPropertiesEtox, comp.Trip]
@@ -73,10 +80,17 @@ type ForagingEtox struct {
 	patchFilter          *ecs.Filter2[comp.Resource, comp.PatchProperties]
 	patchUpdateFilter    *ecs.Filter7[comp.PatchProperties, comp.PatchDistance, comp.Resource, comp.HandlingTime, comp.Trip, comp.Mortality, comp.Dance]
 
+	oralResponse    util.DoseResponse
+	contactResponse util.DoseResponse
+	homingResponse  util.DoseResponse
+	handlingEffect  util.DoseResponse
+	carryOver       float64
+
 	maxHoneyStore float64
 }
 
 func (s *ForagingEtox) Initialize(w *ecs.World) {
+	s.exposure = newForagerExposureRecorder(w)
 	s.foragerParams = ecs.GetResource[params.Foragers](w)
 	s.forageParams = ecs.GetResource[params.Foraging](w)
 	s.handlingTimeParams = ecs.GetResource[params.HandlingTime](w)
@@ -87,14 +101,22 @@ func (s *ForagingEtox) Initialize(w *ecs.World) {
 
 	s.etox = ecs.GetResource[params.PPPApplication](w)
 	s.toxic = ecs.GetResource[params.PPPToxicity](w)
+	s.oralResponse = s.toxic.ForagerOralResponse()
+	s.contactResponse = s.toxic.ForagerContactResponse()
+	s.homingResponse = s.toxic.HomingFailureResponse()
+	s.handlingEffect = s.toxic.HandlingResponse()
+	s.carryOver = s.toxic.SublethalCarryOver()
 
 	s.foragingStats = ecs.GetResource[globals.ForagingStatsEtox](w)
 	s.foragePeriod = ecs.GetResource[globals.ForagingPeriod](w)
 	s.stores = ecs.GetResource[globals.Stores](w)
 	s.storesEtox = ecs.GetResource[globals.StoragesEtox](w)
+	s.honeyStores = ecs.GetResource[globals.HoneyStoresEtox](w)
+	s.pollenStores = ecs.GetResource[globals.PollenStoresEtox](w)
 	s.pppfate = ecs.GetResource[globals.PPPFate](w)
 	s.pop = ecs.GetResource[globals.PopulationStats](w)
 	s.newCohorts = ecs.GetResource[globals.NewCohorts](w)
+	s.inHiveEtox = ecs.GetResource[globals.InHiveEtox](w)
 	s.aff = ecs.GetResource[globals.AgeFirstForaging](w)
 	s.factory = ecs.GetResource[globals.ForagerFactory](w)
 
@@ -113,6 +135,7 @@ func (s *ForagingEtox) Initialize(w *ecs.World) {
 	s.patchVisitsMapper = s.patchVisitsMapper.New(w)
 	s.patchDanceMapper = s.patchDanceMapper.New(w)
 	s.patchTripMapper = s.patchTripMapper.New(w)
+	s.patchHandlingMapper = s.patchHandlingMapper.New(w)
 	s.patchMortalityMapper = s.patchMortalityMapper.New(w)
 	s.patchConfigMapper = s.patchConfigMapper.New(w)
 	s.patchConfigMapperEtox = s.patchConfigMapperEtox.New(w)
@@ -124,7 +147,9 @@ func (s *ForagingEtox) Initialize(w *ecs.World) {
 	energyParams := ecs.GetResource[params.EnergyContent](w)
 
 	s.maxHoneyStore = storeParams.MaxHoneyStoreKg * 1000.0 * energyParams.Honey
-	s.rng = rand.New(ecs.GetResource[resource.Rand](w))
+	s.rng = rand.New(ecs.GetResource[globals.RandStreams](w).Get(randForaging))
+	s.sensitivity = rand.New(ecs.GetResource[globals.RandStreams](w).Get(randForagerSensitivity))
+	s.homing = rand.New(ecs.GetResource[globals.RandStreams](w).Get(randHomingFailure))
 	s.time = ecs.GetResource[resource.Tick](w)
 }
 
@@ -207,7 +232,7 @@ func (s *ForagingEtox) newForagers(w *ecs.World) {
 	year := int((s.time.Tick) / 365)
 	for _, e := range s.toAdd {
 		// adding etox components to the newly initialized forager entities
-		s.pppExpoAdder.Add(e, &comp.PPPExpo{OralDose: 0., ContactDose: 0., RdmSurvivalContact: s.rng.Float64(), RdmSurvivalOral: s.rng.Float64()}, &comp.EtoxLoad{PPPLoad: 0., EnergyUsed: 0.})
+		s.pppExpoAdder.Add(e, &comp.PPPExpo{OralDose: 0., ContactDose: 0., RdmSurvivalContact: s.sensitivity.Float64(), RdmSurvivalOral: s.sensitivity.Float64(), LarvalDose: s.inHiveEtox.ForagerLarvalDose}, &comp.EtoxLoad{PPPLoad: 0., EnergyUsed: 0.})
 
 		// check if the squadron is to be considered a winter bee or not
 		if s.nursingParams.WinterBees {
@@ -300,6 +325,29 @@ func (s *ForagingEtox) updatePatches(w *ecs.World) {
 	}
 }
 
+// extraHandling calculates the time a squadron's handling time on a patch is prolonged by due to sublethal effects [s].
+// The handling times of the patches are those of unexposed bees, which are used for patch choice and dancing;
+// exposed squadrons spend the extra time on their own trips, see [ForagingEtox.flightCost] and [ForagingEtox.mortality].
+func (s *ForagingEtox) extraHandling(expo *comp.PPPExpo, handling float64) float64 {
+	if !s.toxic.SublethalForagers {
+		return 0
+	}
+	return handling * s.toxic.HandlingMaxIncrease * s.handlingEffect.Mortality(s.recentDose(expo))
+}
+
+// tripMortality calculates the mortality of a trip to a patch, with the handling time prolonged by the given extra time [s].
+func (s *ForagingEtox) tripMortality(mortality float64, extra float64) float64 {
+	if extra <= 0 {
+		return mortality
+	}
+	return 1 - (1-mortality)*math.Pow(1-s.forageParams.MortalityPerSec, extra)
+}
+
+// recentDose calculates the dose of a squadron sublethal effects depend on.
+func (s *ForagingEtox) recentDose(expo *comp.PPPExpo) float64 {
+	return expo.RecentDose*s.carryOver + expo.OralDose + expo.Contact()
+}
+
 func (s *ForagingEtox) decisions(w *ecs.World, probForage, probCollectPollen float64) {
 	query := s.foragerFilter.Query()
 	for query.Next() {
@@ -659,15 +707,19 @@ func (s *ForagingEtox) flightCost(w *ecs.World) (duration float64, foragers int)
 			foragers++
 		} else if act.Current == activity.BringNectar || act.Current == activity.BringPollen {
 			en := 0.0
+			extra := 0.0
 			if act.PollenForager {
 				trip := s.patchTripMapper.Get(patch.Pollen)
-				duration += trip.DurationPollen + s.handlingTimeParams.PollenUnloading
+				extra = s.extraHandling(ppp, s.patchHandlingMapper.Get(patch.Pollen).Pollen)
+				duration += trip.DurationPollen + extra + s.handlingTimeParams.PollenUnloading
 				en = trip.CostPollen
 			} else {
 				trip := s.patchTripMapper.Get(patch.Nectar)
-				duration += trip.DurationNectar + s.handlingTimeParams.NectarUnloading
+				extra = s.extraHandling(ppp, s.patchHandlingMapper.Get(patch.Nectar).Nectar)
+				duration += trip.DurationNectar + extra + s.handlingTimeParams.NectarUnloading
 				en = trip.CostNectar
 			}
+			en += s.foragerParams.FlightCostPerM * extra * s.foragerParams.FlightVelocity * s.forageParams.EnergyOnFlower // [kJ] = [kJ/m * s * m/s]
 			s.stores.Honey -= en * float64(s.foragerParams.SquadronSize)
 			eload.EnergyUsed += en
 
@@ -701,14 +753,24 @@ func (s *ForagingEtox) mortality(w *ecs.World) {
 		// Acute toxicity during flight
 		lethaldose := false
 		if s.etox.ForagerImmediateMortality { // always false for now; might as well be deactivated
-			if PPPexpo.RdmSurvivalOral < 1-(1/(1+math.Pow(PPPexpo.OralDose/s.toxic.ForagerOralLD50, s.toxic.ForagerOralSlope))) {
+			if PPPexpo.RdmSurvivalOral < s.oralResponse.Mortality(PPPexpo.OralDose) {
 				lethaldose = true
 			}
-			if PPPexpo.RdmSurvivalContact < 1-(1/(1+math.Pow(PPPexpo.ContactDose/s.toxic.ForagerContactLD50, s.toxic.ForagerContactSlope))) {
+			if PPPexpo.RdmSurvivalContact < s.contactResponse.Mortality(PPPexpo.Contact()) {
 				lethaldose = true
 			}
 		}
 
+		// Sublethal homing failure; independent of the lethal dose-response
+		inFlight := act.Current == activity.Searching || act.Current == activity.BringNectar || act.Current == activity.BringPollen
+		if !lethaldose && inFlight && s.toxic.SublethalForagers &&
+			s.homing.Float64() < s.homingResponse.Mortality(s.recentDose(PPPexpo)) {
+			s.toRemove = append(s.toRemove, foragerQuery.Entity())
+			s.pppfate.ForagerDiedInFlight += PPPload.PPPLoad * float64(s.foragerParams.SquadronSize)
+			s.foragingStats.HomingFailures++
+			continue
+		}
+
 		if lethaldose {
 			s.toRemove = append(s.toRemove, foragerQuery.Entity())
 		} else if act.Current == activity.Searching {
@@ -717,13 +779,13 @@ func (s *ForagingEtox) mortality(w *ecs.World) {
 			}
 		} else if act.Current == activity.BringNectar {
 			m := s.patchMortalityMapper.Get(patch.Nectar)
-			if s.rng.Float64() < m.Nectar || lethaldose {
+			if s.rng.Float64() < s.tripMortality(m.Nectar, s.extraHandling(PPPexpo, s.patchHandlingMapper.Get(patch.Nectar).Nectar)) || lethaldose {
 				s.toRemove = append(s.toRemove, foragerQuery.Entity())
 				s.pppfate.ForagerDiedInFlight += PPPload.PPPLoad * float64(s.foragerParams.SquadronSize)
 			}
 		} else if act.Current == activity.BringPollen {
 			m := s.patchMortalityMapper.Get(patch.Pollen)
-			if s.rng.Float64() < m.Pollen || lethaldose {
+			if s.rng.Float64() < s.tripMortality(m.Pollen, s.extraHandling(PPPexpo, s.patchHandlingMapper.Get(patch.Pollen).Pollen)) || lethaldose {
 				s.toRemove = append(s.toRemove, foragerQuery.Entity())
 				s.pppfate.ForagerDiedInFlight += PPPload.PPPLoad * float64(s.foragerParams.SquadronSize)
 			}
@@ -731,6 +793,7 @@ func (s *ForagingEtox) mortality(w *ecs.World) {
 	}
 
 	for _, e := range s.toRemove {
+		s.exposure.record(e)
 		w.RemoveEntity(e)
 	}
 	s.foragingStats.Foragerdied += len(s.toRemove)
@@ -857,7 +920,6 @@ func (s *ForagingEtox) unloading(w *ecs.World) {
 
 			s.stores.Honey += load.Energy * float64(s.foragerParams.SquadronSize)
 
-			s.storesEtox.ETOX_HES_C_D0 = ((s.storesEtox.ETOX_HES_C_D0 * s.storesEtox.ETOX_HES_E_D0) + (PPPload.PPPLoad * (1 - s.toxic.HSuptake) * float64(s.foragerParams.SquadronSize))) / (s.storesEtox.ETOX_HES_E_D0 + (load.Energy * float64(s.foragerParams.SquadronSize))) // may need to readjust
 			// HSuptake actually gets applied a second time in here; it already got applied to PPPload when the foragers took up the load; the lost fraction then got added to the foragers OralDose
 			// here PPPLoad loses another 10% (in total 19% are "lost" to the forager), but these 10% just dissipate. There is no addition to foragers OralDose, 9% of total pesticide taken in via nectarforaging is thus lost in the model without the fix below
 			// BEEHAVE_ecotox ODD´s do not talk about HSuptake anywhere sadly
@@ -865,21 +927,28 @@ func (s *ForagingEtox) unloading(w *ecs.World) {
 				ppp.OralDose += PPPload.PPPLoad * s.toxic.HSuptake
 				s.pppfate.PPPforagersImmediate += PPPload.PPPLoad * s.toxic.HSuptake * float64(s.foragerParams.SquadronSize)
 				s.pppfate.PPPforagersTotal += PPPload.PPPLoad * s.toxic.HSuptake * float64(s.foragerParams.SquadronSize)
+			} else {
+				s.pppfate.PPPdissipatedHSU += PPPload.PPPLoad * s.toxic.HSuptake * float64(s.foragerParams.SquadronSize)
 			}
 			s.pppfate.PPPhoneyStores += PPPload.PPPLoad * (1 - s.toxic.HSuptake) * float64(s.foragerParams.SquadronSize)
 
-			s.storesEtox.ETOX_HES_E_D0 += load.Energy * float64(s.foragerParams.SquadronSize)
-			if s.stores.Honey > s.maxHoneyStore ||
-				s.storesEtox.ETOX_HES_E_Capped+s.storesEtox.ETOX_HES_E_D0+s.storesEtox.ETOX_HES_E_D1+s.storesEtox.ETOX_HES_E_D2+s.storesEtox.ETOX_HES_E_D3+s.storesEtox.ETOX_HES_E_D4 > s.maxHoneyStore {
+			s.pppfate.PPPstoresLost += s.honeyStores.Add(load.Energy*float64(s.foragerParams.SquadronSize), PPPload.PPPLoad*(1-s.toxic.HSuptake)*float64(s.foragerParams.SquadronSize))
+			if s.stores.Honey > s.maxHoneyStore || s.honeyStores.Total() > s.maxHoneyStore {
 				s.stores.Honey = s.maxHoneyStore
-				s.storesEtox.ETOX_HES_E_D0 = s.maxHoneyStore - (s.storesEtox.ETOX_HES_E_Capped + s.storesEtox.ETOX_HES_E_D1 + s.storesEtox.ETOX_HES_E_D2 + s.storesEtox.ETOX_HES_E_D3 + s.storesEtox.ETOX_HES_E_D4)
+				overflow := s.honeyStores.Total() - s.maxHoneyStore
+				s.pppfate.PPPstoresLost += overflow * s.honeyStores.Conc[0]
+				s.honeyStores.Energy[0] -= overflow
 			}
 
 			load.Energy = 0.
 			PPPload.PPPLoad = 0.
 			act.Current = activity.Experienced
 		} else if act.Current == activity.BringPollen {
-			s.storesEtox.PPPInHivePollenConc = ((s.storesEtox.PPPInHivePollenConc * s.stores.Pollen) + (PPPload.PPPLoad * float64(s.foragerParams.SquadronSize))) / (s.stores.Pollen + s.foragerParams.PollenLoad*float64(s.foragerParams.SquadronSize)) // may need to readjust
+			if s.etox.PollenCompartments {
+				s.pollenStores.Add(s.foragerParams.PollenLoad*float64(s.foragerParams.SquadronSize), PPPload.PPPLoad*float64(s.foragerParams.SquadronSize))
+			} else {
+				s.storesEtox.PPPInHivePollenConc = ((s.storesEtox.PPPInHivePollenConc * s.stores.Pollen) + (PPPload.PPPLoad * float64(s.foragerParams.SquadronSize))) / (s.stores.Pollen + s.foragerParams.PollenLoad*float64(s.foragerParams.SquadronSize)) // may need to readjust
+			}
 
 			s.pppfate.PPPpollenStores += PPPload.PPPLoad * float64(s.foragerParams.SquadronSize)
 
@@ -938,65 +1007,11 @@ type patchCandidateEtox struct {
 
 // copy from etox_storages_consumption
 func (s *ForagingEtox) FeedOnHoneyStores(w *ecs.World, cons float64, number float64, honeydilution bool) (OralDose float64) {
-	OralDose = 0.
-	if cons < s.storesEtox.ETOX_HES_E_D0 {
-		OralDose += cons * s.storesEtox.ETOX_HES_C_D0 / number
-		s.storesEtox.ETOX_HES_E_D0 -= cons
-	} else {
-		OralDose += s.storesEtox.ETOX_HES_E_D0 * s.storesEtox.ETOX_HES_C_D0 / number
-		cons -= s.storesEtox.ETOX_HES_E_D0
-		s.storesEtox.ETOX_HES_E_D0 = 0
-
-		if cons < s.storesEtox.ETOX_HES_E_D1 {
-			OralDose += cons * s.storesEtox.ETOX_HES_C_D1 / number
-			s.storesEtox.ETOX_HES_E_D1 -= cons
-		} else {
-			OralDose += s.storesEtox.ETOX_HES_E_D1 * s.storesEtox.ETOX_HES_C_D1 / number
-			cons -= s.storesEtox.ETOX_HES_E_D1
-			s.storesEtox.ETOX_HES_E_D1 = 0
-
-			if cons < s.storesEtox.ETOX_HES_E_D2 {
-				OralDose += cons * s.storesEtox.ETOX_HES_C_D2 / number
-				s.storesEtox.ETOX_HES_E_D2 -= cons
-			} else {
-				OralDose += s.storesEtox.ETOX_HES_E_D1 * s.storesEtox.ETOX_HES_C_D2 / number
-				cons -= s.storesEtox.ETOX_HES_E_D2
-				s.storesEtox.ETOX_HES_E_D2 = 0
-
-				if cons < s.storesEtox.ETOX_HES_E_D3 {
-					OralDose += cons * s.storesEtox.ETOX_HES_C_D3 / number
-					s.storesEtox.ETOX_HES_E_D3 -= cons
-				} else {
-					OralDose += s.storesEtox.ETOX_HES_E_D3 * s.storesEtox.ETOX_HES_C_D3 / number
-					cons -= s.storesEtox.ETOX_HES_E_D3
-					s.storesEtox.ETOX_HES_E_D3 = 0
-
-					if cons < s.storesEtox.ETOX_HES_E_D4 {
-						OralDose += cons * s.storesEtox.ETOX_HES_C_D4 / number
-						s.storesEtox.ETOX_HES_E_D4 -= cons
-					} else {
-						OralDose += s.storesEtox.ETOX_HES_E_D4 * s.storesEtox.ETOX_HES_C_D4 / number
-						cons -= s.storesEtox.ETOX_HES_E_D4
-						s.storesEtox.ETOX_HES_E_D4 = 0
-
-						if cons < s.storesEtox.ETOX_HES_E_Capped {
-							OralDose += cons * s.storesEtox.ETOX_HES_C_Capped / number
-							s.storesEtox.ETOX_HES_E_Capped -= cons
-							if honeydilution {
-								s.storesEtox.ETOX_Waterneedfordilution += cons / s.energyParams.Honey / s.etox.ETOXDensityOfHoney * 0.6
-							}
-						} else {
-							OralDose += s.storesEtox.ETOX_HES_E_Capped * s.storesEtox.ETOX_HES_C_Capped / number
-							cons -= s.storesEtox.ETOX_HES_E_Capped
-							if honeydilution {
-								s.storesEtox.ETOX_Waterneedfordilution += s.storesEtox.ETOX_HES_E_Capped / s.energyParams.Honey / s.etox.ETOXDensityOfHoney * 0.6
-							}
-							s.storesEtox.ETOX_HES_E_Capped = 0
-						}
-					}
-				}
-			}
-		}
+	ppp, capped := s.honeyStores.Feed(cons, s.etox.HoneyOldestFirst)
+	if honeydilution { // capped honey gets diluted with water, which may carry PPP from contaminated water sources
+		water := capped / s.energyParams.Honey / s.etox.ETOXDensityOfHoney * 0.6
+		s.storesEtox.ETOX_Waterneedfordilution += water
+		ppp += s.storesEtox.UseWater(water)
 	}
-	return
+	return ppp / number
 }
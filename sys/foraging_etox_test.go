@@ -0,0 +1,39 @@
+package sys
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForagingEtoxExtraHandling(t *testing.T) {
+	pe := params.DefaultEtox()
+	p := params.Default()
+	effect := &recordResponse{mortality: 0.5}
+	s := ForagingEtox{
+		toxic:          &pe.PPPToxicity,
+		forageParams:   &p.Foraging,
+		handlingEffect: effect,
+	}
+	expo := &comp.PPPExpo{OralDose: 0.01, ContactDose: 0.02}
+
+	pe.PPPToxicity.SublethalForagers = false
+	assert.Equal(t, 0.0, s.extraHandling(expo, 600))
+
+	// the squadron's own dose prolongs its handling time
+	pe.PPPToxicity.SublethalForagers = true
+	pe.PPPToxicity.HandlingMaxIncrease = 1
+	assert.InDelta(t, 300.0, s.extraHandling(expo, 600), 1e-12)
+	assert.InDelta(t, 0.03, effect.exposure, 1e-12)
+	assert.Equal(t, 0.0, s.extraHandling(&comp.PPPExpo{}, 600))
+
+	// the extra time adds to the mortality of the trip as if the trip lasted longer
+	perSec := p.Foraging.MortalityPerSec
+	duration := 1000.0
+	mortality := 1 - math.Pow(1-perSec, duration)
+	assert.Equal(t, mortality, s.tripMortality(mortality, 0))
+	assert.InDelta(t, 1-math.Pow(1-perSec, duration+300), s.tripMortality(mortality, 300), 1e-12)
+}
@@ -26,8 +26,7 @@ func (s *CalcForagingPeriod) Initialize(w *ecs.World) {
 	s.period = globals.ForagingPeriod{}
 	ecs.AddResource(w, &s.period)
 
-	src := ecs.GetResource[resource.Rand](w)
-	s.rng = rand.New(src)
+	s.rng = rand.New(ecs.GetResource[globals.RandStreams](w).Get(randForagingPeriod))
 }
 
 func (s *CalcForagingPeriod) Update(w *ecs.World) {
@@ -14,7 +14,6 @@ import (
 	"github.com/fzeitner/Nursebeecs-master-thesis/params"
 	"github.com/fzeitner/Nursebeecs-master-thesis/util"
 
-	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
 )
 
@@ -22,6 +21,7 @@ import (
 // necessary to simulate beecs_ecotox.
 type InitEtox struct {
 	larvaeEtox globals.LarvaeEtox
+	pupaeEtox  globals.PupaeEtox
 	inHiveEtox globals.InHiveEtox
 	etox       *params.PPPApplication
 
@@ -42,37 +42,64 @@ func (s *InitEtox) Initialize(w *ecs.World) {
 	droneDev := ecs.GetResource[params.DroneDevelopment](w)
 	s.etox = ecs.GetResource[params.PPPApplication](w)
 
+	toxic := ecs.GetResource[params.PPPToxicity](w)
+
 	s.larvaeEtox = globals.LarvaeEtox{
-		WorkerCohortDose: make([]float64, workerDev.LarvaeTime),
-		DroneCohortDose:  make([]float64, droneDev.LarvaeTime),
+		WorkerCohortDose:  make([]float64, workerDev.LarvaeTime),
+		DroneCohortDose:   make([]float64, droneDev.LarvaeTime),
+		WorkerDoseHistory: newDoseHistory(workerDev.LarvaeTime, toxic.ChronicWindowLarvae),
+		DroneDoseHistory:  newDoseHistory(droneDev.LarvaeTime, toxic.ChronicWindowLarvae),
+		WorkerLarvalDose:  make([]float64, workerDev.LarvaeTime),
+		DroneLarvalDose:   make([]float64, droneDev.LarvaeTime),
 	}
 	ecs.AddResource(w, &s.larvaeEtox)
 
+	s.pupaeEtox = globals.PupaeEtox{
+		WorkerLarvalDose: make([]float64, workerDev.PupaeTime),
+		DroneLarvalDose:  make([]float64, droneDev.PupaeTime),
+	}
+	ecs.AddResource(w, &s.pupaeEtox)
+
 	s.inHiveEtox = globals.InHiveEtox{
-		WorkerCohortDose: make([]float64, aff.Max+1),
-		DroneCohortDose:  make([]float64, droneDev.MaxLifespan),
+		WorkerCohortDose:  make([]float64, aff.Max+1),
+		DroneCohortDose:   make([]float64, droneDev.MaxLifespan),
+		WorkerDoseHistory: newDoseHistory(aff.Max+1, toxic.ChronicWindowAdults),
+		DroneDoseHistory:  newDoseHistory(droneDev.MaxLifespan, toxic.ChronicWindowAdults),
+		WorkerContactDose: make([]float64, aff.Max+1),
+		DroneContactDose:  make([]float64, droneDev.MaxLifespan),
+		WorkerLarvalDose:  make([]float64, aff.Max+1),
+		DroneLarvalDose:   make([]float64, droneDev.MaxLifespan),
 	}
 	ecs.AddResource(w, &s.inHiveEtox)
 
 	// initialize ETOX storage globals
 	init := ecs.GetResource[params.InitialStores](w)
 	energyParams := ecs.GetResource[params.EnergyContent](w)
-	storagesEtox := globals.StoragesEtox{
-		ETOX_HES_E_Capped: init.Honey * 1000.0 * energyParams.Honey,
-		ETOX_HES_C_Capped: 0,
-		ETOX_HES_E_D0:     0,
-		ETOX_HES_C_D0:     0,
-		ETOX_HES_E_D1:     0,
-		ETOX_HES_C_D1:     0,
-		ETOX_HES_E_D2:     0,
-		ETOX_HES_C_D2:     0,
-		ETOX_HES_E_D3:     0,
-		ETOX_HES_C_D3:     0,
-		ETOX_HES_E_D4:     0,
-		ETOX_HES_C_D4:     0,
-	}
+	storagesEtox := globals.StoragesEtox{}
 	ecs.AddResource(w, &storagesEtox)
 
+	uncappedDays := s.etox.UncappedDays()
+	honeyStores := globals.HoneyStoresEtox{
+		Energy:       make([]float64, uncappedDays),
+		Conc:         make([]float64, uncappedDays),
+		CappedEnergy: init.Honey * 1000.0 * energyParams.Honey,
+	}
+	ecs.AddResource(w, &honeyStores)
+
+	storeDays := util.MaxInt(s.etox.PollenStoreDays, 1)
+	pollenStores := globals.PollenStoresEtox{
+		Mass: make([]float64, storeDays),
+		Conc: make([]float64, storeDays),
+	}
+	pollenStores.AddOld(ecs.GetResource[globals.Stores](w).Pollen, 0)
+	ecs.AddResource(w, &pollenStores)
+
+	events := globals.AccountingEvents{}
+	ecs.AddResource(w, &events)
+
+	exposure := globals.ForagerExposureRecords{}
+	ecs.AddResource(w, &exposure)
+
 	PPPfate := globals.PPPFate{}
 	ecs.AddResource(w, &PPPfate)
 
@@ -83,7 +110,7 @@ func (s *InitEtox) Initialize(w *ecs.World) {
 	ecs.AddResource(w, &forstatsEtox)
 
 	// add the PPPExpo component to all foragers
-	s.source = rand.New(ecs.GetResource[resource.Rand](w))
+	s.source = ecs.GetResource[globals.RandStreams](w).Get(randForagerSensitivity)
 	s.foragerPPPmapper = s.foragerPPPmapper.New(w)
 	s.etoxAdder = s.etoxAdder.New(w)
 	s.foragerFilter = s.foragerFilter.New(w)
@@ -115,9 +142,7 @@ func (s *InitEtox) Initialize(w *ecs.World) {
 		s.patchPPPmapper.Add(entity, &comp.PatchPropertiesEtox{PPPconcentrationNectar: 0., PPPconcentrationPollen: 0., PPPcontactDose: 0.}, &comp.ResourceEtox{PPPconcentrationNectar: 0., PPPconcentrationPollen: 0., PPPcontactDose: 0.})
 	}
 
-	// WATERFORAGING IMPLEMENTATION HERE: EToX_WaterforcoolingREP <- Reporter for Water need per day
-	// water foraging from here on, does work with embedded files, but water foraging as a whole is not tested
-	// and should not be turned on as of yet, might be deleted/never finished; time will tell
+	// daily water need for cooling, read from files or provided directly; see CalcWaterForagingPeriod
 	waterParams := ecs.GetResource[params.WaterForaging](w)
 	s.waterPeriodData = globals.WaterForagingPeriodData{}
 	ecs.AddResource(w, &s.waterPeriodData)
@@ -160,3 +185,13 @@ func (s *InitEtox) Initialize(w *ecs.World) {
 func (s *InitEtox) Update(w *ecs.World) {}
 
 func (s *InitEtox) Finalize(w *ecs.World) {}
+
+// newDoseHistory creates the per-cohort daily dose windows used for chronic toxicity.
+func newDoseHistory(cohorts int, window int) [][]float64 {
+	window = util.MaxInt(window, 1)
+	history := make([][]float64, cohorts)
+	for i := range history {
+		history[i] = make([]float64, window)
+	}
+	return history
+}
@@ -0,0 +1,57 @@
+package sys
+
+import (
+	"math/rand/v2"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// InitMites initializes and adds the resources [globals.Mites] and [globals.InfectedCohorts]
+// of the Varroa mite module, and creates the initial phoretic mites if [params.Varroa] is enabled.
+//
+// The resources are added in any case, so that observers work with the module disabled.
+type InitMites struct {
+	mites    globals.Mites
+	infected globals.InfectedCohorts
+}
+
+func (s *InitMites) Initialize(w *ecs.World) {
+	varroa := ecs.GetResource[params.Varroa](w)
+	aff := ecs.GetResource[params.AgeFirstForaging](w)
+	workerDev := ecs.GetResource[params.WorkerDevelopment](w)
+	droneDev := ecs.GetResource[params.DroneDevelopment](w)
+
+	s.mites = globals.Mites{
+		WorkerCellsHealthy:  make([]int, workerDev.PupaeTime),
+		WorkerCellsInfected: make([]int, workerDev.PupaeTime),
+		DroneCellsHealthy:   make([]int, droneDev.PupaeTime),
+		DroneCellsInfected:  make([]int, droneDev.PupaeTime),
+	}
+	if varroa.Enabled {
+		rng := rand.New(ecs.GetResource[globals.RandStreams](w).Get(randMites))
+		for range varroa.InitialMites {
+			if rng.Float64() < varroa.InitialInfected {
+				s.mites.PhoreticInfected++
+			} else {
+				s.mites.PhoreticHealthy++
+			}
+		}
+	}
+	ecs.AddResource(w, &s.mites)
+
+	s.infected = globals.InfectedCohorts{
+		WorkerPupae:    make([]int, workerDev.PupaeTime),
+		DronePupae:     make([]int, droneDev.PupaeTime),
+		WorkersAsPupa:  make([]int, aff.Max+1),
+		WorkersAsAdult: make([]int, aff.Max+1),
+		DronesAsPupa:   make([]int, droneDev.MaxLifespan),
+		DronesAsAdult:  make([]int, droneDev.MaxLifespan),
+	}
+	ecs.AddResource(w, &s.infected)
+}
+
+func (s *InitMites) Update(w *ecs.World) {}
+
+func (s *InitMites) Finalize(w *ecs.World) {}
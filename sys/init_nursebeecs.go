@@ -1,14 +1,11 @@
 package sys
 
 import (
-	"math/rand/v2"
-
 	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
 	"github.com/fzeitner/Nursebeecs-master-thesis/enum/activity"
 	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
 	"github.com/fzeitner/Nursebeecs-master-thesis/params"
 
-	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
 )
 
@@ -22,7 +19,6 @@ type InitNursebeecs struct {
 
 	foragersFilter *ecs.Filter0
 	actAdder       *ecs.Map1[comp.ActivityEtox]
-	source         rand.Source
 }
 
 func (s *InitNursebeecs) Initialize(w *ecs.World) {
@@ -88,6 +84,10 @@ func (s *InitNursebeecs) Initialize(w *ecs.World) {
 	s.nGlobals = ecs.GetResource[globals.NursingGlobals](w)
 	s.nGlobals.SuffNurses = true                           // assume that first stimestep there simply are enough nurses, maybe change at some point
 	s.nGlobals.NurseAgeMax = s.nurseParams.NurseAgeCeiling // initialize with baseline nurse max age from params
+	s.nGlobals.Capability = append([]float64{}, s.nurseCons.Nursingcapabiliies...)
+
+	hpg := newHPG(w)
+	ecs.AddResource(w, &hpg)
 
 	statsEtox := globals.PopulationStatsEtox{}
 	ecs.AddResource(w, &statsEtox)
@@ -95,7 +95,6 @@ func (s *InitNursebeecs) Initialize(w *ecs.World) {
 	forstatsEtox := globals.ForagingStatsEtox{}
 	ecs.AddResource(w, &forstatsEtox)
 
-	s.source = rand.New(ecs.GetResource[resource.Rand](w))
 	s.actAdder = s.actAdder.New(w)
 	s.foragersFilter = ecs.NewFilter0(w).With(ecs.C[comp.Age]())
 
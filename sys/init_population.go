@@ -3,7 +3,6 @@ package sys
 import (
 	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
 	"github.com/fzeitner/Nursebeecs-master-thesis/params"
-	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
 )
 
@@ -15,7 +14,7 @@ func (s *InitPopulation) Initialize(w *ecs.World) {
 	init := ecs.GetResource[params.InitialPopulation](w)
 	params := ecs.GetResource[params.Foragers](w)
 	factory := ecs.GetResource[globals.ForagerFactory](w)
-	rand := ecs.GetResource[resource.Rand](w)
+	rand := ecs.GetResource[globals.RandStreams](w).Get(randInitPopulation)
 
 	squadrons := init.Count / params.SquadronSize
 	factory.CreateInitialSquadrons(squadrons, -init.MaxAge, -init.MinAge, init.MinMilage, init.MaxMilage, rand)
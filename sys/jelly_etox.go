@@ -0,0 +1,29 @@
+package sys
+
+// jellyConcentration returns the PPP concentration [µg/kg] of larval food processed by nurses
+// from the given amounts of honey and pollen [mg].
+// Concentrations of honey and pollen are given in [µg/mg] and get reduced by the nurse bee filter factors.
+// Returns 0 if no food is processed.
+func jellyConcentration(honey, pollen, honeyConc, pollenConc, nectarFilter, pollenFilter float64) float64 {
+	if honey+pollen <= 0 {
+		return 0
+	}
+	ppp := honey*honeyConc*nectarFilter + pollen*pollenConc*pollenFilter
+	return ppp / (honey + pollen) * 1e6
+}
+
+// nurseProcessedFood returns the amounts of honey and pollen [mg] that get processed by nurses for the given larval cohorts.
+// From age 3 onwards, larvae take in a fraction of their food directly from the stores.
+func nurseProcessedFood(coh []int, honeyLarva, pollenLarva []float64, honeyDirect, pollenDirect float64) (honey, pollen float64) {
+	for i, num := range coh {
+		h := honeyLarva[i] * float64(num)
+		p := pollenLarva[i] * float64(num)
+		if i > 2 {
+			h *= 1 - honeyDirect
+			p *= 1 - pollenDirect
+		}
+		honey += h
+		pollen += p
+	}
+	return
+}
@@ -0,0 +1,276 @@
+package sys
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"path"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/management"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+)
+
+const sucroseMolarMass = 342.3 // [g/mol]
+
+// Management applies the beekeeper management events of [params.Management] on their scheduled days,
+// and records them in [globals.ManagementRecords]. See [management.Action] for the available actions.
+//
+// Feed is free of PPP. In the _ecotox models, it enters the compartments of the stores like food brought in by foragers,
+// and added brood dilutes the exposure markers of the receiving cohorts.
+// PPP in honey and pollen removed by the beekeeper leaves the hive.
+// A colony split keeps the queen in the parent colony; a fraction of each brood and in-hive cohort,
+// of the forager squadrons, of the phoretic mites and of the stores is removed.
+//
+// Must run after [AgeCohorts], so that brood exchanges and splits act on today's cohorts, and before [EggLaying],
+// which sets today's egg cohort that is therefore neither removed nor diluted by these actions.
+// Running after [QueenDynamics] lets emergency queen rearing see the brood before the beekeeper's intervention.
+type Management struct {
+	storeParams   *params.Stores
+	energyParams  *params.EnergyContent
+	foragerParams *params.Foragers
+	time          *resource.Tick
+	rng           *rand.Rand
+
+	eggs   *globals.Eggs
+	larvae *globals.Larvae
+	pupae  *globals.Pupae
+	inHive *globals.InHive
+	stores *globals.Stores
+	mites  *globals.Mites
+
+	etox         *params.PPPApplication
+	storesEtox   *globals.StoragesEtox
+	honeyStores  *globals.HoneyStoresEtox
+	pollenStores *globals.PollenStoresEtox
+	larvaeEtox   *globals.LarvaeEtox
+	pupaeEtox    *globals.PupaeEtox
+	pppFate      *globals.PPPFate
+
+	events  []params.ManagementEvent
+	records globals.ManagementRecords
+
+	squadrons squadronRemoval
+}
+
+func (s *Management) Initialize(w *ecs.World) {
+	s.storeParams = ecs.GetResource[params.Stores](w)
+	s.energyParams = ecs.GetResource[params.EnergyContent](w)
+	s.foragerParams = ecs.GetResource[params.Foragers](w)
+	s.time = ecs.GetResource[resource.Tick](w)
+	s.rng = rand.New(ecs.GetResource[globals.RandStreams](w).Get(randManagement))
+
+	s.eggs = ecs.GetResource[globals.Eggs](w)
+	s.larvae = ecs.GetResource[globals.Larvae](w)
+	s.pupae = ecs.GetResource[globals.Pupae](w)
+	s.inHive = ecs.GetResource[globals.InHive](w)
+	s.stores = ecs.GetResource[globals.Stores](w)
+	s.mites = ecs.GetResource[globals.Mites](w)
+
+	// the compartments of the stores and the exposure of the brood only exist in the _ecotox models
+	if etox := ecs.NewResource[params.PPPApplication](w); etox.Has() {
+		s.etox = etox.Get()
+		s.storesEtox = ecs.GetResource[globals.StoragesEtox](w)
+		s.honeyStores = ecs.GetResource[globals.HoneyStoresEtox](w)
+		s.pollenStores = ecs.GetResource[globals.PollenStoresEtox](w)
+		s.larvaeEtox = ecs.GetResource[globals.LarvaeEtox](w)
+		s.pupaeEtox = ecs.GetResource[globals.PupaeEtox](w)
+		s.pppFate = ecs.GetResource[globals.PPPFate](w)
+	}
+
+	mgmt := ecs.GetResource[params.Management](w)
+	s.events = append([]params.ManagementEvent{}, mgmt.Events...)
+	if mgmt.File != "" {
+		wd := ecs.GetResource[params.WorkingDirectory](w).Path
+		events, err := params.ManagementEventsFromFile(path.Join(wd, mgmt.File))
+		if err != nil {
+			log.Fatal(fmt.Errorf("error reading management events file '%s': %s", mgmt.File, err.Error()))
+		}
+		s.events = append(s.events, events...)
+	}
+
+	s.records = globals.ManagementRecords{}
+	ecs.AddResource(w, &s.records)
+
+	s.squadrons = newSquadronRemoval(w)
+}
+
+func (s *Management) Update(w *ecs.World) {
+	for i := range s.events {
+		e := &s.events[i]
+		if !e.Due(s.time.Tick) {
+			continue
+		}
+		rec := globals.ManagementRecord{Tick: s.time.Tick, Name: e.Name, Action: e.Action}
+		switch e.Action {
+		case management.HoneyHarvest:
+			rec.Honey = -s.harvestHoney(e.Amount)
+		case management.SugarFeeding:
+			rec.Honey = s.feedSugar(e.Amount)
+		case management.PollenSubstitute:
+			rec.Pollen = s.feedPollen(e.Amount)
+		case management.BroodExchange:
+			rec.Brood = s.exchangeBrood(int(math.Round(e.Amount)))
+		case management.Split:
+			rec.Honey, rec.Pollen, rec.Brood, rec.Adults = s.split(w, util.Clamp(e.Amount, 0, 1))
+		default:
+			panic(fmt.Sprintf("unknown management action %d of event '%s'", e.Action, e.Name))
+		}
+		s.records.Records = append(s.records.Records, rec)
+	}
+}
+
+func (s *Management) Finalize(w *ecs.World) {}
+
+// harvestHoney removes all honey above the given amount [kg] and returns the energy removed [kJ].
+func (s *Management) harvestHoney(keep float64) float64 {
+	honey := math.Max(s.stores.Honey-keep*1000.0*s.energyParams.Honey, 0) // [kg] -> [g] * [kJ/g]
+	s.stores.Honey -= honey
+	if s.honeyStores != nil {
+		s.pppFate.PPPmanagement += s.honeyStores.Harvest(honey)
+	}
+	return honey
+}
+
+// feedSugar adds the energy of the given amount of sucrose [kg] to the honey stores, up to their maximum,
+// and returns the energy added [kJ].
+func (s *Management) feedSugar(sugar float64) float64 {
+	energy := sugar * 1000.0 / sucroseMolarMass * 1e6 * s.energyParams.Sucrose // [kg] -> [g] -> [µmol] * [kJ/µmol]
+	energy = math.Min(energy, math.Max(s.storeParams.MaxHoneyStoreKg*1000.0*s.energyParams.Honey-s.stores.Honey, 0))
+	s.stores.Honey += energy
+	if s.honeyStores != nil {
+		s.honeyStores.Add(energy, 0)
+	}
+	return energy
+}
+
+// feedPollen adds the given amount of pollen substitute [g] to the pollen stores and returns it.
+func (s *Management) feedPollen(mass float64) float64 {
+	if mass <= 0 {
+		return 0
+	}
+	if s.etox != nil {
+		if s.etox.PollenCompartments {
+			s.pollenStores.Add(mass, 0)
+		} else {
+			s.storesEtox.PPPInHivePollenConc = s.storesEtox.PPPInHivePollenConc * s.stores.Pollen / (s.stores.Pollen + mass)
+		}
+	}
+	s.stores.Pollen += mass
+	return mass
+}
+
+// exchangeBrood adds the given number of worker brood cells, or removes them if negative, and returns the actual change.
+// Brood is added and removed in proportion to the current worker brood cohorts.
+// Brood added to a colony without brood is capped brood, spread evenly over the pupa cohorts.
+func (s *Management) exchangeBrood(cells int) int {
+	total := 0
+	for _, coh := range [][]int{s.eggs.Workers, s.larvae.Workers, s.pupae.Workers} {
+		for _, n := range coh {
+			total += n
+		}
+	}
+	if cells < 0 {
+		if total == 0 {
+			return 0
+		}
+		fraction := math.Min(float64(-cells)/float64(total), 1)
+		return -(removeFraction(s.eggs.Workers, fraction) + removeFraction(s.larvae.Workers, fraction) + removeFraction(s.pupae.Workers, fraction))
+	}
+
+	added := 0
+	if total == 0 {
+		for j := range s.pupae.Workers {
+			n := cells*(j+1)/len(s.pupae.Workers) - cells*j/len(s.pupae.Workers)
+			s.addPupae(j, n)
+			added += n
+		}
+		return added
+	}
+	for j, n := range s.eggs.Workers {
+		add := int(math.Round(float64(cells) * float64(n) / float64(total)))
+		s.eggs.Workers[j] += add
+		added += add
+	}
+	for j, n := range s.larvae.Workers {
+		add := int(math.Round(float64(cells) * float64(n) / float64(total)))
+		s.addLarvae(j, add)
+		added += add
+	}
+	for j, n := range s.pupae.Workers {
+		add := int(math.Round(float64(cells) * float64(n) / float64(total)))
+		s.addPupae(j, add)
+		added += add
+	}
+	return added
+}
+
+// addLarvae adds unexposed larvae to a worker larva cohort.
+func (s *Management) addLarvae(j int, n int) {
+	if n <= 0 {
+		return
+	}
+	if s.larvaeEtox != nil {
+		dilution := float64(s.larvae.Workers[j]) / float64(s.larvae.Workers[j]+n)
+		s.larvaeEtox.WorkerCohortDose[j] *= dilution
+		s.larvaeEtox.WorkerLarvalDose[j] *= dilution
+		for i := range s.larvaeEtox.WorkerDoseHistory[j] {
+			s.larvaeEtox.WorkerDoseHistory[j][i] *= dilution
+		}
+	}
+	s.larvae.Workers[j] += n
+}
+
+// addPupae adds unexposed pupae to a worker pupa cohort.
+func (s *Management) addPupae(j int, n int) {
+	if n <= 0 {
+		return
+	}
+	if s.pupaeEtox != nil {
+		s.pupaeEtox.WorkerLarvalDose[j] *= float64(s.pupae.Workers[j]) / float64(s.pupae.Workers[j]+n)
+	}
+	s.pupae.Workers[j] += n
+}
+
+// split removes the given fraction of the colony to form a new colony, and returns the changes of the stores and of the population.
+func (s *Management) split(w *ecs.World, fraction float64) (honey float64, pollen float64, brood int, adults int) {
+	for _, coh := range [][]int{s.eggs.Workers, s.eggs.Drones, s.larvae.Workers, s.larvae.Drones, s.pupae.Workers, s.pupae.Drones} {
+		brood -= removeFraction(coh, fraction)
+	}
+	adults -= removeFraction(s.inHive.Workers, fraction)
+	adults -= removeFraction(s.inHive.Drones, fraction)
+
+	adults -= s.squadrons.removeFraction(w, s.rng, fraction) * s.foragerParams.SquadronSize
+
+	s.mites.PhoreticHealthy -= int(math.Round(float64(s.mites.PhoreticHealthy) * fraction))
+	s.mites.PhoreticInfected -= int(math.Round(float64(s.mites.PhoreticInfected) * fraction))
+
+	honey = -math.Max(s.stores.Honey, 0) * fraction
+	pollen = -s.stores.Pollen * fraction
+	if s.etox != nil {
+		s.pppFate.PPPmanagement += s.honeyStores.Split(fraction)
+		if s.etox.PollenCompartments {
+			s.pppFate.PPPmanagement += s.pollenStores.Split(fraction)
+		} else {
+			s.pppFate.PPPmanagement += s.storesEtox.PPPInHivePollenConc * -pollen
+		}
+	}
+	s.stores.Honey += honey
+	s.stores.Pollen += pollen
+	return
+}
+
+// removeFraction removes the given fraction of each cohort and returns the number removed.
+func removeFraction(coh []int, fraction float64) int {
+	removed := 0
+	for j, n := range coh {
+		r := int(math.Round(float64(n) * fraction))
+		coh[j] -= r
+		removed += r
+	}
+	return removed
+}
@@ -0,0 +1,422 @@
+package sys
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/virus"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// MiteProc simulates the Varroa mite population and the transmission of a virus between mites and bees,
+// analogous to the miteproc of BEEHAVE. It does nothing unless [params.Varroa] is enabled.
+//
+// Each day, it
+//   - moves infected in-hive bees along with their cohorts, and passes the infection of bees becoming foragers on to the new squadrons
+//   - moves mites and infected pupae along with the capped brood, and releases the mites of emerging brood together with their offspring
+//   - transmits the virus between phoretic mites and in-hive bees, and applies the mortality of infected bees
+//   - applies background mortality and beekeeper treatments to the mites
+//   - lets phoretic mites invade the worker and drone cells capped today
+//
+// Losses of brood and in-hive bees caused by other sub-models since the last update are applied proportionally
+// to the infected bees and to the mites in the cells, which die with their brood.
+// Must run once per day, after [AgeCohorts], [TransitionForagers] and [NewCohorts] and before foragers are created.
+type MiteProc struct {
+	params        *params.Varroa
+	foragerParams *params.Foragers
+	time          *resource.Tick
+	rng           *rand.Rand
+	src           *util.RandWrapper
+
+	pupae      *globals.Pupae
+	inHive     *globals.InHive
+	newCohorts *globals.NewCohorts
+	aff        *globals.AgeFirstForaging
+	stats      *globals.PopulationStats
+	mites      *globals.Mites
+	infected   *globals.InfectedCohorts
+
+	workerPupae []int // sizes of the cohorts after the last update, to follow ageing and losses
+	dronePupae  []int
+	workers     []int
+	drones      []int
+
+	pendingAsPupa  int // squadrons infected as pupae among the squadrons created after the last update
+	pendingAsAdult int // squadrons infected as adults among the squadrons created after the last update
+
+	newFilter     *ecs.Filter1[comp.Age]
+	foragerFilter *ecs.Filter1[comp.Virus]
+	virusAdder    *ecs.Map1[comp.Virus]
+	toAdd         []ecs.Entity
+	toRemove      []ecs.Entity
+	exposure      foragerExposureRecorder
+	healthy       []int
+}
+
+func (s *MiteProc) Initialize(w *ecs.World) {
+	s.params = ecs.GetResource[params.Varroa](w)
+	s.foragerParams = ecs.GetResource[params.Foragers](w)
+	s.time = ecs.GetResource[resource.Tick](w)
+	source := ecs.GetResource[globals.RandStreams](w).Get(randMites)
+	s.rng = rand.New(source)
+	s.src = &util.RandWrapper{Src: source}
+
+	s.pupae = ecs.GetResource[globals.Pupae](w)
+	s.inHive = ecs.GetResource[globals.InHive](w)
+	s.newCohorts = ecs.GetResource[globals.NewCohorts](w)
+	s.aff = ecs.GetResource[globals.AgeFirstForaging](w)
+	s.stats = ecs.GetResource[globals.PopulationStats](w)
+	s.mites = ecs.GetResource[globals.Mites](w)
+	s.infected = ecs.GetResource[globals.InfectedCohorts](w)
+
+	s.workerPupae = make([]int, len(s.pupae.Workers))
+	s.dronePupae = make([]int, len(s.pupae.Drones))
+	s.workers = make([]int, len(s.inHive.Workers))
+	s.drones = make([]int, len(s.inHive.Drones))
+
+	s.newFilter = s.newFilter.New(w)
+	s.foragerFilter = s.foragerFilter.New(w)
+	s.virusAdder = s.virusAdder.New(w)
+	s.exposure = newForagerExposureRecorder(w)
+}
+
+func (s *MiteProc) Update(w *ecs.World) {
+	if !s.params.Enabled {
+		return
+	}
+	s.mites.Invaded, s.mites.Emerged, s.mites.Died, s.mites.Treated = 0, 0, 0, 0
+
+	s.infectNewSquadrons()
+
+	// in-hive workers from the transition index on were pooled by the transition to foragers
+	transition := util.Clamp(s.aff.Aff-2, 1, len(s.inHive.Workers))
+	asPupa, asAdult := s.ageAdults(s.inHive.Workers, s.workers, transition, s.newCohorts.Foragers*s.foragerParams.SquadronSize,
+		s.infected.WorkersAsPupa, s.infected.WorkersAsAdult)
+	s.pendingAsPupa = util.MinInt(s.stochasticRound(float64(asPupa)/float64(s.foragerParams.SquadronSize)), s.newCohorts.Foragers)
+	s.pendingAsAdult = util.MinInt(s.stochasticRound(float64(asAdult)/float64(s.foragerParams.SquadronSize)), s.newCohorts.Foragers-s.pendingAsPupa)
+	s.ageAdults(s.inHive.Drones, s.drones, len(s.inHive.Drones), 0, s.infected.DronesAsPupa, s.infected.DronesAsAdult)
+
+	s.ageBrood(s.pupae.Workers, s.workerPupae, s.mites.WorkerCellsHealthy, s.mites.WorkerCellsInfected, s.infected.WorkerPupae,
+		s.inHive.Workers, s.infected.WorkersAsPupa, s.params.OffspringWorkers)
+	s.ageBrood(s.pupae.Drones, s.dronePupae, s.mites.DroneCellsHealthy, s.mites.DroneCellsInfected, s.infected.DronePupae,
+		s.inHive.Drones, s.infected.DronesAsPupa, s.params.OffspringDrones)
+
+	s.transmit()
+	s.virusMortality(s.inHive.Workers, s.infected.WorkersAsPupa, s.infected.WorkersAsAdult)
+	s.virusMortality(s.inHive.Drones, s.infected.DronesAsPupa, s.infected.DronesAsAdult)
+	s.virusMortalityForagers(w)
+
+	s.mortality()
+	s.invade()
+
+	copy(s.workerPupae, s.pupae.Workers)
+	copy(s.dronePupae, s.pupae.Drones)
+	copy(s.workers, s.inHive.Workers)
+	copy(s.drones, s.inHive.Drones)
+}
+
+func (s *MiteProc) Finalize(w *ecs.World) {}
+
+// infectNewSquadrons adds the virus component to squadrons created since the last update,
+// with the infection states of the bees that became foragers.
+func (s *MiteProc) infectNewSquadrons() {
+	query := s.newFilter.Without(ecs.C[comp.Virus]()).Query()
+	for query.Next() {
+		s.toAdd = append(s.toAdd, query.Entity())
+	}
+	for _, e := range s.toAdd {
+		state := virus.Healthy
+		if s.pendingAsPupa > 0 {
+			state = virus.InfectedAsPupa
+			s.pendingAsPupa--
+		} else if s.pendingAsAdult > 0 {
+			state = virus.InfectedAsAdult
+			s.pendingAsAdult--
+		}
+		s.virusAdder.Add(e, &comp.Virus{State: state})
+	}
+	s.toAdd = s.toAdd[:0]
+	s.pendingAsPupa, s.pendingAsAdult = 0, 0
+}
+
+// ageAdults moves the infected bees of two infection routes along with their in-hive cohorts,
+// which have aged by one day since the last update. Cohorts from index transition on are treated as a pool,
+// of which the given number of bees left the cohorts. Returns the infected bees among those that left.
+func (s *MiteProc) ageAdults(coh []int, sizes []int, transition int, left int, first []int, second []int) (int, int) {
+	shiftCohorts(first, 0)
+	shiftCohorts(second, 0)
+
+	for j := 1; j < transition; j++ {
+		first[j] = util.MinInt(s.thin(first[j], sizes[j-1], coh[j]), coh[j])
+		second[j] = util.MinInt(s.thin(second[j], sizes[j-1], coh[j]), coh[j]-first[j])
+	}
+	if transition >= len(coh) {
+		return 0, 0
+	}
+
+	before, after := 0, left
+	poolFirst, poolSecond := 0, 0
+	for j := transition; j < len(coh); j++ {
+		before += sizes[j-1]
+		after += coh[j]
+		poolFirst += first[j]
+		poolSecond += second[j]
+	}
+	poolFirst = util.MinInt(s.thin(poolFirst, before, after), after)
+	poolSecond = util.MinInt(s.thin(poolSecond, before, after), after-poolFirst)
+
+	for j := transition; j < len(coh); j++ {
+		first[j] = s.binomial(poolFirst, float64(coh[j])/float64(after))
+		second[j] = util.MinInt(s.binomial(poolSecond, float64(coh[j])/float64(after)), coh[j]-first[j])
+		poolFirst -= first[j]
+		poolSecond -= second[j]
+		after -= coh[j]
+	}
+	return poolFirst, poolSecond
+}
+
+// ageBrood moves mites and infected pupae along with the capped brood, which has aged by one day since the last update,
+// and processes the brood that emerged into the first adult cohort.
+func (s *MiteProc) ageBrood(coh []int, sizes []int, healthy []int, infected []int, pupae []int, adults []int, asPupa []int, offspring float64) {
+	last := len(coh) - 1
+	emergedHealthy, emergedInfected, emergedPupae, emergedBefore := healthy[last], infected[last], pupae[last], sizes[last]
+
+	shiftCohorts(healthy, 0)
+	shiftCohorts(infected, 0)
+	shiftCohorts(pupae, 0)
+	for j := 1; j < len(coh); j++ {
+		healthy[j] = s.thin(healthy[j], sizes[j-1], coh[j])
+		infected[j] = s.thin(infected[j], sizes[j-1], coh[j])
+		pupae[j] = util.MinInt(s.thin(pupae[j], sizes[j-1], coh[j]), coh[j])
+	}
+
+	cells := adults[0]
+	mitesHealthy := s.thin(emergedHealthy, emergedBefore, cells)
+	mitesInfected := s.thin(emergedInfected, emergedBefore, cells)
+	infectedPupae := util.MinInt(s.thin(emergedPupae, emergedBefore, cells), cells)
+
+	// infected pupae killed by the virus do not emerge
+	dead := s.binomial(infectedPupae, s.params.BroodMortality)
+	adults[0] -= dead
+	asPupa[0] = util.MinInt(infectedPupae-dead, adults[0])
+
+	mothers := mitesHealthy + mitesInfected
+	infested := infestedCells(mothers, cells)
+	newOffspring, infectedOffspring, infectedMothers := 0, 0, 0
+	if infested > 0 {
+		// mites in cells of dead pupae do not reproduce
+		inDead := s.binomial(mothers, math.Min(float64(dead)/float64(infested), 1))
+		newOffspring = s.poisson(offspring * float64(mothers-inDead))
+
+		infectedMothers = s.binomial(mitesHealthy, math.Min(float64(infectedPupae)/float64(infested), 1)*s.params.PupaToMite)
+		if infested > dead {
+			infectedOffspring = s.binomial(newOffspring, math.Min(float64(infectedPupae-dead)/float64(infested-dead), 1)*s.params.PupaToMite)
+		}
+	}
+
+	s.mites.PhoreticHealthy += mitesHealthy - infectedMothers + newOffspring - infectedOffspring
+	s.mites.PhoreticInfected += mitesInfected + infectedMothers + infectedOffspring
+	s.mites.Emerged += mothers + newOffspring
+}
+
+// transmit transmits the virus between phoretic mites and in-hive bees.
+func (s *MiteProc) transmit() {
+	s.healthy = s.healthy[:0]
+	hosts := 0
+	for j, n := range s.inHive.Workers {
+		s.healthy = append(s.healthy, n-s.infected.WorkersAsPupa[j]-s.infected.WorkersAsAdult[j])
+		hosts += n
+	}
+	for j, n := range s.inHive.Drones {
+		s.healthy = append(s.healthy, n-s.infected.DronesAsPupa[j]-s.infected.DronesAsAdult[j])
+		hosts += n
+	}
+	if hosts == 0 {
+		return
+	}
+	infectedHosts := s.infected.InHive()
+
+	acquired := s.binomial(s.mites.PhoreticHealthy, s.params.AdultToMite*float64(infectedHosts)/float64(hosts))
+	newInfections := s.binomial(s.mites.PhoreticInfected, s.params.MiteToAdult)
+	s.mites.PhoreticHealthy -= acquired
+	s.mites.PhoreticInfected += acquired
+
+	remaining := hosts - infectedHosts
+	newInfections = util.MinInt(newInfections, remaining)
+	workers := len(s.inHive.Workers)
+	for j, n := range s.healthy {
+		if newInfections <= 0 || remaining <= 0 {
+			break
+		}
+		infections := util.MinInt(s.binomial(newInfections, float64(n)/float64(remaining)), n)
+		if j < workers {
+			s.infected.WorkersAsAdult[j] += infections
+		} else {
+			s.infected.DronesAsAdult[j-workers] += infections
+		}
+		newInfections -= infections
+		remaining -= n
+	}
+}
+
+// virusMortality applies the additional mortality of infected in-hive bees.
+func (s *MiteProc) virusMortality(coh []int, asPupa []int, asAdult []int) {
+	for j := range coh {
+		deadPupa := s.binomial(asPupa[j], s.params.MortalityAsPupa)
+		deadAdult := s.binomial(asAdult[j], s.params.MortalityAsAdult)
+		asPupa[j] -= deadPupa
+		asAdult[j] -= deadAdult
+		coh[j] = util.MaxInt(0, coh[j]-deadPupa-deadAdult)
+	}
+}
+
+// virusMortalityForagers applies the additional mortality of infected forager squadrons.
+func (s *MiteProc) virusMortalityForagers(w *ecs.World) {
+	query := s.foragerFilter.Query()
+	for query.Next() {
+		v := query.Get()
+		switch v.State {
+		case virus.InfectedAsPupa:
+			if s.rng.Float64() < s.params.MortalityAsPupa {
+				s.toRemove = append(s.toRemove, query.Entity())
+			}
+		case virus.InfectedAsAdult:
+			if s.rng.Float64() < s.params.MortalityAsAdult {
+				s.toRemove = append(s.toRemove, query.Entity())
+			}
+		}
+	}
+	for _, e := range s.toRemove {
+		s.exposure.record(e)
+		w.RemoveEntity(e)
+	}
+	s.toRemove = s.toRemove[:0]
+}
+
+// mortality applies background mortality to the phoretic mites, and the active treatments to phoretic mites and mites in cells.
+func (s *MiteProc) mortality() {
+	rate := s.params.MortalityBroodless
+	if s.stats.TotalBrood > 0 {
+		rate = s.params.MortalityBrood
+	}
+	deadHealthy := s.binomial(s.mites.PhoreticHealthy, rate)
+	deadInfected := s.binomial(s.mites.PhoreticInfected, rate)
+	s.mites.PhoreticHealthy -= deadHealthy
+	s.mites.PhoreticInfected -= deadInfected
+	s.mites.Died = deadHealthy + deadInfected
+
+	dayOfYear := int(s.time.Tick % 365)
+	for i := range s.params.Treatments {
+		t := &s.params.Treatments[i]
+		if !t.Active(dayOfYear) {
+			continue
+		}
+		s.mites.PhoreticHealthy -= s.treat(s.mites.PhoreticHealthy, t.Efficacy)
+		s.mites.PhoreticInfected -= s.treat(s.mites.PhoreticInfected, t.Efficacy)
+		for _, cells := range [][]int{s.mites.WorkerCellsHealthy, s.mites.WorkerCellsInfected, s.mites.DroneCellsHealthy, s.mites.DroneCellsInfected} {
+			for j := range cells {
+				cells[j] -= s.treat(cells[j], t.CellEfficacy)
+			}
+		}
+	}
+}
+
+// treat returns the number of mites killed by a treatment, and adds them to the treated mites.
+func (s *MiteProc) treat(mites int, efficacy float64) int {
+	killed := s.binomial(mites, efficacy)
+	s.mites.Treated += killed
+	return killed
+}
+
+// invade lets phoretic mites invade the worker and drone cells capped today, and infects the pupae.
+func (s *MiteProc) invade() {
+	workerCells, droneCells := s.pupae.Workers[0], s.pupae.Drones[0]
+	workerRate := s.params.InvasionWorkers * float64(workerCells)
+	rate := workerRate + s.params.InvasionDrones*float64(droneCells)
+	if rate <= 0 {
+		return
+	}
+	prob := 1 - math.Exp(-rate)
+	share := workerRate / rate
+
+	invadingHealthy := s.binomial(s.mites.PhoreticHealthy, prob)
+	invadingInfected := s.binomial(s.mites.PhoreticInfected, prob)
+	workerHealthy := s.binomial(invadingHealthy, share)
+	workerInfected := s.binomial(invadingInfected, share)
+
+	workerHealthy, workerInfected = s.enterCells(workerCells, s.params.MaxPerWorkerCell, workerHealthy, workerInfected,
+		s.mites.WorkerCellsHealthy, s.mites.WorkerCellsInfected, s.infected.WorkerPupae)
+	droneHealthy, droneInfected := s.enterCells(droneCells, s.params.MaxPerDroneCell, invadingHealthy-workerHealthy, invadingInfected-workerInfected,
+		s.mites.DroneCellsHealthy, s.mites.DroneCellsInfected, s.infected.DronePupae)
+
+	s.mites.PhoreticHealthy -= workerHealthy + droneHealthy
+	s.mites.PhoreticInfected -= workerInfected + droneInfected
+	s.mites.Invaded = workerHealthy + workerInfected + droneHealthy + droneInfected
+}
+
+// enterCells places invading mites in the cells capped today, limited by the maximum number of mites per cell,
+// and infects the pupae. Returns the numbers of healthy and infected mites that entered.
+func (s *MiteProc) enterCells(cells int, maxPerCell int, healthy int, infected int, cellsHealthy []int, cellsInfected []int, pupae []int) (int, int) {
+	if capacity := cells * maxPerCell; healthy+infected > capacity {
+		healthy = capacity * healthy / (healthy + infected)
+		infected = capacity - healthy
+	}
+	cellsHealthy[0] = healthy
+	cellsInfected[0] = infected
+
+	infested := infestedCells(healthy+infected, cells)
+	if infested > 0 {
+		pupae[0] = s.binomial(infested, 1-math.Exp(-float64(infected)*s.params.MiteToPupa/float64(infested)))
+	}
+	return healthy, infected
+}
+
+// thin reduces a number of individuals in a cohort proportionally to the loss of the cohort from size before to size after.
+func (s *MiteProc) thin(count int, before int, after int) int {
+	if after >= before {
+		return count
+	}
+	return s.binomial(count, float64(after)/float64(before))
+}
+
+func (s *MiteProc) binomial(n int, p float64) int {
+	if n <= 0 || p <= 0 {
+		return 0
+	}
+	if p >= 1 {
+		return n
+	}
+	dist := distuv.Binomial{N: float64(n), P: p, Src: s.src}
+	return int(dist.Rand())
+}
+
+func (s *MiteProc) poisson(lambda float64) int {
+	if lambda <= 0 {
+		return 0
+	}
+	dist := distuv.Poisson{Lambda: lambda, Src: s.src}
+	return int(dist.Rand())
+}
+
+func (s *MiteProc) stochasticRound(v float64) int {
+	whole := math.Floor(v)
+	if s.rng.Float64() < v-whole {
+		whole++
+	}
+	return int(whole)
+}
+
+// infestedCells returns the expected number of cells with at least one mite, for mites distributed randomly over the cells.
+func infestedCells(mites int, cells int) int {
+	if mites <= 0 || cells <= 0 {
+		return 0
+	}
+	infested := int(math.Round(float64(cells) * (1 - math.Exp(-float64(mites)/float64(cells)))))
+	return util.Clamp(infested, 1, util.MinInt(mites, cells))
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
 	"github.com/fzeitner/Nursebeecs-master-thesis/params"
 	"github.com/fzeitner/Nursebeecs-master-thesis/util"
-	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
 	"gonum.org/v1/gonum/stat/distuv"
 )
@@ -16,7 +15,7 @@ import (
 type MortalityCohorts struct {
 	workerMort *params.WorkerMortality
 	droneMort  *params.DroneMortality
-	rng        *resource.Rand
+	rng        rand.Source
 
 	eggs   *globals.Eggs
 	larvae *globals.Larvae
@@ -27,7 +26,7 @@ type MortalityCohorts struct {
 func (s *MortalityCohorts) Initialize(w *ecs.World) {
 	s.workerMort = ecs.GetResource[params.WorkerMortality](w)
 	s.droneMort = ecs.GetResource[params.DroneMortality](w)
-	s.rng = ecs.GetResource[resource.Rand](w)
+	s.rng = ecs.GetResource[globals.RandStreams](w).Get(randCohortMortality)
 
 	s.eggs = ecs.GetResource[globals.Eggs](w)
 	s.larvae = ecs.GetResource[globals.Larvae](w)
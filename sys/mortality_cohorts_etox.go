@@ -7,7 +7,6 @@ import (
 	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
 	"github.com/fzeitner/Nursebeecs-master-thesis/params"
 	"github.com/fzeitner/Nursebeecs-master-thesis/util"
-	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
 )
 
@@ -26,7 +25,14 @@ type MortalityCohortsEtox struct {
 	etox  *params.PPPApplication
 	toxic *params.PPPToxicity
 
-	rng *resource.Rand
+	larvaeResponse        util.DoseResponse
+	inHiveResponse        util.DoseResponse
+	contactResponse       util.DoseResponse
+	larvaeChronicResponse util.DoseResponse
+	adultChronicResponse  util.DoseResponse
+	lethalCutoff          float64
+
+	rng rand.Source
 }
 
 func (s *MortalityCohortsEtox) Initialize(w *ecs.World) {
@@ -42,31 +48,61 @@ func (s *MortalityCohortsEtox) Initialize(w *ecs.World) {
 	s.etox = ecs.GetResource[params.PPPApplication](w)
 	s.toxic = ecs.GetResource[params.PPPToxicity](w)
 
-	s.rng = ecs.GetResource[resource.Rand](w)
+	// cohorts get a mean dose, so mortality is integrated over the distribution of doses within the cohort if configured
+	s.larvaeResponse = s.toxic.WithinCohort(s.toxic.LarvaeOralResponse())
+	s.inHiveResponse = s.toxic.WithinCohort(s.toxic.InHiveOralResponse())
+	s.contactResponse = s.toxic.WithinCohort(s.toxic.ForagerContactResponse()) // adult contact endpoints are the same for in-hive bees and foragers
+	s.larvaeChronicResponse = s.toxic.WithinCohort(s.toxic.LarvaeChronicResponse())
+	s.adultChronicResponse = s.toxic.WithinCohort(s.toxic.AdultChronicResponse())
+	s.lethalCutoff = s.toxic.LethalCutoff()
+
+	s.rng = ecs.GetResource[globals.RandStreams](w).Get(randCohortMortalityEtox)
 }
 
 func (s *MortalityCohortsEtox) Update(w *ecs.World) {
-	s.applyMortalityEtox(s.larvae.Workers, s.larvaeEtox.WorkerCohortDose, s.toxic.LarvaeOralSlope, s.toxic.LarvaeOralLD50)
-	s.applyMortalityEtox(s.larvae.Drones, s.larvaeEtox.DroneCohortDose, s.toxic.LarvaeOralSlope, s.toxic.LarvaeOralLD50)
+	// the cumulative larval dose is the exposure marker for sublethal effects in [DevelopmentEtox]
+	accumulateDose(s.larvaeEtox.WorkerLarvalDose, s.larvaeEtox.WorkerCohortDose)
+	accumulateDose(s.larvaeEtox.DroneLarvalDose, s.larvaeEtox.DroneCohortDose)
 
-	s.applyMortalityEtox(s.inHive.Workers, s.inHiveEtox.WorkerCohortDose, s.toxic.ForagerOralSlope, s.toxic.ForagerOralLD50)
+	s.applyMortalityEtox(s.larvae.Workers, s.larvaeEtox.WorkerCohortDose, s.larvaeResponse,
+		s.larvaeEtox.WorkerDoseHistory, s.larvaeChronicResponse, nil, true)
+	s.applyMortalityEtox(s.larvae.Drones, s.larvaeEtox.DroneCohortDose, s.larvaeResponse,
+		s.larvaeEtox.DroneDoseHistory, s.larvaeChronicResponse, nil, true)
 
-	s.applyMortalityEtox(s.inHive.Drones, s.inHiveEtox.DroneCohortDose, s.toxic.ForagerOralSlope, s.toxic.ForagerOralLD50)
+	s.applyMortalityEtox(s.inHive.Workers, s.inHiveEtox.WorkerCohortDose, s.inHiveResponse,
+		s.inHiveEtox.WorkerDoseHistory, s.adultChronicResponse, s.inHiveEtox.WorkerContactDose, false)
+
+	s.applyMortalityEtox(s.inHive.Drones, s.inHiveEtox.DroneCohortDose, s.inHiveResponse,
+		s.inHiveEtox.DroneDoseHistory, s.adultChronicResponse, s.inHiveEtox.DroneContactDose, false)
 
 	s.popStats.Reset() // resets cumulative and mean doses for the timestep
 }
 
 func (s *MortalityCohortsEtox) Finalize(w *ecs.World) {}
 
-func (s *MortalityCohortsEtox) applyMortalityEtox(coh []int, dose []float64, slope float64, LD50 float64) {
+func (s *MortalityCohortsEtox) applyMortalityEtox(coh []int, dose []float64, acute util.DoseResponse,
+	history [][]float64, chronic util.DoseResponse, contact []float64, larvae bool) {
 	r := rand.New(s.rng)
+	acuteCheck := !(s.toxic.ChronicMortality && s.toxic.ChronicOnly)
 	for i := range coh {
 		num := coh[i]
 		toDie := 0
-		if dose[i] > 1e-20 { // simple dose response relationship for all larvae/IHBees/drones
-			num = coh[i]
-			ldx := (1 - (1 / (1 + math.Pow((dose[i]/LD50), slope))))
-			if ldx > 0.99 { // introduced this because netlogo-version behaves the same way. This makes it much less likely to have single digit cohorts left over after very lethal PPP events
+
+		ldx := 0.
+		if acuteCheck && dose[i] > 1e-20 { // simple dose response relationship for all larvae/IHbees/drones
+			ldx = acute.Mortality(dose[i])
+		}
+		if s.toxic.ChronicMortality {
+			history[i][0] = dose[i]
+			ldx = 1 - (1-ldx)*(1-s.chronicMortality(i, len(coh), history[i], chronic, larvae))
+		}
+		if contact != nil && contact[i] > 1e-20 { // contact exposure is always acute, independent of the oral endpoints
+			ldx = 1 - (1-ldx)*(1-s.contactResponse.Mortality(contact[i]))
+			contact[i] = 0.
+		}
+
+		if ldx > 0 {
+			if ldx > s.lethalCutoff { // introduced this because netlogo-version behaves the same way. This makes it much less likely to have single digit cohorts left over after very lethal PPP events
 				ldx = 1
 			}
 			if s.etox.RealisticStoch { // this is deactivated by default and not part of BEEHAVE_ecotox, but found to make sense
@@ -88,4 +124,63 @@ func (s *MortalityCohortsEtox) applyMortalityEtox(coh []int, dose []float64, slo
 		coh[i] = util.MaxInt(0, num-toDie)
 		dose[i] = 0. // doses get reset to 0 after the mortality check in every timestep, only dose from previous day is ever relevant
 	}
+	if s.toxic.ChronicMortality {
+		shiftDoseHistory(history)
+	}
+}
+
+// chronicMortality calculates the daily mortality of a cohort from its dose history.
+//
+// Larvae are evaluated based on their cumulative dose in the window, adults based on their mean daily dose.
+// As chronic endpoints describe mortality over the whole test duration, the resulting mortality
+// is converted to a constant daily hazard over the period of exposure.
+func (s *MortalityCohortsEtox) chronicMortality(age int, cohorts int, history []float64, chronic util.DoseResponse, larvae bool) float64 {
+	window := len(history)
+	days := util.MinInt(window, age+1)
+
+	exposure := 0.
+	for _, d := range history[:days] {
+		exposure += d
+	}
+	if exposure <= 1e-20 {
+		return 0
+	}
+
+	period := window
+	if larvae {
+		period = util.MinInt(window, cohorts) // larvae can not be exposed longer than their development
+	} else {
+		exposure /= float64(days)
+	}
+
+	mort := chronic.Mortality(exposure)
+	if mort >= 1 {
+		return 1
+	}
+	return 1 - math.Pow(1-mort, 1/float64(period))
+}
+
+// accumulateDose adds the daily doses of cohorts to their cumulative doses.
+func accumulateDose(cum []float64, dose []float64) {
+	for i, d := range dose {
+		cum[i] += d
+	}
+}
+
+// shiftDoseHistory ages the dose windows along with the cohorts.
+// This anticipates the shift of the cohorts themselves in [AgeCohorts], which runs after this system.
+func shiftDoseHistory(history [][]float64) {
+	if len(history) == 0 {
+		return
+	}
+	last := history[len(history)-1]
+	copy(history[1:], history[:len(history)-1])
+	history[0] = last
+	for i := range last {
+		last[i] = 0
+	}
+	for _, h := range history[1:] {
+		copy(h[1:], h[:len(h)-1])
+		h[0] = 0
+	}
 }
@@ -0,0 +1,115 @@
+package sys
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordResponse returns a fixed mortality for any positive exposure and records the last exposure.
+type recordResponse struct {
+	mortality float64
+	exposure  float64
+}
+
+func (r *recordResponse) Mortality(dose float64) float64 {
+	r.exposure = dose
+	if dose <= 0 {
+		return 0
+	}
+	return r.mortality
+}
+
+func TestChronicMortalityWindowMean(t *testing.T) {
+	s := MortalityCohortsEtox{}
+	chronic := &recordResponse{mortality: 0.5}
+
+	// a young cohort is averaged over the days it was exposed only
+	history := []float64{3, 6, 0, 0, 0, 0, 0, 0, 0, 0}
+	mort := s.chronicMortality(1, 20, history, chronic, false)
+	assert.InDelta(t, 4.5, chronic.exposure, 1e-12)
+	assert.InDelta(t, 1-math.Pow(0.5, 0.1), mort, 1e-12)
+
+	// an older cohort is averaged over the whole window
+	history = []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	s.chronicMortality(15, 20, history, chronic, false)
+	assert.InDelta(t, 5.5, chronic.exposure, 1e-12)
+
+	// no exposure, no mortality
+	chronic.exposure = -1
+	assert.Equal(t, 0.0, s.chronicMortality(15, 20, make([]float64, 10), chronic, false))
+	assert.Equal(t, -1.0, chronic.exposure)
+
+	// full mortality over the test duration is full daily mortality
+	chronic.mortality = 1
+	assert.Equal(t, 1.0, s.chronicMortality(15, 20, history, chronic, false))
+}
+
+func TestChronicMortalityCumulative(t *testing.T) {
+	s := MortalityCohortsEtox{}
+	chronic := &recordResponse{mortality: 0.5}
+
+	// larvae are evaluated by their cumulative dose, over the days they were exposed
+	history := []float64{1, 2, 3, 4, 5, 6, 7}
+	mort := s.chronicMortality(3, 6, history, chronic, true)
+	assert.InDelta(t, 10, chronic.exposure, 1e-12)
+	// the mortality of the test is spread over the larval development, not the full window
+	assert.InDelta(t, 1-math.Pow(0.5, 1./6.), mort, 1e-12)
+
+	s.chronicMortality(10, 6, history, chronic, true)
+	assert.InDelta(t, 28, chronic.exposure, 1e-12)
+}
+
+func TestShiftDoseHistory(t *testing.T) {
+	history := [][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 9},
+	}
+	shiftDoseHistory(history)
+
+	assert.Equal(t, [][]float64{
+		{0, 0, 0},
+		{0, 1, 2},
+		{0, 4, 5},
+	}, history)
+
+	shiftDoseHistory(nil)
+}
+
+func TestChronicOnly(t *testing.T) {
+	run := func(chronicMortality, chronicOnly bool) (alive int, history [][]float64) {
+		s := MortalityCohortsEtox{
+			etox:         &params.PPPApplication{},
+			toxic:        &params.PPPToxicity{ChronicMortality: chronicMortality, ChronicOnly: chronicOnly},
+			lethalCutoff: 0.99,
+			rng:          rand.NewPCG(1, 2),
+		}
+		coh := []int{1000, 1000}
+		dose := []float64{1, 1}
+		history = [][]float64{make([]float64, 4), make([]float64, 4)}
+		s.applyMortalityEtox(coh, dose, &recordResponse{mortality: 0.5}, history, &recordResponse{mortality: 0}, nil, false)
+		assert.Equal(t, []float64{0, 0}, dose)
+		return coh[0] + coh[1], history
+	}
+
+	alive, history := run(false, false)
+	assert.Equal(t, 1000, alive)
+	assert.Equal(t, [][]float64{make([]float64, 4), make([]float64, 4)}, history)
+
+	// chronic endpoints in addition to the acute check
+	alive, history = run(true, false)
+	assert.Equal(t, 1000, alive)
+	assert.Equal(t, [][]float64{{0, 0, 0, 0}, {0, 1, 0, 0}}, history)
+
+	// chronic endpoints replace the acute check
+	alive, _ = run(true, true)
+	assert.Equal(t, 2000, alive)
+
+	// without chronic mortality, the toggle has no effect
+	alive, _ = run(false, true)
+	assert.Equal(t, 1000, alive)
+}
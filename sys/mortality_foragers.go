@@ -4,6 +4,7 @@ import (
 	"math/rand/v2"
 
 	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
 	"github.com/fzeitner/Nursebeecs-master-thesis/params"
 	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
@@ -14,20 +15,22 @@ import (
 //   - removal of squadrons reaching [params.WorkerDevelopment.MaxLifespan]
 //   - removal of squadrons exceeding [params.WorkerMortality.MaxMilage]
 type MortalityForagers struct {
-	rng           *resource.Rand
+	rng           rand.Source
 	time          *resource.Tick
 	workerMort    *params.WorkerMortality
 	workerDev     *params.WorkerDevelopment
 	toRemove      []ecs.Entity
 	foragerFilter *ecs.Filter2[comp.Age, comp.Milage]
+	exposure      foragerExposureRecorder
 }
 
 func (s *MortalityForagers) Initialize(w *ecs.World) {
-	s.rng = ecs.GetResource[resource.Rand](w)
+	s.rng = ecs.GetResource[globals.RandStreams](w).Get(randForagerMortality)
 	s.time = ecs.GetResource[resource.Tick](w)
 	s.workerMort = ecs.GetResource[params.WorkerMortality](w)
 	s.workerDev = ecs.GetResource[params.WorkerDevelopment](w)
 	s.foragerFilter = s.foragerFilter.New(w)
+	s.exposure = newForagerExposureRecorder(w)
 }
 
 func (s *MortalityForagers) Update(w *ecs.World) {
@@ -46,6 +49,7 @@ func (s *MortalityForagers) Update(w *ecs.World) {
 	}
 
 	for _, e := range s.toRemove {
+		s.exposure.record(e)
 		w.RemoveEntity(e)
 	}
 	s.toRemove = s.toRemove[:0]
@@ -1,36 +1,61 @@
 package sys
 
 import (
-	"math"
+	"math/rand/v2"
 
 	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
 	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
 	"github.com/fzeitner/Nursebeecs-master-thesis/params"
-	"github.com/mlange-42/ark-tools/resource"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
 	"github.com/mlange-42/ark/ecs"
 )
 
 // MortalityForagersEtox applies worker mortality, including
-//   - mortality from PPP exposure if applicable
+//   - mortality from PPP exposure if applicable; reverted foragers nursing in the nursebeecs models get the mean oral dose of the nurses,
+//     so their oral mortality is integrated over the within-cohort dose distribution like for in-hive cohorts (see [params.PPPToxicity.WithinCohort])
+//   - reduced longevity from the larval dose of the squadron, like [DevelopmentEtox] does for in-hive bees
 type MortalityForagersEtox struct {
-	rng                  *resource.Rand
+	rng                  *rand.Rand
 	toRemove             []ecs.Entity
 	foragerFilter        *ecs.Filter1[comp.PPPExpo]
 	foragersFilterSimple *ecs.Filter0
+	activityMapper       *ecs.Map1[comp.ActivityEtox]
 
-	etoxStats *globals.PopulationStatsEtox
-	etox      *params.PPPApplication
-	toxic     *params.PPPToxicity
+	etoxStats  *globals.PopulationStatsEtox
+	etox       *params.PPPApplication
+	treatments *params.InHiveTreatments
+	toxic      *params.PPPToxicity
+	workerMort *params.WorkerMortality
+	squadron   int
+
+	oralResponse      util.DoseResponse
+	nurseResponse     util.DoseResponse
+	contactResponse   util.DoseResponse
+	longevityResponse util.DoseResponse
+	carryOver         float64
+
+	exposure foragerExposureRecorder
 }
 
 func (s *MortalityForagersEtox) Initialize(w *ecs.World) {
-	s.rng = ecs.GetResource[resource.Rand](w)
 	s.foragerFilter = s.foragerFilter.New(w)
 	s.foragersFilterSimple = ecs.NewFilter0(w).With(ecs.C[comp.Age]())
+	s.activityMapper = s.activityMapper.New(w)
 
 	s.etoxStats = ecs.GetResource[globals.PopulationStatsEtox](w)
 	s.etox = ecs.GetResource[params.PPPApplication](w)
+	s.treatments = ecs.GetResource[params.InHiveTreatments](w)
 	s.toxic = ecs.GetResource[params.PPPToxicity](w)
+	s.workerMort = ecs.GetResource[params.WorkerMortality](w)
+	s.squadron = ecs.GetResource[params.Foragers](w).SquadronSize
+	s.rng = rand.New(ecs.GetResource[globals.RandStreams](w).Get(randLarvalEffects))
+
+	s.oralResponse = s.toxic.ForagerOralResponse()
+	s.nurseResponse = s.toxic.WithinCohort(s.oralResponse)
+	s.contactResponse = s.toxic.ForagerContactResponse()
+	s.longevityResponse = s.toxic.LongevityResponse()
+	s.carryOver = s.toxic.SublethalCarryOver()
+	s.exposure = newForagerExposureRecorder(w)
 }
 
 func (s *MortalityForagersEtox) Update(w *ecs.World) {
@@ -43,27 +68,47 @@ func (s *MortalityForagersEtox) Update(w *ecs.World) {
 		p := query.Get()
 		// mortality from PPP exposition, either dose-response relationship depending on their susceptibility to the contaminant or BeeGUTS can be called here
 		lethaldose := false
-		if s.etox.Application {
+		if s.etox.Application || s.treatments.Enabled() {
 			s.etoxStats.CumDoseForagers += p.OralDose * 100
 			if p.OralDose > 1e-20 && p.OralDose < s.toxic.ForagerOralLD50*1e5 {
-				if p.RdmSurvivalOral < 1-(1/(1+math.Pow(p.OralDose/s.toxic.ForagerOralLD50, s.toxic.ForagerOralSlope))) {
+				oral := s.oralResponse
+				if e := query.Entity(); s.activityMapper.HasAll(e) && s.activityMapper.Get(e).Reverted {
+					oral = s.nurseResponse
+				}
+				if p.RdmSurvivalOral < oral.Mortality(p.OralDose) {
 					lethaldose = true
 				}
 			}
-			if p.ContactDose > 0 {
-				if p.RdmSurvivalContact < 1-(1/(1+math.Pow(p.ContactDose/s.toxic.ForagerContactLD50, s.toxic.ForagerContactSlope))) {
+			contact := p.Contact()
+			if contact > 0 {
+				if p.RdmSurvivalContact < s.contactResponse.Mortality(contact) {
 					lethaldose = true
 				}
 			}
+			// lifetime exposure of the squadron; this is the only place where the daily doses are complete
+			p.CumOralDose += p.OralDose
+			p.CumContactDose += contact
+			p.PeakOralDose = max(p.PeakOralDose, p.OralDose)
+			p.PeakContactDose = max(p.PeakContactDose, contact)
+			if p.OralDose > 0 || contact > 0 {
+				p.DaysExposed++
+			}
+			p.RecentDose = p.RecentDose*s.carryOver + p.OralDose + contact
+
 			p.OralDose = 0.    // exposure doses get reset to 0 every tick BEFORE the added dose from honey and pollen consumption gets taken into account,
 			p.ContactDose = 0. // therefore exposure from foraging of the current day and exposure from food of the previous day is relevant for lethal effects only
+			p.TreatmentDose = 0.
 		}
 		if lethaldose {
 			s.toRemove = append(s.toRemove, query.Entity())
+		} else if s.reducedLongevity(p.LarvalDose) {
+			s.toRemove = append(s.toRemove, query.Entity())
+			s.etoxStats.LongevityDeaths += s.squadron
 		}
 	}
 
 	for _, e := range s.toRemove {
+		s.exposure.record(e)
 		w.RemoveEntity(e)
 	}
 	s.toRemove = s.toRemove[:0]
@@ -76,3 +121,14 @@ func (s *MortalityForagersEtox) Update(w *ecs.World) {
 }
 
 func (s *MortalityForagersEtox) Finalize(w *ecs.World) {}
+
+// reducedLongevity returns whether a squadron dies from its lifespan reduced by the larval dose.
+// Analogous to [DevelopmentEtox], a lifespan reduced by the fraction r raises the background mortality by the factor 1/(1-r).
+func (s *MortalityForagersEtox) reducedLongevity(larvalDose float64) bool {
+	if larvalDose <= 0 {
+		return false
+	}
+	reduction := min(s.toxic.LongevityMaxReduction*s.longevityResponse.Mortality(larvalDose), 0.99)
+	m := s.workerMort.InHive * (1/(1-reduction) - 1)
+	return m > 0 && s.rng.Float64() < m
+}
@@ -0,0 +1,49 @@
+package sys
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/dosedist"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMortalityForagersEtoxNurses(t *testing.T) {
+	w := ecs.NewWorld()
+
+	pe := params.DefaultEtox()
+	pe.PPPApplication.Application = true
+	pe.PPPToxicity.DoseDistribution = dosedist.LogNormal
+	pe.PPPToxicity.DoseCV = 2
+	p := params.Default()
+
+	ecs.AddResource(&w, &pe.PPPApplication)
+	ecs.AddResource(&w, &pe.InHiveTreatments)
+	ecs.AddResource(&w, &pe.PPPToxicity)
+	ecs.AddResource(&w, &p.WorkerMortality)
+	ecs.AddResource(&w, &p.Foragers)
+	ecs.AddResource(&w, &globals.PopulationStatsEtox{})
+	streams := globals.NewRandStreams(1)
+	ecs.AddResource(&w, &streams)
+
+	// a dose with a low mortality at the mean, but a high one for the upper tail of the within-cohort distribution
+	dose := pe.PPPToxicity.ForagerOralLD50 / 4
+	point := pe.PPPToxicity.ForagerOralResponse().Mortality(dose)
+	within := pe.PPPToxicity.WithinCohort(pe.PPPToxicity.ForagerOralResponse()).Mortality(dose)
+	assert.Greater(t, within, point)
+	survival := (point + within) / 2
+
+	mapper := ecs.NewMap2[comp.PPPExpo, comp.ActivityEtox](&w)
+	forager := mapper.NewEntity(&comp.PPPExpo{OralDose: dose, RdmSurvivalOral: survival, RdmSurvivalContact: 1}, &comp.ActivityEtox{})
+	nurse := mapper.NewEntity(&comp.PPPExpo{OralDose: dose, RdmSurvivalOral: survival, RdmSurvivalContact: 1}, &comp.ActivityEtox{Reverted: true})
+
+	s := MortalityForagersEtox{}
+	s.Initialize(&w)
+	s.Update(&w)
+
+	assert.True(t, w.Alive(forager))
+	assert.False(t, w.Alive(nurse))
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
 	"github.com/fzeitner/Nursebeecs-master-thesis/params"
 	"github.com/fzeitner/Nursebeecs-master-thesis/util"
-	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
 	"gonum.org/v1/gonum/stat/distuv"
 )
@@ -27,7 +26,7 @@ type Nbroodcare struct {
 	inHive   *globals.InHive
 	nGlobals *globals.NursingGlobals
 	pop      *globals.PopulationStats
-	rng      *resource.Rand
+	rng      rand.Source
 }
 
 func (s *Nbroodcare) Initialize(w *ecs.World) {
@@ -42,7 +41,7 @@ func (s *Nbroodcare) Initialize(w *ecs.World) {
 	s.nGlobals = ecs.GetResource[globals.NursingGlobals](w)
 
 	s.pop = ecs.GetResource[globals.PopulationStats](w)
-	s.rng = ecs.GetResource[resource.Rand](w)
+	s.rng = ecs.GetResource[globals.RandStreams](w).Get(randBroodCare)
 }
 
 func (s *Nbroodcare) Update(w *ecs.World) {
@@ -179,7 +179,7 @@ func (s *NurseConsumption) Update(w *ecs.World) {
 		foragernursefrac = float64(s.nStats.WinterBees+s.nStats.RevertedForagers) / float64(s.nStats.TotalNurses)
 	}
 	if s.nGlobals.SuffNurses && foragernursefrac <= 0.1 && s.nStats.RevertedForagers == 0 { // is a reduction in the nursing force possible?
-		TotalNurseCap_red := TotalNurseCap - float64(s.inHive.Workers[s.nGlobals.NurseAgeMax])*s.newCons.MaxPollenNurse*s.newCons.Nursingcapabiliies[s.nGlobals.NurseAgeMax] // could also use nonZeroCohort here for reducing to NurseAgeMax to that spot
+		TotalNurseCap_red := TotalNurseCap - float64(s.inHive.Workers[s.nGlobals.NurseAgeMax])*s.newCons.MaxPollenNurse*s.nGlobals.Capability[s.nGlobals.NurseAgeMax] // could also use nonZeroCohort here for reducing to NurseAgeMax to that spot
 		if TotalNurseCap_red >= s.nGlobals.Total_pollen {
 			s.nGlobals.Reductionpossible = true
 		}
@@ -237,10 +237,10 @@ func (s *NurseConsumption) calcNursingMetrics(w *ecs.World) (nursingcap float64,
 	maxpollenpernurse = 0.
 	for i := 4; i <= s.nGlobals.NurseAgeMax; i++ {
 		s.nStats.IHbeeNurses += s.inHive.Workers[i]
-		nursingcap += float64(s.inHive.Workers[i]) * s.nGlobals.CurrentMaxPollenNurse * s.newCons.Nursingcapabiliies[i]
+		nursingcap += float64(s.inHive.Workers[i]) * s.nGlobals.CurrentMaxPollenNurse * s.nGlobals.Capability[i]
 
-		if s.inHive.Workers[i] != 0 && s.nGlobals.CurrentMaxPollenNurse*s.newCons.Nursingcapabiliies[i] > maxpollenpernurse { // just to get some nursing stats for analysis and debugging
-			maxpollenpernurse = s.nGlobals.CurrentMaxPollenNurse * s.newCons.Nursingcapabiliies[i]
+		if s.inHive.Workers[i] != 0 && s.nGlobals.CurrentMaxPollenNurse*s.nGlobals.Capability[i] > maxpollenpernurse { // just to get some nursing stats for analysis and debugging
+			maxpollenpernurse = s.nGlobals.CurrentMaxPollenNurse * s.nGlobals.Capability[i]
 		}
 	}
 	// quick fix for the beginning and end of the year without IHbees via making starting foragers winterbees; also includes reverted foragers that might occur in times of great nursing needs or after mass death events
@@ -21,10 +21,8 @@ type NurseConsumptionEtox struct {
 
 	newCons     *params.ConsumptionRework
 	nurseParams *params.NursingRework
-	toxic       *params.PPPToxicity
 	larvae      *globals.Larvae
 	inHive      *globals.InHive
-	storesETOX  *globals.StoragesEtox
 
 	nStats   *globals.NursingStats
 	nGlobals *globals.NursingGlobals
@@ -43,10 +41,8 @@ func (s *NurseConsumptionEtox) Initialize(w *ecs.World) {
 
 	s.newCons = ecs.GetResource[params.ConsumptionRework](w)
 	s.nurseParams = ecs.GetResource[params.NursingRework](w)
-	s.toxic = ecs.GetResource[params.PPPToxicity](w)
 	s.larvae = ecs.GetResource[globals.Larvae](w)
 	s.inHive = ecs.GetResource[globals.InHive](w)
-	s.storesETOX = ecs.GetResource[globals.StoragesEtox](w)
 
 	s.nStats = ecs.GetResource[globals.NursingStats](w)
 	s.nGlobals = ecs.GetResource[globals.NursingGlobals](w)
@@ -58,20 +54,8 @@ func (s *NurseConsumptionEtox) Initialize(w *ecs.World) {
 }
 
 func (s *NurseConsumptionEtox) Update(w *ecs.World) {
-	// reduce maximum pollen intake here if last there was a HPGthreshold exceeded last step
-	if s.nurseParams.HGEffects && s.nurseParams.HGFoodIntake {
-		if s.storesETOX.Nectarconcbeforeeating >= s.toxic.HGthreshold[2] {
-			s.nGlobals.CurrentMaxPollenNurse = s.newCons.MaxPollenNurse * s.toxic.MaxPollenRed[2]
-		} else if s.storesETOX.Nectarconcbeforeeating >= s.toxic.HGthreshold[1] {
-			s.nGlobals.CurrentMaxPollenNurse = s.newCons.MaxPollenNurse * s.toxic.MaxPollenRed[1]
-		} else if s.storesETOX.Nectarconcbeforeeating >= s.toxic.HGthreshold[0] {
-			s.nGlobals.CurrentMaxPollenNurse = s.newCons.MaxPollenNurse * s.toxic.MaxPollenRed[0]
-		} else {
-			s.nGlobals.CurrentMaxPollenNurse = s.newCons.MaxPollenNurse
-		}
-	} else {
-		s.nGlobals.CurrentMaxPollenNurse = s.newCons.MaxPollenNurse
-	}
+	// PPP effects on jelly production act through the nursing capabilities set from the HPG state in NursingNeeds
+	s.nGlobals.CurrentMaxPollenNurse = s.newCons.MaxPollenNurse
 
 	// start by recalculating nursing metrics and total capacities
 	TotalNurseCap, maxpollenpernurse := s.calcNursingMetrics(w)
@@ -196,7 +180,7 @@ func (s *NurseConsumptionEtox) Update(w *ecs.World) {
 		foragernursefrac = float64(s.nStats.WinterBees+s.nStats.RevertedForagers) / float64(s.nStats.TotalNurses)
 	}
 	if s.nGlobals.SuffNurses && foragernursefrac <= 0.1 && s.nStats.RevertedForagers == 0 { // is a reduction in the nursing force possible?
-		TotalNurseCap_red := TotalNurseCap - float64(s.inHive.Workers[s.nGlobals.NurseAgeMax])*s.newCons.MaxPollenNurse*s.newCons.Nursingcapabiliies[s.nGlobals.NurseAgeMax] // could also use nonZeroCohort here for reducing to NurseAgeMax to that spot
+		TotalNurseCap_red := TotalNurseCap - float64(s.inHive.Workers[s.nGlobals.NurseAgeMax])*s.newCons.MaxPollenNurse*s.nGlobals.Capability[s.nGlobals.NurseAgeMax] // could also use nonZeroCohort here for reducing to NurseAgeMax to that spot
 		if TotalNurseCap_red >= s.nGlobals.Total_pollen {
 			s.nGlobals.Reductionpossible = true
 		}
@@ -244,21 +228,7 @@ func (s *NurseConsumptionEtox) Update(w *ecs.World) {
 		s.stores.ProteinFactorNurses = util.Clamp(s.stores.ProteinFactorNurses, 0.0, 1.0)
 	}
 
-	// effects on ProteinFactorNurses by reduced HG-activity based on Schott et al. 2021
-	if s.nurseParams.HGEffects && !s.nurseParams.HGFoodIntake {
-		if s.storesETOX.Nectarconcbeforeeating >= s.toxic.HGthreshold[2] {
-			s.stores.ProteinFactorNurses = util.Clamp(s.stores.ProteinFactorNurses, 0.0, s.toxic.ProteinFactorNurseExposed[2])
-		} else if s.storesETOX.Nectarconcbeforeeating >= s.toxic.HGthreshold[1] {
-			s.stores.ProteinFactorNurses = util.Clamp(s.stores.ProteinFactorNurses, 0.0, s.toxic.ProteinFactorNurseExposed[1])
-		} else if s.storesETOX.Nectarconcbeforeeating >= s.toxic.HGthreshold[0] {
-			s.stores.ProteinFactorNurses = util.Clamp(s.stores.ProteinFactorNurses, 0.0, s.toxic.ProteinFactorNurseExposed[0])
-		} else {
-			s.stores.ProteinFactorNurses = util.Clamp(s.stores.ProteinFactorNurses, 0.0, 1.0)
-		}
-	} else {
-		s.stores.ProteinFactorNurses = util.Clamp(s.stores.ProteinFactorNurses, 0.0, 1.0)
-	}
-
+	s.stores.ProteinFactorNurses = util.Clamp(s.stores.ProteinFactorNurses, 0.0, 1.0)
 }
 
 func (s *NurseConsumptionEtox) Finalize(w *ecs.World) {}
@@ -270,10 +240,10 @@ func (s *NurseConsumptionEtox) calcNursingMetrics(w *ecs.World) (nursingcap floa
 	maxpollenpernurse = 0.
 	for i := 4; i <= s.nGlobals.NurseAgeMax; i++ {
 		s.nStats.IHbeeNurses += s.inHive.Workers[i]
-		nursingcap += float64(s.inHive.Workers[i]) * s.nGlobals.CurrentMaxPollenNurse * s.newCons.Nursingcapabiliies[i]
+		nursingcap += float64(s.inHive.Workers[i]) * s.nGlobals.CurrentMaxPollenNurse * s.nGlobals.Capability[i]
 
-		if s.inHive.Workers[i] != 0 && s.nGlobals.CurrentMaxPollenNurse*s.newCons.Nursingcapabiliies[i] > maxpollenpernurse { // just to get some nursing stats for analysis and debugging
-			maxpollenpernurse = s.nGlobals.CurrentMaxPollenNurse * s.newCons.Nursingcapabiliies[i]
+		if s.inHive.Workers[i] != 0 && s.nGlobals.CurrentMaxPollenNurse*s.nGlobals.Capability[i] > maxpollenpernurse { // just to get some nursing stats for analysis and debugging
+			maxpollenpernurse = s.nGlobals.CurrentMaxPollenNurse * s.nGlobals.Capability[i]
 		}
 	}
 	// quick fix for the beginning and end of the year without IHbees via making starting foragers winterbees; also includes reverted foragers that might occur in times of great nursing needs or after mass death events
@@ -16,6 +16,7 @@ type NursingNeeds struct {
 	NurseParams    *params.NursingRework
 	newCons        *params.ConsumptionRework
 	affParams      *params.AgeFirstForaging
+	hpgParams      *params.HPGDevelopment
 
 	newCohorts *globals.NewCohorts
 	aff        *globals.AgeFirstForaging
@@ -29,7 +30,10 @@ type NursingNeeds struct {
 	pop        *globals.PopulationStats
 	cons       *globals.ConsumptionStats
 	time       *resource.Tick
-	rng        *resource.Rand
+	hpg        *globals.HPG
+
+	hpgResponse   util.DoseResponse
+	hpgInhibition float64
 
 	foragerFilter *ecs.Filter1[comp.ActivityEtox]
 	foragerMapper *ecs.Map1[comp.Age]
@@ -45,6 +49,7 @@ func (s *NursingNeeds) Initialize(w *ecs.World) {
 	s.NurseParams = ecs.GetResource[params.NursingRework](w)
 	s.newCons = ecs.GetResource[params.ConsumptionRework](w)
 	s.affParams = ecs.GetResource[params.AgeFirstForaging](w)
+	s.hpgParams = ecs.GetResource[params.HPGDevelopment](w)
 
 	s.newCohorts = ecs.GetResource[globals.NewCohorts](w)
 	s.aff = ecs.GetResource[globals.AgeFirstForaging](w)
@@ -59,7 +64,14 @@ func (s *NursingNeeds) Initialize(w *ecs.World) {
 	s.pop = ecs.GetResource[globals.PopulationStats](w)
 	s.cons = ecs.GetResource[globals.ConsumptionStats](w)
 	s.time = ecs.GetResource[resource.Tick](w)
-	s.rng = ecs.GetResource[resource.Rand](w)
+	s.hpg = ecs.GetResource[globals.HPG](w)
+
+	// PPP effects on the HPG are only possible in nursebeecs_ecotox
+	s.hpgResponse = util.NoEffect{}
+	if toxic := ecs.NewResource[params.PPPToxicity](w); toxic.Has() {
+		s.hpgResponse = toxic.Get().HPGInhibitionResponse()
+		s.hpgInhibition = toxic.Get().HPGMaxInhibition
+	}
 
 	s.foragerFilter = s.foragerFilter.New(w)
 	s.foragerMapper = s.foragerMapper.New(w)
@@ -67,6 +79,7 @@ func (s *NursingNeeds) Initialize(w *ecs.World) {
 }
 
 func (s *NursingNeeds) Update(w *ecs.World) {
+	s.updateHPG()
 
 	s.nGlobals.KillDrones = false
 	TotalNursesLastDay := s.nStats.TotalNurses                      // in case of etox mortality events in between last day´s consumption proc and now we recount available nurses here
@@ -171,6 +184,52 @@ func (s *NursingNeeds) Update(w *ecs.World) {
 
 func (s *NursingNeeds) Finalize(w *ecs.World) {}
 
+// newHPG creates the gland state of the initial IHbees, assuming their glands are developed according to their age.
+// As [NursingNeeds] ages the gland state before its first evaluation, just like [AgeCohorts] ages the cohorts,
+// cohort i is initialized with the size of age i+1. Thus, cohorts of age a start into the first tick with the potential size of age a.
+func newHPG(w *ecs.World) globals.HPG {
+	hpgParams := ecs.GetResource[params.HPGDevelopment](w)
+	inHive := ecs.GetResource[globals.InHive](w)
+	hpg := globals.HPG{
+		Size: make([]float64, len(inHive.Workers)),
+		Dose: make([]float64, len(inHive.Workers)),
+	}
+	for i := range hpg.Size {
+		hpg.Size[i] = hpgParams.Potential(i + 1)
+	}
+	return hpg
+}
+
+// updateHPG ages the gland state along with the IHbee cohorts, moves the gland size of each cohort towards its target
+// and sets the nursing capabilities of today. The target depends on age, nutrition and the PPP dose of the previous day.
+func (s *NursingNeeds) updateHPG() {
+	if !s.NurseParams.HGEffects {
+		copy(s.nGlobals.Capability, s.newCons.Nursingcapabiliies)
+		return
+	}
+
+	shiftMarker(s.hpg.Size, 0)
+	shiftMarker(s.hpg.Dose, 0)
+
+	growth, atrophy := s.hpgParams.Rates()
+	nutrition := s.hpgParams.Nutrition(s.stores.ProteinFactorNurses)
+	for i, size := range s.hpg.Size {
+		target := s.hpgParams.Potential(i) * nutrition * (1 - s.hpgInhibition*s.hpgResponse.Mortality(s.hpg.Dose[i]))
+		if target > size {
+			s.hpg.Size[i] += growth * (target - size)
+		} else {
+			s.hpg.Size[i] += atrophy * (target - size)
+		}
+	}
+
+	for i := range s.nGlobals.Capability {
+		if i < len(s.hpg.Size) {
+			s.nGlobals.Capability[i] = s.hpg.Size[i]
+		}
+	}
+	s.nGlobals.Capability[0] = 0 // freshly emerged bees never nurse, as with the default capabilities
+}
+
 func (s *NursingNeeds) calcNursingMetrics(w *ecs.World) {
 	s.nStats.IHbeeNurses = 0
 	s.nStats.WinterBees = 0
@@ -3,26 +3,33 @@ package sys
 // TODO: PPP input from read_in_file
 // TExposure_at_patch_ETOX <- netlogo proc
 import (
-	"math"
-
 	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
 	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
 	"github.com/mlange-42/ark-tools/resource"
 	"github.com/mlange-42/ark/ecs"
 )
 
 // PPPApplication calculates concentrations inside of nectar and pollen as well as contact exposure for any available patch.
 // submodel logic is basically identical to BEEHAVE_ecotox, but lacks the ability to simulate multiple applications of different PPP within one run.
+// Patches outside of the treated field receive the fraction of the application given by the drift curve.
 type PPPApplication struct {
 	time   *resource.Tick
 	filter *ecs.Filter2[comp.PatchPropertiesEtox, comp.ResourceEtox]
 
 	etox          *params.PPPApplication
 	energycontent *params.EnergyContent
+	pppfate       *globals.PPPFate
 
-	constantFilter *ecs.Filter3[comp.PatchPropertiesEtox, comp.ConstantPatch, comp.ResourceEtox]
-	seasonalFilter *ecs.Filter3[comp.PatchPropertiesEtox, comp.SeasonalPatch, comp.ResourceEtox]
+	constantFilter *ecs.Filter5[comp.PatchPropertiesEtox, comp.ConstantPatch, comp.ResourceEtox, comp.Resource, comp.PatchDistance]
+	seasonalFilter *ecs.Filter5[comp.PatchPropertiesEtox, comp.SeasonalPatch, comp.ResourceEtox, comp.Resource, comp.PatchDistance]
 	scriptedFilter *ecs.Filter3[comp.PatchPropertiesEtox, comp.ScriptedPatch, comp.ResourceEtox]
+
+	nectarKinetics  util.Kinetics
+	pollenKinetics  util.Kinetics
+	contactKinetics util.Kinetics
+	drift           util.DriftCurve
 }
 
 func (s *PPPApplication) Initialize(w *ecs.World) {
@@ -31,21 +38,31 @@ func (s *PPPApplication) Initialize(w *ecs.World) {
 
 	s.etox = ecs.GetResource[params.PPPApplication](w)
 	s.energycontent = ecs.GetResource[params.EnergyContent](w)
+	s.pppfate = ecs.GetResource[globals.PPPFate](w)
 
 	s.constantFilter = s.constantFilter.New(w)
 	s.seasonalFilter = s.seasonalFilter.New(w)
 	s.scriptedFilter = s.scriptedFilter.New(w)
 
+	s.nectarKinetics = s.etox.NectarKinetics.Kinetics(s.etox.DT50)
+	s.pollenKinetics = s.etox.PollenKinetics.Kinetics(s.etox.DT50)
+	s.contactKinetics = s.etox.ContactKinetics.Kinetics(s.etox.DT50)
+	s.drift = s.etox.Drift()
 }
 
 func (s *PPPApplication) Update(w *ecs.World) {
 	if s.etox.Application {
 		dayOfYear := int(s.time.Tick % 365)
 		etox_year := int(s.time.Tick / 365)
+		residueAge := float64((dayOfYear - s.etox.AppDay + 364) % 365) // days since application at the start of today's decline
+
+		nectarDecay := util.DecayFactor(s.nectarKinetics, residueAge)
+		pollenDecay := util.DecayFactor(s.pollenKinetics, residueAge)
+		contactDecay := util.DecayFactor(s.contactKinetics, residueAge)
 
 		constQuery := s.constantFilter.Query()
 		for constQuery.Next() {
-			props, con, res := constQuery.Get()
+			props, con, res, resources, dist := constQuery.Get()
 
 			props.PPPconcentrationNectar = res.PPPconcentrationNectar
 			props.PPPconcentrationPollen = res.PPPconcentrationPollen
@@ -54,33 +71,37 @@ func (s *PPPApplication) Update(w *ecs.World) {
 			if (etox_year >= s.etox.SpinupPhase && etox_year < s.etox.SpinupPhase+s.etox.ExposurePhase) ||
 				props.PPPconcentrationNectar+props.PPPconcentrationPollen+props.PPPcontactDose > 0 {
 				if s.etox.AppDay == dayOfYear && etox_year >= s.etox.SpinupPhase && etox_year < s.etox.SpinupPhase+s.etox.ExposurePhase {
+					drift := s.drift.Fraction(dist.DistToField)
 					if con.NectarConcentration != 0 {
-						props.PPPconcentrationNectar += ((s.etox.PPPconcentrationNectar / (1 - 0.1047*con.NectarConcentration)) / con.NectarConcentration) / (1000 * 1000 * s.energycontent.Sucrose) // looks complicated, but simply adjusts the units properly to mug/kJ depending on chemical properties
+						props.PPPconcentrationNectar += drift * ((s.etox.PPPconcentrationNectar / (1 - 0.1047*con.NectarConcentration)) / con.NectarConcentration) / (1000 * 1000 * s.energycontent.Sucrose) // looks complicated, but simply adjusts the units properly to mug/kJ depending on chemical properties
 					} else {
 						props.PPPconcentrationNectar += 0
 					}
-					props.PPPconcentrationPollen += s.etox.PPPconcentrationPollen / 1000 // mug/kg -> mug/g
-					props.PPPcontactDose += s.etox.PPPcontactExposure * s.etox.RUD * 0.1 // [kg/ha] * [(ha*mg)/(kg*kg)] * [g]
+					props.PPPconcentrationPollen += drift * s.etox.PPPconcentrationPollen / 1000 // mug/kg -> mug/g
+					props.PPPcontactDose += drift * s.etox.PPPcontactExposure * s.etox.RUD * 0.1 // [kg/ha] * [(ha*mg)/(kg*kg)] * [g]
 				}
 				if s.etox.ContactExposureOneDay && dayOfYear != s.etox.AppDay {
 					props.PPPcontactDose = 0
 				}
 				if dayOfYear >= s.etox.AppDay+s.etox.ExposurePeriod || // TODO: could add ReadInFile support here like in Netlogo; multiple applications
 					etox_year*365+dayOfYear == s.etox.SpinupPhase*365+(s.etox.ExposurePhase-1)*365+s.etox.AppDay+s.etox.ExposurePeriod {
+					s.pppfate.PPPpatchCleared += s.residues(props, resources, con.NectarConcentration) // residues left at the end of the exposure period
 					props.PPPconcentrationNectar = 0
 					props.PPPconcentrationPollen = 0
 					props.PPPcontactDose = 0
 				} else if dayOfYear != s.etox.AppDay {
-					props.PPPconcentrationNectar *= math.Exp(-math.Log(2) / s.etox.DT50)
-					props.PPPconcentrationPollen *= math.Exp(-math.Log(2) / s.etox.DT50)
-					props.PPPcontactDose *= math.Exp(-math.Log(2) / s.etox.DT50)
+					before := s.residues(props, resources, con.NectarConcentration)
+					props.PPPconcentrationNectar *= nectarDecay
+					props.PPPconcentrationPollen *= pollenDecay
+					props.PPPcontactDose *= contactDecay
+					s.pppfate.PPPpatchDegraded += before - s.residues(props, resources, con.NectarConcentration)
 				}
 			}
 		}
 
 		seasonalQuery := s.seasonalFilter.Query()
 		for seasonalQuery.Next() {
-			props, seas, res := seasonalQuery.Get()
+			props, seas, res, resources, dist := seasonalQuery.Get()
 
 			props.PPPconcentrationNectar = res.PPPconcentrationNectar
 			props.PPPconcentrationPollen = res.PPPconcentrationPollen
@@ -89,26 +110,30 @@ func (s *PPPApplication) Update(w *ecs.World) {
 			if (etox_year >= s.etox.SpinupPhase && etox_year < s.etox.SpinupPhase+s.etox.ExposurePhase) ||
 				props.PPPconcentrationNectar+props.PPPconcentrationPollen+props.PPPcontactDose > 0 {
 				if s.etox.AppDay == dayOfYear && etox_year > s.etox.SpinupPhase && etox_year < s.etox.SpinupPhase+s.etox.ExposurePhase {
+					drift := s.drift.Fraction(dist.DistToField)
 					if seas.NectarConcentration != 0 {
-						props.PPPconcentrationNectar += ((s.etox.PPPconcentrationNectar / (1 - 0.1047*seas.NectarConcentration)) / seas.NectarConcentration) / (1000 * 1000 * s.energycontent.Sucrose) // looks complicated, but simply adjusts the units properly to mug/kJ depending on chemical properties
+						props.PPPconcentrationNectar += drift * ((s.etox.PPPconcentrationNectar / (1 - 0.1047*seas.NectarConcentration)) / seas.NectarConcentration) / (1000 * 1000 * s.energycontent.Sucrose) // looks complicated, but simply adjusts the units properly to mug/kJ depending on chemical properties
 					} else {
 						props.PPPconcentrationNectar += 0
 					}
-					props.PPPconcentrationPollen += s.etox.PPPconcentrationPollen / 1000 // mug/kg -> mug/g
-					props.PPPcontactDose += s.etox.PPPcontactExposure * s.etox.RUD * 0.1 // [kg/ha] * [(ha*mg)/(kg*kg)] * [g]
+					props.PPPconcentrationPollen += drift * s.etox.PPPconcentrationPollen / 1000 // mug/kg -> mug/g
+					props.PPPcontactDose += drift * s.etox.PPPcontactExposure * s.etox.RUD * 0.1 // [kg/ha] * [(ha*mg)/(kg*kg)] * [g]
 				}
 				if s.etox.ContactExposureOneDay && dayOfYear != s.etox.AppDay {
 					props.PPPcontactDose = 0
 				}
 				if dayOfYear >= s.etox.AppDay+s.etox.ExposurePeriod || // TODO: could add ReadInFile support here like in Netlogo; multiple applications
 					etox_year*365+dayOfYear == s.etox.SpinupPhase*365+(s.etox.ExposurePhase-1)*365+s.etox.AppDay+s.etox.ExposurePeriod {
+					s.pppfate.PPPpatchCleared += s.residues(props, resources, seas.NectarConcentration) // residues left at the end of the exposure period
 					props.PPPconcentrationNectar = 0
 					props.PPPconcentrationPollen = 0
 					props.PPPcontactDose = 0
 				} else if dayOfYear != s.etox.AppDay {
-					props.PPPconcentrationNectar *= math.Exp(-math.Log(2) / s.etox.DT50)
-					props.PPPconcentrationPollen *= math.Exp(-math.Log(2) / s.etox.DT50)
-					props.PPPcontactDose *= math.Exp(-math.Log(2) / s.etox.DT50)
+					before := s.residues(props, resources, seas.NectarConcentration)
+					props.PPPconcentrationNectar *= nectarDecay
+					props.PPPconcentrationPollen *= pollenDecay
+					props.PPPcontactDose *= contactDecay
+					s.pppfate.PPPpatchDegraded += before - s.residues(props, resources, seas.NectarConcentration)
 				}
 			}
 		}
@@ -126,4 +151,10 @@ func (s *PPPApplication) Update(w *ecs.World) {
 	}
 }
 
+// residues returns the amount of PPP in the standing nectar and pollen of a patch [µg].
+func (s *PPPApplication) residues(props *comp.PatchPropertiesEtox, res *comp.Resource, nectarConcentration float64) float64 {
+	nectarEnergy := res.Nectar * nectarConcentration * s.energycontent.Sucrose // µL * mol/L * kJ/µmol = kJ
+	return props.PPPconcentrationNectar*nectarEnergy + props.PPPconcentrationPollen*res.Pollen
+}
+
 func (s *PPPApplication) Finalize(w *ecs.World) {}
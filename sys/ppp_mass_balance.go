@@ -0,0 +1,97 @@
+package sys
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// PPPMassBalance checks in every tick that all PPP that entered the hive (see [globals.PPPFate.Input])
+// is either stored in the hive, taken in by bees, degraded or lost, within the tolerance of [params.PPPApplication]
+// or the absolute Epsilon of [params.MassBalance]. A remainder beyond the tolerance is recorded as a [globals.AccountingEvent]. Only new imbalances are reported,
+// i.e. the remainder is compared to the one at the last event.
+//
+// At the end of a run, the number of accounting events of all checks is logged together with the first one,
+// and the mass balance is written as a CSV and a Markdown table
+// if [params.MassBalance] File is set, with the share of each row in the total input. Errors writing these files are logged. This replaces the manual aggregation in _examples/beecs_ecotox/PPP_massbalance.
+type PPPMassBalance struct {
+	etox       *params.PPPApplication
+	treatments *params.InHiveTreatments
+	params     *params.MassBalance
+	fate       *globals.PPPFate
+	stores     *globals.StoragesEtox
+	events     *globals.AccountingEvents
+	time       *resource.Tick
+
+	reported float64 // unaccounted PPP at the last accounting event
+}
+
+func (s *PPPMassBalance) Initialize(w *ecs.World) {
+	s.etox = ecs.GetResource[params.PPPApplication](w)
+	s.treatments = ecs.GetResource[params.InHiveTreatments](w)
+	s.params = ecs.GetResource[params.MassBalance](w)
+	s.fate = ecs.GetResource[globals.PPPFate](w)
+	s.stores = ecs.GetResource[globals.StoragesEtox](w)
+	s.events = ecs.GetResource[globals.AccountingEvents](w)
+	s.time = ecs.GetResource[resource.Tick](w)
+	s.reported = 0
+}
+
+func (s *PPPMassBalance) Update(w *ecs.World) {
+	if !s.etox.Application && !s.treatments.Enabled() {
+		return
+	}
+	balance := s.fate.MassBalance(s.stores)
+	unaccounted := balance[len(balance)-1].PPP
+	input := s.fate.Input()
+	if !checkAccountingEps(s.events, s.time.Tick, "PPP mass balance", input, input-(unaccounted-s.reported), s.etox.Tolerance(), s.params.Epsilon) {
+		s.reported = unaccounted
+	}
+}
+
+func (s *PPPMassBalance) Finalize(w *ecs.World) {
+	if s.events.Count > 0 {
+		log.Printf("%d accounting events, the first at %s", s.events.Count, s.events.Err().Error())
+	}
+	if s.params.File == "" {
+		return
+	}
+	balance := s.fate.MassBalance(s.stores)
+
+	csv := strings.Builder{}
+	md := strings.Builder{}
+	csv.WriteString("Sink;PPP;PercentOfInput\n")
+	md.WriteString("| Sink | PPP [µg] | Share of input [%] |\n")
+	md.WriteString("|------|---------:|-------------------:|\n")
+
+	rows := append([]globals.MassBalanceEntry{{Sink: "TotalPPPforaged", PPP: s.fate.TotalPPPforaged}, {Sink: "PPPwaxInput", PPP: s.fate.PPPwaxInput}, {Sink: "PPPtreatmentInput", PPP: s.fate.PPPtreatmentInput}, {Sink: "PPPpollenExcess", PPP: s.fate.PPPpollenExcess}}, balance...)
+	input := s.fate.Input()
+	for _, e := range rows {
+		percent := 0.0
+		if input > 0 {
+			percent = e.PPP / input * 100
+		}
+		fmt.Fprintf(&csv, "%s;%g;%g\n", e.Sink, e.PPP, percent)
+		fmt.Fprintf(&md, "| %s | %.4f | %.2f |\n", e.Sink, e.PPP, percent)
+	}
+
+	// the run is finished at this point, so failing to write the report must not abort it
+	if dir := filepath.Dir(s.params.File); dir != "" {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			log.Printf("error creating directory for mass balance '%s': %s", s.params.File, err.Error())
+			return
+		}
+	}
+	for _, f := range []struct{ ext, content string }{{".csv", csv.String()}, {".md", md.String()}} {
+		if err := os.WriteFile(s.params.File+f.ext, []byte(f.content), 0666); err != nil {
+			log.Printf("error writing mass balance file '%s': %s", s.params.File+f.ext, err.Error())
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package sys
+
+import (
+	"math/rand/v2"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/queen"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// QueenDynamics initializes and updates the queen state [globals.Queen], following [params.Queen].
+//
+// The laying queen ages and can be lost from background causes, or is superseded when she reaches the supersedure age.
+// After the loss of the queen, the colony rears an emergency queen if it has worker eggs or young worker larvae,
+// and becomes hopelessly queenless otherwise. New queens mate after emergence, which fails without drones.
+//
+// In the _ecotox models with [params.PPPToxicity] QueenEffects, the laying queen takes up PPP with the food processed by nurses.
+// Her dose reduces her egg laying and can lead to her loss.
+// Must run before [EggLaying].
+type QueenDynamics struct {
+	params *params.Queen
+	eggs   *globals.Eggs
+	larvae *globals.Larvae
+	pop    *globals.PopulationStats
+	rng    *rand.Rand
+
+	queen globals.Queen
+
+	storesEtox     *globals.StoragesEtox
+	toxic          *params.PPPToxicity
+	honeyNeeds     *params.HoneyNeeds
+	pollenNeeds    *params.PollenNeeds
+	energyParams   *params.EnergyContent
+	layingResponse util.DoseResponse
+	lethalResponse util.DoseResponse
+}
+
+func (s *QueenDynamics) Initialize(w *ecs.World) {
+	s.params = ecs.GetResource[params.Queen](w)
+	s.eggs = ecs.GetResource[globals.Eggs](w)
+	s.larvae = ecs.GetResource[globals.Larvae](w)
+	s.pop = ecs.GetResource[globals.PopulationStats](w)
+	s.rng = rand.New(ecs.GetResource[globals.RandStreams](w).Get(randQueen))
+
+	// PPP effects on the queen are only possible in the _ecotox models
+	s.layingResponse = util.NoEffect{}
+	s.lethalResponse = util.NoEffect{}
+	if toxic := ecs.NewResource[params.PPPToxicity](w); toxic.Has() && toxic.Get().QueenEffects {
+		s.toxic = toxic.Get()
+		s.storesEtox = ecs.GetResource[globals.StoragesEtox](w)
+		s.honeyNeeds = ecs.GetResource[params.HoneyNeeds](w)
+		s.pollenNeeds = ecs.GetResource[params.PollenNeeds](w)
+		s.energyParams = ecs.GetResource[params.EnergyContent](w)
+		s.layingResponse = s.toxic.LayingReductionResponse()
+		s.lethalResponse = s.toxic.QueenOralResponse()
+	}
+
+	s.queen = globals.Queen{
+		State:     queen.Laying,
+		Age:       s.params.InitialAge,
+		Fecundity: 1,
+	}
+	ecs.AddResource(w, &s.queen)
+}
+
+func (s *QueenDynamics) Update(w *ecs.World) {
+	q := &s.queen
+	q.Dose = 0
+	q.Fecundity = 1
+
+	switch q.State {
+	case queen.Laying:
+		q.Age++
+		lost := s.rng.Float64() < s.params.LossProbability
+		if s.toxic != nil {
+			q.Dose = s.queenDose()
+			q.Fecundity = 1 - s.toxic.LayingMaxReduction*s.layingResponse.Mortality(q.Dose)
+			lost = lost || s.rng.Float64() < s.lethalResponse.Mortality(q.Dose)
+		}
+		if lost {
+			q.Lose()
+			return
+		}
+		if q.Supersedure > 0 {
+			q.Supersedure--
+			if q.Supersedure == 0 { // the new queen emerges and replaces the old one
+				s.startMating()
+			}
+		} else if s.params.SupersedureAge > 0 && q.Age >= s.params.SupersedureAge {
+			q.Supersedure = util.MaxInt(s.params.RearingTime, 1)
+		}
+	case queen.Queenless:
+		if s.hasYoungBrood() {
+			q.State = queen.Rearing
+			q.Timer = s.params.RearingTime
+		} else {
+			q.State = queen.Hopeless
+		}
+	case queen.Rearing:
+		q.Timer--
+		if q.Timer <= 0 {
+			s.startMating()
+		}
+	case queen.Mating:
+		q.Age++
+		q.Timer--
+		if q.Timer <= 0 {
+			if s.pop.DronesInHive > 0 && s.rng.Float64() < s.params.MatingSuccess {
+				q.State = queen.Laying
+				q.Replacements++
+			} else {
+				q.Lose()
+			}
+		}
+	}
+}
+
+func (s *QueenDynamics) Finalize(w *ecs.World) {}
+
+// queenDose returns the daily oral dose of the queen [µg]. Her food is processed by nurses from the honey and pollen eaten
+// in the last step, in the same proportions and with the same filter effects as the food for worker larvae.
+func (s *QueenDynamics) queenDose() float64 {
+	honeyConc := s.storesEtox.Nectarconcbeforeeating * 0.001 * s.energyParams.Honey // [µg/kJ] -> [µg/mg]
+	pollenConc := s.storesEtox.Pollenconcbeforeeating * 0.001                       // [µg/g] -> [µg/mg]
+	conc := jellyConcentration(s.honeyNeeds.WorkerLarvaTotal, s.pollenNeeds.WorkerLarvaTotal,
+		honeyConc, pollenConc, s.toxic.NursebeesNectar, s.toxic.NursebeesPollen)
+	return s.toxic.QueenFoodIntake * conc * 1e-6 // [mg] * [µg/kg] -> [µg]
+}
+
+// startMating lets a new virgin queen emerge.
+func (s *QueenDynamics) startMating() {
+	s.queen.State = queen.Mating
+	s.queen.Timer = s.params.MatingTime
+	s.queen.Age = 0
+}
+
+// hasYoungBrood returns whether there are worker eggs or worker larvae young enough to rear a queen from.
+func (s *QueenDynamics) hasYoungBrood() bool {
+	for _, n := range s.eggs.Workers {
+		if n > 0 {
+			return true
+		}
+	}
+	for i := 0; i < s.params.MaxLarvaAge && i < len(s.larvae.Workers); i++ {
+		if s.larvae.Workers[i] > 0 {
+			return true
+		}
+	}
+	return false
+}
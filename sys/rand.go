@@ -0,0 +1,23 @@
+package sys
+
+// Names of the random number streams of the systems, see [globals.RandStreams].
+// Equivalent processes of the different model versions use the same stream.
+const (
+	randInitPopulation      = "InitPopulation"
+	randForagingPeriod      = "ForagingPeriod"
+	randWaterForagingPeriod = "WaterForagingPeriod"
+	randForaging            = "Foraging"
+	randWaterForaging       = "WaterForaging"
+	randForagerMortality    = "ForagerMortality"
+	randCohortMortality     = "CohortMortality"
+	randBroodCare           = "BroodCare"
+	randForagerSensitivity  = "ForagerSensitivity" // individual sensitivity of foragers to PPP
+	randHomingFailure       = "HomingFailure"
+	randCohortMortalityEtox = "CohortMortalityEtox"
+	randLarvalEffects       = "LarvalEffects"
+	randInHiveExposure      = "InHiveExposure" // distribution of PPP in the food among in-hive bees and larvae
+	randMites               = "Mites"          // Varroa mite population and virus transmission
+	randQueen               = "Queen"
+	randSwarming            = "Swarming"
+	randManagement          = "Management"
+)
@@ -0,0 +1,40 @@
+package sys
+
+import (
+	"math/rand/v2"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// squadronRemoval removes forager squadrons that leave the colony, like a swarm or a colony split.
+// The exposure of each removed squadron is recorded, see [foragerExposureRecorder].
+type squadronRemoval struct {
+	filter   *ecs.Filter1[comp.Age]
+	exposure foragerExposureRecorder
+	toRemove []ecs.Entity
+}
+
+func newSquadronRemoval(w *ecs.World) squadronRemoval {
+	return squadronRemoval{
+		filter:   ecs.NewFilter1[comp.Age](w),
+		exposure: newForagerExposureRecorder(w),
+	}
+}
+
+// removeFraction removes each squadron with the given probability and returns the number of squadrons removed.
+func (r *squadronRemoval) removeFraction(w *ecs.World, rng *rand.Rand, fraction float64) int {
+	query := r.filter.Query()
+	for query.Next() {
+		if rng.Float64() < fraction {
+			r.toRemove = append(r.toRemove, query.Entity())
+		}
+	}
+	for _, e := range r.toRemove {
+		r.exposure.record(e)
+		w.RemoveEntity(e)
+	}
+	removed := len(r.toRemove)
+	r.toRemove = r.toRemove[:0]
+	return removed
+}
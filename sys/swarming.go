@@ -0,0 +1,128 @@
+package sys
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// Swarming initializes and updates the swarming of the colony [globals.Swarming], following [params.Swarming].
+// It does nothing unless swarming is enabled.
+//
+// During the swarming season, a congested colony with a laying queen swarms with a daily probability, at most once per year.
+// The queen leaves with the swarm, together with a fraction of each in-hive worker cohort, of the forager squadrons
+// and of the phoretic mites. Drones and brood stay in the parent colony. Swarming bees take honey along;
+// in the _ecotox models, the PPP in this honey leaves the hive as well.
+// The parent colony is queenless until the first new queen emerges, see [globals.Queen].
+//
+// Must run after [QueenDynamics], so that only a queen that is still laying today leaves with the swarm,
+// and rearing from the queen cells left behind starts with the next update of [QueenDynamics].
+// Must run before [EggLaying], so that no eggs are laid on the day the queen leaves.
+type Swarming struct {
+	params        *params.Swarming
+	nurseParams   *params.Nursing
+	energyParams  *params.EnergyContent
+	foragerParams *params.Foragers
+	time          *resource.Tick
+	rng           *rand.Rand
+
+	inHive *globals.InHive
+	stores *globals.Stores
+	pop    *globals.PopulationStats
+	queen  *globals.Queen
+	mites  *globals.Mites
+
+	honeyStores *globals.HoneyStoresEtox
+	pppFate     *globals.PPPFate
+
+	swarming globals.Swarming
+
+	squadrons squadronRemoval
+}
+
+func (s *Swarming) Initialize(w *ecs.World) {
+	s.params = ecs.GetResource[params.Swarming](w)
+	s.nurseParams = ecs.GetResource[params.Nursing](w)
+	s.energyParams = ecs.GetResource[params.EnergyContent](w)
+	s.foragerParams = ecs.GetResource[params.Foragers](w)
+	s.time = ecs.GetResource[resource.Tick](w)
+	s.rng = rand.New(ecs.GetResource[globals.RandStreams](w).Get(randSwarming))
+
+	s.inHive = ecs.GetResource[globals.InHive](w)
+	s.stores = ecs.GetResource[globals.Stores](w)
+	s.pop = ecs.GetResource[globals.PopulationStats](w)
+	s.queen = ecs.GetResource[globals.Queen](w)
+	s.mites = ecs.GetResource[globals.Mites](w)
+
+	// the compartments of the honey stores only exist in the _ecotox models
+	if honeyStores := ecs.NewResource[globals.HoneyStoresEtox](w); honeyStores.Has() {
+		s.honeyStores = honeyStores.Get()
+		s.pppFate = ecs.GetResource[globals.PPPFate](w)
+	}
+
+	s.swarming = globals.Swarming{LastSwarm: -1}
+	ecs.AddResource(w, &s.swarming)
+
+	s.squadrons = newSquadronRemoval(w)
+}
+
+func (s *Swarming) Update(w *ecs.World) {
+	s.swarming.Bees, s.swarming.Honey, s.swarming.Mites = 0, 0, 0
+	if !s.params.Enabled || !s.isCongested() || s.rng.Float64() >= s.params.Probability {
+		return
+	}
+	s.swarm(w)
+}
+
+func (s *Swarming) Finalize(w *ecs.World) {}
+
+// isCongested returns whether the colony is ready to swarm today.
+func (s *Swarming) isCongested() bool {
+	dayOfYear := int(s.time.Tick % 365)
+	if dayOfYear < s.params.SeasonStart || dayOfYear > s.params.SeasonEnd || !s.queen.IsLaying() {
+		return false
+	}
+	if s.swarming.LastSwarm >= 0 && s.swarming.LastSwarm/365 == s.time.Tick/365 {
+		return false
+	}
+	maxCells := float64(s.nurseParams.MaxBroodCells)
+	return float64(s.pop.TotalBrood) >= s.params.BroodCongestion*maxCells &&
+		float64(s.pop.TotalAdults) >= s.params.AdultDensity*maxCells
+}
+
+// swarm lets the swarm leave the colony.
+func (s *Swarming) swarm(w *ecs.World) {
+	bees := 0
+	for i, n := range s.inHive.Workers {
+		leaving := int(math.Round(float64(n) * s.params.Fraction))
+		s.inHive.Workers[i] -= leaving
+		bees += leaving
+	}
+
+	bees += s.squadrons.removeFraction(w, s.rng, s.params.Fraction) * s.foragerParams.SquadronSize
+
+	honey := math.Min(float64(bees)*s.params.HoneyLoad*0.001*s.energyParams.Honey, math.Max(s.stores.Honey, 0)) // [mg] -> [g] * [kJ/g]
+	s.stores.Honey -= honey
+	if s.honeyStores != nil {
+		ppp, _ := s.honeyStores.Feed(honey, false) // the bees engorge on uncapped honey first
+		s.pppFate.PPPswarm += ppp
+	}
+
+	healthy := int(math.Round(float64(s.mites.PhoreticHealthy) * s.params.Fraction))
+	infected := int(math.Round(float64(s.mites.PhoreticInfected) * s.params.Fraction))
+	s.mites.PhoreticHealthy -= healthy
+	s.mites.PhoreticInfected -= infected
+
+	s.queen.Supersedure = max(s.params.QueenEmergence, 1) // the parent colony is left with capped queen cells
+	s.queen.Lose()
+
+	s.swarming.Swarms++
+	s.swarming.LastSwarm = s.time.Tick
+	s.swarming.Bees = bees
+	s.swarming.Honey = honey
+	s.swarming.Mites = healthy + infected
+}
@@ -20,6 +20,7 @@ type FixedTermination struct {
 	foragersFilter *ecs.Filter0
 	toKill         []ecs.Entity
 	inHive         *globals.InHive
+	exposure       foragerExposureRecorder
 }
 
 func (s *FixedTermination) Initialize(w *ecs.World) {
@@ -31,6 +32,7 @@ func (s *FixedTermination) Initialize(w *ecs.World) {
 
 	s.foragersFilter = ecs.NewFilter0(w).With(ecs.C[comp.Age]())
 	s.inHive = ecs.GetResource[globals.InHive](w)
+	s.exposure = newForagerExposureRecorder(w)
 }
 
 func (s *FixedTermination) Update(w *ecs.World) {
@@ -66,6 +68,7 @@ func (s *FixedTermination) KillColony(w *ecs.World) {
 		s.toKill = append(s.toKill, q.Entity())
 	}
 	for _, e := range s.toKill {
+		s.exposure.record(e)
 		w.RemoveEntity(e)
 	}
 	s.toKill = s.toKill[:0]
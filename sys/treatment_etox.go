@@ -0,0 +1,176 @@
+package sys
+
+import (
+	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/treatment"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// InHiveTreatmentEtox applies veterinary treatments of the beekeeper inside the hive, like varroacides.
+//
+// Strips are placed on the first day of the treatment window and release a fraction of the remaining
+// active ingredient each day until they are removed at the end of the window. Trickling and fumigation
+// release their dose in pulses. Of the released active ingredient, fixed shares go to the honey stores and
+// to the comb wax (see [WaxEtox]), and the rest forms an in-hive residue that degrades with the product's DT50.
+// Adult bees pick up a fraction of the residue each day as contact dose, split evenly over
+// in-hive workers, drones and foragers. Residues of fumigation not picked up on the day are vented.
+// If the treatment has its own contact LD50, the contact dose is converted into the dose of the simulated PPP
+// with the same toxicity (see [params.Treatment.ContactEquivalent]), so that it can be added to the contact doses of the PPP.
+//
+// Limitation: the model tracks a single PPP. Only the in-hive residue degrades with the DT50 and acts with the contact LD50
+// of the treatment; once in the honey stores or the comb wax, the active ingredient follows the store and wax kinetics
+// of the simulated PPP, and its doses are evaluated with the toxicity of [params.PPPToxicity].
+// Treatments are therefore only fully meaningful for the active ingredient that is simulated as the PPP.
+//
+// Must run after the feeding in [EtoxStorages] or [EtoxStoragesNbeecs], so that the released active ingredient
+// reaches the bees from the next day on: through the honey they eat, and as contact dose evaluated by the etox mortality
+// ([MortalityCohortsEtox] and [MortalityForagersEtox]). Must run before [PPPMassBalance], which needs today's input.
+// This is part of beecs_ecotox/nursebeecs_ecotox only, and does nothing if no treatments are configured.
+type InHiveTreatmentEtox struct {
+	treatments    *params.InHiveTreatments
+	wax           *params.Wax
+	energyParams  *params.EnergyContent
+	foragerParams *params.Foragers
+	toxic         *params.PPPToxicity
+
+	time        *resource.Tick
+	inHive      *globals.InHive
+	inHiveEtox  *globals.InHiveEtox
+	stores      *globals.StoragesEtox
+	honeyStores *globals.HoneyStoresEtox
+	pppFate     *globals.PPPFate
+
+	foragerFilter *ecs.Filter1[comp.PPPExpo]
+
+	device  []float64 // active ingredient remaining in the strips of each treatment [µg]
+	residue []float64 // in-hive residue of each treatment [µg]
+}
+
+func (s *InHiveTreatmentEtox) Initialize(w *ecs.World) {
+	s.treatments = ecs.GetResource[params.InHiveTreatments](w)
+	s.wax = ecs.GetResource[params.Wax](w)
+	s.energyParams = ecs.GetResource[params.EnergyContent](w)
+	s.foragerParams = ecs.GetResource[params.Foragers](w)
+	s.toxic = ecs.GetResource[params.PPPToxicity](w)
+
+	s.time = ecs.GetResource[resource.Tick](w)
+	s.inHive = ecs.GetResource[globals.InHive](w)
+	s.inHiveEtox = ecs.GetResource[globals.InHiveEtox](w)
+	s.stores = ecs.GetResource[globals.StoragesEtox](w)
+	s.honeyStores = ecs.GetResource[globals.HoneyStoresEtox](w)
+	s.pppFate = ecs.GetResource[globals.PPPFate](w)
+
+	s.foragerFilter = s.foragerFilter.New(w)
+
+	s.device = make([]float64, len(s.treatments.Treatments))
+	s.residue = make([]float64, len(s.treatments.Treatments))
+}
+
+func (s *InHiveTreatmentEtox) Update(w *ecs.World) {
+	if !s.treatments.Enabled() {
+		return
+	}
+
+	dayOfYear := int(s.time.Tick % 365)
+	honey := s.honeyStores.Total() / s.energyParams.Honey // [kJ] -> [g]
+	adults := s.countAdults()
+
+	contact := 0.    // active ingredient picked up [µg]
+	equivalent := 0. // contact expressed as dose of the simulated PPP with the same toxicity [µg]
+	s.stores.PPPtreatment = 0
+	for i := range s.treatments.Treatments {
+		t := &s.treatments.Treatments[i]
+
+		degraded := s.residue[i] * (1 - t.Decay())
+		s.residue[i] -= degraded
+		s.pppFate.PPPtreatmentDegraded += degraded
+
+		if t.Method == treatment.Strip && dayOfYear == t.Start {
+			s.device[i] = t.Dose
+		}
+		released := t.Release(dayOfYear, s.device[i])
+		if t.Method == treatment.Strip {
+			s.device[i] -= released
+		}
+		if released > 0 {
+			s.release(i, t, released, honey)
+		}
+
+		if adults > 0 {
+			picked := s.residue[i] * t.ContactRate
+			s.residue[i] -= picked
+			contact += picked
+			equivalent += t.ContactEquivalent(picked, s.toxic.ForagerContactLD50)
+		}
+		if t.Method == treatment.Fumigation {
+			s.pppFate.PPPtreatmentDegraded += s.residue[i]
+			s.residue[i] = 0
+		}
+		if t.Method == treatment.Strip && dayOfYear == t.End {
+			s.device[i] = 0 // strips are removed together with the active ingredient left in them
+		}
+
+		s.stores.PPPtreatment += s.residue[i]
+	}
+
+	if contact > 0 {
+		s.applyContact(equivalent / float64(adults))
+		s.pppFate.PPPtreatmentContact += contact
+	}
+
+	s.stores.PPPhoneyTotal = s.honeyStores.TotalPPP()
+	s.stores.PPPTotal = s.stores.PPPpollenTotal + s.stores.PPPhoneyTotal
+}
+
+func (s *InHiveTreatmentEtox) Finalize(w *ecs.World) {}
+
+// release splits the active ingredient released by a treatment between the honey stores, the comb wax and the in-hive residue.
+func (s *InHiveTreatmentEtox) release(i int, t *params.Treatment, released float64, honey float64) {
+	s.pppFate.PPPtreatmentInput += released
+
+	toHoney := 0.
+	if honey > 0 {
+		toHoney = released * t.ShareHoney
+		s.honeyStores.Exchange(toHoney)
+		s.pppFate.PPPtreatmentToHoney += toHoney
+	}
+	toWax := 0.
+	if s.wax.WaxCompartment {
+		toWax = released * t.ShareWax
+		s.stores.PPPwax += toWax
+		s.pppFate.PPPtreatmentToWax += toWax
+	}
+	s.residue[i] += released - toHoney - toWax
+}
+
+// countAdults returns the number of adult bees exposed to the in-hive residues.
+func (s *InHiveTreatmentEtox) countAdults() int {
+	num := countCohort(s.inHive.Workers) + countCohort(s.inHive.Drones)
+	query := s.foragerFilter.Query()
+	num += query.Count() * s.foragerParams.SquadronSize
+	query.Close()
+	return num
+}
+
+// applyContact adds the contact dose per bee to all adult cohorts and forager squadrons.
+// For squadrons, it is kept apart from the contact dose of foraging, see [comp.PPPExpo].
+func (s *InHiveTreatmentEtox) applyContact(dose float64) {
+	for i, n := range s.inHive.Workers {
+		if n > 0 {
+			s.inHiveEtox.WorkerContactDose[i] += dose
+		}
+	}
+	for i, n := range s.inHive.Drones {
+		if n > 0 {
+			s.inHiveEtox.DroneContactDose[i] += dose
+		}
+	}
+	query := s.foragerFilter.Query()
+	for query.Next() {
+		expo := query.Get()
+		expo.TreatmentDose += dose
+	}
+}
@@ -0,0 +1,170 @@
+package sys
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/comp"
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark-tools/resource"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// WaterForagingEtox lets forager squadrons collect water in the morning, before the regular foraging.
+//
+// The water need is the need for cooling (see [CalcWaterForagingPeriod]) plus the water needed for diluting
+// the capped honey eaten since the last water foraging. Just enough squadrons to meet the need are sent out,
+// replacing squadrons that find no water source, each making ETOX_Watertripsperh trips per hour during the first ETOX_Durationofwatertrips hours of the foraging period.
+// Squadrons keep collecting from the water source they know, and search a new one by detection probability otherwise.
+//
+// PPP in the water from contaminated puddles, guttation drops or surface water is added to the PPP foraged.
+// The PPP of the water collected for dilution is taken in by the bees eating capped honey today, including nurses and larvae.
+// PPP in water used for cooling, and in dilution water that is not used until the next water foraging,
+// stays behind on the combs when the water evaporates.
+//
+// Water foraging does not change the time and energy budgets of the regular foraging,
+// and squadrons collecting water still take part in all foraging rounds of the day.
+// Water trips are short and take place early, so their time, energy and mortality are neglected.
+//
+// Must run after [CalcWaterForagingPeriod], which sets today's need for cooling, and before [ForagingEtox]
+// and [EtoxStorages] or [EtoxStoragesNbeecs], which use the collected water when bees eat capped honey.
+// The need for dilution is thus the one accumulated by these systems since the last water foraging.
+// This is part of beecs_ecotox/nursebeecs_ecotox only, and does nothing if water foraging is disabled.
+type WaterForagingEtox struct {
+	waterParams   *params.WaterForaging
+	foragerParams *params.Foragers
+
+	time       *resource.Tick
+	period     *globals.ForagingPeriod
+	waterNeeds *globals.WaterNeeds
+	stores     *globals.StoragesEtox
+	pppFate    *globals.PPPFate
+	stats      globals.WaterForagingStats
+
+	foragerFilter *ecs.Filter2[comp.ActivityEtox, comp.KnownPatchEtox]
+	foragerMapper *ecs.Map2[comp.ActivityEtox, comp.KnownPatchEtox]
+	waterFilter   *ecs.Filter1[comp.WaterPatch]
+	waterMapper   *ecs.Map1[comp.WaterPatch]
+
+	rng        *rand.Rand
+	squadrons  []ecs.Entity
+	candidates []ecs.Entity
+}
+
+func (s *WaterForagingEtox) Initialize(w *ecs.World) {
+	s.waterParams = ecs.GetResource[params.WaterForaging](w)
+	s.foragerParams = ecs.GetResource[params.Foragers](w)
+
+	s.time = ecs.GetResource[resource.Tick](w)
+	s.period = ecs.GetResource[globals.ForagingPeriod](w)
+	s.waterNeeds = ecs.GetResource[globals.WaterNeeds](w)
+	s.stores = ecs.GetResource[globals.StoragesEtox](w)
+	s.pppFate = ecs.GetResource[globals.PPPFate](w)
+	s.stats = globals.WaterForagingStats{}
+	ecs.AddResource(w, &s.stats)
+
+	s.foragerFilter = s.foragerFilter.New(w)
+	s.foragerMapper = s.foragerMapper.New(w)
+	s.waterFilter = s.waterFilter.New(w)
+	s.waterMapper = s.waterMapper.New(w)
+
+	s.rng = rand.New(ecs.GetResource[globals.RandStreams](w).Get(randWaterForaging))
+}
+
+func (s *WaterForagingEtox) Update(w *ecs.World) {
+	if !s.waterParams.WaterForaging {
+		return
+	}
+
+	// dilution water from yesterday evaporates
+	s.pppFate.PPPwaterEvaporated += s.stores.PPPwater
+	s.stores.PPPwater = 0
+	s.stores.ETOX_Waterfordilution = 0
+
+	s.stats = globals.WaterForagingStats{
+		NeedCooling:  s.waterNeeds.ETOX_Waterneedforcooling,
+		NeedDilution: s.stores.ETOX_Waterneedfordilution,
+	}
+	s.stores.ETOX_Waterneedfordilution = 0
+
+	s.selectSquadrons()
+	need := s.stats.NeedCooling + s.stats.NeedDilution
+	if need <= 0 || len(s.squadrons) == 0 {
+		return
+	}
+
+	hours := math.Min(s.waterParams.ETOX_Durationofwatertrips, float64(s.period.SecondsToday)/3600)
+	perSquadron := s.waterParams.ETOX_cropvolume_water * s.waterParams.ETOX_Watertripsperh * hours * float64(s.foragerParams.SquadronSize)
+	if perSquadron <= 0 {
+		return
+	}
+
+	// squadrons that find no water source do not collect, so further squadrons are sent out until the need is met
+	dayOfYear := int(s.time.Tick % 365)
+	for _, e := range s.squadrons {
+		if s.stats.Collected >= need {
+			break
+		}
+		_, known := s.foragerMapper.Get(e)
+		patch := s.waterSource(known)
+		if patch.IsZero() {
+			continue
+		}
+
+		water := math.Min(perSquadron, need-s.stats.Collected)
+		ppp := water * 0.001 * s.waterMapper.Get(patch).Concentration(dayOfYear) // [g] -> [L] * [µg/L]
+		s.stats.Collected += water
+		s.stats.PPP += ppp
+		s.stats.Squadrons++
+	}
+
+	// the collected water is split between cooling and dilution according to the needs
+	dilution := s.stats.NeedDilution / need
+	s.stores.ETOX_Waterfordilution = s.stats.Collected * dilution
+	s.stores.PPPwater = s.stats.PPP * dilution
+	s.pppFate.PPPwaterEvaporated += s.stats.PPP * (1 - dilution)
+
+	s.pppFate.PPPwaterForaged += s.stats.PPP
+	s.pppFate.TotalPPPforaged += s.stats.PPP
+}
+
+func (s *WaterForagingEtox) Finalize(w *ecs.World) {}
+
+// selectSquadrons collects the squadrons available for water foraging in random order.
+func (s *WaterForagingEtox) selectSquadrons() {
+	s.squadrons = s.squadrons[:0]
+	query := s.foragerFilter.Query()
+	for query.Next() {
+		act, _ := query.Get()
+		if !act.Reverted {
+			s.squadrons = append(s.squadrons, query.Entity())
+		}
+	}
+	s.rng.Shuffle(len(s.squadrons), func(i, j int) {
+		s.squadrons[i], s.squadrons[j] = s.squadrons[j], s.squadrons[i]
+	})
+}
+
+// waterSource returns the water patch known by a squadron, or searches a new one by detection probability.
+// Returns the zero entity if no water source is found.
+func (s *WaterForagingEtox) waterSource(known *comp.KnownPatchEtox) ecs.Entity {
+	if !known.Water.IsZero() { // water patches are never removed
+		return known.Water
+	}
+	s.candidates = s.candidates[:0]
+	query := s.waterFilter.Query()
+	for query.Next() {
+		s.candidates = append(s.candidates, query.Entity())
+	}
+	s.rng.Shuffle(len(s.candidates), func(i, j int) {
+		s.candidates[i], s.candidates[j] = s.candidates[j], s.candidates[i]
+	})
+	for _, e := range s.candidates {
+		if s.rng.Float64() < s.waterMapper.Get(e).DetectionProbability {
+			known.Water = e
+			return e
+		}
+	}
+	return ecs.Entity{}
+}
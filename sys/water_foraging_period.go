@@ -1,8 +1,5 @@
 package sys
 
-// WATERFORAGING IMPLEMENTATION HERE: EToX_WaterforcoolingREP <- Reporter for Water need per day
-// this is unfinished and not validated in the Netlogo model version. Can be ignored for now.
-
 import (
 	"math/rand/v2"
 
@@ -12,6 +9,8 @@ import (
 	"github.com/mlange-42/ark/ecs"
 )
 
+// CalcWaterForagingPeriod sets today's water need for cooling from the daily water need data,
+// analogous to the EToX_WaterforcoolingREP reporter of BEEHAVE_ecotox. Only used with water foraging, see [WaterForagingEtox].
 type CalcWaterForagingPeriod struct {
 	time *resource.Tick
 	rng  *rand.Rand
@@ -24,8 +23,7 @@ type CalcWaterForagingPeriod struct {
 
 func (s *CalcWaterForagingPeriod) Initialize(w *ecs.World) {
 	s.time = ecs.GetResource[resource.Tick](w)
-	src := ecs.GetResource[resource.Rand](w)
-	s.rng = rand.New(src)
+	s.rng = rand.New(ecs.GetResource[globals.RandStreams](w).Get(randWaterForagingPeriod))
 
 	s.waterParams = ecs.GetResource[params.WaterForaging](w)
 	s.waterPeriodParams = ecs.GetResource[params.WaterForagingPeriod](w)
@@ -37,12 +35,12 @@ func (s *CalcWaterForagingPeriod) Initialize(w *ecs.World) {
 func (s *CalcWaterForagingPeriod) Update(w *ecs.World) {
 	dayOfYear := int((s.time.Tick) % 365)
 
-	if s.waterParams.WaterForaging { // not bugfixed yet, WaterForaging should not be turned on anyways and thus this never gets accessed
+	if s.waterParams.WaterForaging {
 		if dayOfYear == 0 {
 			if s.waterPeriodParams.RandomYears {
 				s.waterPeriodData.CurrentYear = s.rng.IntN(len(s.waterPeriodData.Years))
 			} else {
-				s.waterPeriodData.CurrentYear = int((s.time.Tick-1)/365) % len(s.waterPeriodData.Years)
+				s.waterPeriodData.CurrentYear = int((s.time.Tick)/365) % len(s.waterPeriodData.Years)
 			}
 		}
 		wateryear := s.waterPeriodData.Years[s.waterPeriodData.CurrentYear]
@@ -0,0 +1,155 @@
+package sys
+
+import (
+	"github.com/fzeitner/Nursebeecs-master-thesis/globals"
+	"github.com/fzeitner/Nursebeecs-master-thesis/params"
+	"github.com/mlange-42/ark/ecs"
+)
+
+// WaxEtox simulates the comb wax as a PPP compartment and long-term source of in-hive exposure.
+//
+// Each day, PPP in the wax degrades and a part of the wax is replaced by new foundation wax.
+// The wax then exchanges PPP with the honey and pollen stores towards the partitioning equilibrium,
+// and larvae take up PPP from the wax of their cells, which is added to their dose of the day.
+//
+// Must run after [EtoxStorages] or [EtoxStoragesNbeecs], which overwrite the larval doses of the day
+// the uptake from the wax is added to, and after [InHiveTreatmentEtox], so that the wax share of a treatment
+// partitions on the day of its release. Must run before [PPPMassBalance], as it moves PPP between the stores
+// and the wax and updates the store totals the balance is checked against.
+// This is part of beecs_ecotox/nursebeecs_ecotox only, and does nothing if the wax compartment is disabled.
+type WaxEtox struct {
+	wax          *params.Wax
+	etox         *params.PPPApplication
+	energyParams *params.EnergyContent
+
+	beecsStores  *globals.Stores
+	stores       *globals.StoragesEtox
+	honeyStores  *globals.HoneyStoresEtox
+	pollenStores *globals.PollenStoresEtox
+	larvae       *globals.Larvae
+	larvaeEtox   *globals.LarvaeEtox
+	pppFate      *globals.PPPFate
+
+	decay float64
+}
+
+func (s *WaxEtox) Initialize(w *ecs.World) {
+	s.wax = ecs.GetResource[params.Wax](w)
+	s.etox = ecs.GetResource[params.PPPApplication](w)
+	s.energyParams = ecs.GetResource[params.EnergyContent](w)
+
+	s.beecsStores = ecs.GetResource[globals.Stores](w)
+	s.stores = ecs.GetResource[globals.StoragesEtox](w)
+	s.honeyStores = ecs.GetResource[globals.HoneyStoresEtox](w)
+	s.pollenStores = ecs.GetResource[globals.PollenStoresEtox](w)
+	s.larvae = ecs.GetResource[globals.Larvae](w)
+	s.larvaeEtox = ecs.GetResource[globals.LarvaeEtox](w)
+	s.pppFate = ecs.GetResource[globals.PPPFate](w)
+
+	s.decay = s.wax.Decay()
+
+	if s.wax.WaxCompartment {
+		initial := s.wax.Mass * s.wax.InitialResidue * 0.001 // [g] * [µg/kg] -> [µg]
+		s.stores.PPPwax = initial
+		s.pppFate.PPPwaxInput += initial
+	}
+}
+
+func (s *WaxEtox) Update(w *ecs.World) {
+	if !s.wax.WaxCompartment || s.wax.Mass <= 0 {
+		return
+	}
+
+	degraded := s.stores.PPPwax * (1 - s.decay)
+	s.stores.PPPwax -= degraded
+	s.pppFate.PPPwaxDegraded += degraded
+
+	// comb replacement
+	replaced := s.wax.CombReplacement / 365
+	removed := s.stores.PPPwax * replaced
+	added := s.wax.Mass * replaced * s.wax.FoundationResidue * 0.001
+	s.stores.PPPwax += added - removed
+	s.pppFate.PPPwaxReplaced += removed
+	s.pppFate.PPPwaxInput += added
+
+	// exchange with honey stores
+	honey := s.honeyStores.Total() / s.energyParams.Honey // [kJ] -> [g]
+	if honey > 0 {
+		flow := s.exchange(honey, s.honeyStores.TotalPPP(), s.wax.PartitionHoney)
+		s.honeyStores.Exchange(flow)
+		s.stores.PPPwax -= flow
+		s.pppFate.PPPwaxToHoney += flow
+	}
+
+	// exchange with pollen stores
+	if s.etox.PollenCompartments {
+		pollen, ppp := s.pollenStores.Total()
+		if pollen > 0 {
+			flow := s.exchange(pollen, ppp, s.wax.PartitionPollen)
+			s.pollenStores.Exchange(flow)
+			s.stores.PPPwax -= flow
+			s.pppFate.PPPwaxToPollen += flow
+		}
+	} else if s.beecsStores.Pollen > 0 {
+		flow := s.exchange(s.beecsStores.Pollen, s.stores.PPPInHivePollenConc*s.beecsStores.Pollen, s.wax.PartitionPollen)
+		s.stores.PPPInHivePollenConc += flow / s.beecsStores.Pollen
+		s.stores.PPPwax -= flow
+		s.pppFate.PPPwaxToPollen += flow
+	}
+
+	s.larvalUptake()
+
+	if s.etox.PollenCompartments {
+		_, s.stores.PPPpollenTotal = s.pollenStores.Total()
+	} else {
+		s.stores.PPPpollenTotal = s.stores.PPPInHivePollenConc * s.beecsStores.Pollen
+	}
+	s.stores.PPPhoneyTotal = s.honeyStores.TotalPPP()
+	s.stores.PPPTotal = s.stores.PPPpollenTotal + s.stores.PPPhoneyTotal
+}
+
+func (s *WaxEtox) Finalize(w *ecs.World) {}
+
+// exchange returns the PPP flow from the wax into a matrix of the given mass [g] and PPP content [µg] for today.
+// The flow is a fraction of the distance to the partitioning equilibrium, and negative if PPP moves into the wax.
+func (s *WaxEtox) exchange(mass float64, ppp float64, partition float64) float64 {
+	equilibrium := (s.stores.PPPwax + ppp) / (partition*s.wax.Mass + mass) // concentration in the matrix at equilibrium [µg/g]
+	return s.wax.ExchangeRate * (equilibrium*mass - ppp)
+}
+
+// larvalUptake adds the PPP larvae take up from the wax of their cells to their dose of the day.
+func (s *WaxEtox) larvalUptake() {
+	if s.wax.PartitionLarvae <= 0 || s.stores.PPPwax <= 0 {
+		return
+	}
+	num := 0
+	for _, n := range s.larvae.Workers {
+		num += n
+	}
+	for _, n := range s.larvae.Drones {
+		num += n
+	}
+	if num == 0 {
+		return
+	}
+
+	conc := s.stores.PPPwax / s.wax.Mass // [µg/g]
+	perLarva := s.wax.ExchangeRate * conc / s.wax.PartitionLarvae * s.wax.LarvalMass * 0.001
+	perLarva = min(perLarva, s.stores.PPPwax/float64(num))
+
+	for i, n := range s.larvae.Workers {
+		if n > 0 {
+			s.larvaeEtox.WorkerCohortDose[i] += perLarva
+			s.pppFate.PPPlarvae += perLarva * float64(n)
+		}
+	}
+	for i, n := range s.larvae.Drones {
+		if n > 0 {
+			s.larvaeEtox.DroneCohortDose[i] += perLarva
+			s.pppFate.PPPdlarvae += perLarva * float64(n)
+		}
+	}
+	uptake := perLarva * float64(num)
+	s.stores.PPPwax -= uptake
+	s.pppFate.PPPwaxToLarvae += uptake
+}
@@ -0,0 +1,39 @@
+package util
+
+import "math"
+
+// LogNormalDose wraps a [DoseResponse] to integrate mortality over a log-normal
+// distribution of individual doses around the given mean dose.
+//
+// The distribution is discretized into equiprobable bins, each represented by its median.
+type LogNormalDose struct {
+	Response DoseResponse
+	factors  []float64
+}
+
+// NewLogNormalDose creates a [LogNormalDose] with the given coefficient of variation
+// of individual doses and the number of bins for the integration.
+func NewLogNormalDose(response DoseResponse, cv float64, bins int) LogNormalDose {
+	sigma := math.Sqrt(math.Log(1 + cv*cv))
+	mu := -sigma * sigma / 2 // keeps the mean of the distribution at the mean dose
+
+	factors := make([]float64, bins)
+	for i := range factors {
+		p := (float64(i) + 0.5) / float64(bins)
+		z := math.Sqrt2 * math.Erfinv(2*p-1)
+		factors[i] = math.Exp(mu + sigma*z)
+	}
+	return LogNormalDose{Response: response, factors: factors}
+}
+
+// Mortality implements [DoseResponse]. The argument is the mean dose of the cohort.
+func (d LogNormalDose) Mortality(dose float64) float64 {
+	if dose <= 0 {
+		return 0
+	}
+	sum := 0.
+	for _, f := range d.factors {
+		sum += d.Response.Mortality(dose * f)
+	}
+	return sum / float64(len(d.factors))
+}
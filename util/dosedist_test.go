@@ -0,0 +1,29 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/doseresponse"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogNormalDose(t *testing.T) {
+	dr := util.NewDoseResponse(doseresponse.LogLogistic, 0.2, 4, 0)
+
+	narrow := util.NewLogNormalDose(dr, 1e-9, 20)
+	assert.InDelta(t, dr.Mortality(0.1), narrow.Mortality(0.1), 1e-9)
+	assert.Equal(t, 0.0, narrow.Mortality(0))
+
+	// a steep dose-response is convex below the LD50, so variable doses kill more than the mean dose
+	wide := util.NewLogNormalDose(dr, 1, 20)
+	assert.Greater(t, wide.Mortality(0.05), dr.Mortality(0.05))
+	assert.Less(t, wide.Mortality(0.8), dr.Mortality(0.8))
+
+	linear := util.NewLogNormalDose(linearResponse{}, 0.5, 2000)
+	assert.InDelta(t, 0.3, linear.Mortality(0.3), 1e-3)
+}
+
+type linearResponse struct{}
+
+func (linearResponse) Mortality(dose float64) float64 { return dose }
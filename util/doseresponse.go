@@ -0,0 +1,105 @@
+package util
+
+import (
+	"math"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/doseresponse"
+)
+
+// DoseResponse is a dose-response relationship.
+// It returns the expected fraction of individuals killed by a dose, in the range [0, 1].
+type DoseResponse interface {
+	Mortality(dose float64) float64
+}
+
+// NewDoseResponse creates a [DoseResponse] of the given model family.
+//
+// LD50 and slope have the same units as the dose. If noel is larger than zero,
+// doses at or below it cause no mortality.
+func NewDoseResponse(model doseresponse.Model, LD50 float64, slope float64, noel float64) DoseResponse {
+	var dr DoseResponse
+	switch model {
+	case doseresponse.Probit:
+		dr = Probit{LD50: LD50, Slope: slope}
+	case doseresponse.Weibull:
+		dr = Weibull{LD50: LD50, Slope: slope}
+	default:
+		dr = LogLogistic{LD50: LD50, Slope: slope}
+	}
+	if noel > 0 {
+		return Threshold{Response: dr, NOEL: noel}
+	}
+	return dr
+}
+
+// LogLogistic dose-response as used in BEEHAVE_ecotox.
+//
+// mortality = 1 - 1/(1 + (dose/LD50)^slope)
+type LogLogistic struct {
+	LD50  float64
+	Slope float64
+}
+
+// Mortality implements [DoseResponse].
+func (d LogLogistic) Mortality(dose float64) float64 {
+	if dose <= 0 {
+		return 0
+	}
+	return 1 - 1/(1+math.Pow(dose/d.LD50, d.Slope))
+}
+
+// Probit dose-response, with the slope on the log10 dose scale.
+//
+// mortality = Φ(slope * log10(dose/LD50))
+type Probit struct {
+	LD50  float64
+	Slope float64
+}
+
+// Mortality implements [DoseResponse].
+func (d Probit) Mortality(dose float64) float64 {
+	if dose <= 0 {
+		return 0
+	}
+	z := d.Slope * math.Log10(dose/d.LD50)
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+// Weibull dose-response, parameterized so that LD50 kills exactly 50%.
+//
+// mortality = 1 - exp(-ln(2) * (dose/LD50)^slope)
+type Weibull struct {
+	LD50  float64
+	Slope float64
+}
+
+// Mortality implements [DoseResponse].
+func (d Weibull) Mortality(dose float64) float64 {
+	if dose <= 0 {
+		return 0
+	}
+	return 1 - math.Exp(-math.Ln2*math.Pow(dose/d.LD50, d.Slope))
+}
+
+// Threshold wraps a [DoseResponse] with a no observed effect level (NOEL).
+// Doses at or below the NOEL cause no mortality.
+type Threshold struct {
+	Response DoseResponse
+	NOEL     float64
+}
+
+// Mortality implements [DoseResponse].
+func (d Threshold) Mortality(dose float64) float64 {
+	if dose <= d.NOEL {
+		return 0
+	}
+	return d.Response.Mortality(dose)
+}
+
+// NoEffect is a [DoseResponse] without any effect, for endpoints that are not parameterized.
+type NoEffect struct{}
+
+// Mortality implements [DoseResponse].
+func (d NoEffect) Mortality(dose float64) float64 {
+	return 0
+}
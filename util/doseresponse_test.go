@@ -0,0 +1,28 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/doseresponse"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoseResponseLD50(t *testing.T) {
+	for _, model := range []doseresponse.Model{doseresponse.LogLogistic, doseresponse.Probit, doseresponse.Weibull} {
+		dr := util.NewDoseResponse(model, 0.2, 2.5, 0)
+
+		assert.Equal(t, 0.0, dr.Mortality(0))
+		assert.InDelta(t, 0.5, dr.Mortality(0.2), 1e-12)
+		assert.Less(t, dr.Mortality(0.1), 0.5)
+		assert.Greater(t, dr.Mortality(0.4), 0.5)
+		assert.InDelta(t, 1.0, dr.Mortality(1e6), 1e-9)
+	}
+}
+
+func TestDoseResponseNOEL(t *testing.T) {
+	dr := util.NewDoseResponse(doseresponse.LogLogistic, 0.2, 2.5, 0.05)
+
+	assert.Equal(t, 0.0, dr.Mortality(0.05))
+	assert.Equal(t, util.LogLogistic{LD50: 0.2, Slope: 2.5}.Mortality(0.1), dr.Mortality(0.1))
+}
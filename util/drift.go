@@ -0,0 +1,84 @@
+package util
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/drift"
+)
+
+// DriftCurve is a spray drift model.
+// It returns the fraction of the in-field deposition that reaches a patch at the given distance [m] to the treated field.
+// A distance of zero or less means that the patch is part of the treated field.
+type DriftCurve interface {
+	Fraction(distance float64) float64
+}
+
+// NewDriftCurve creates a [DriftCurve] of the given type.
+//
+// The table is only used by [drift.Table]. Each row holds a distance [m] and the fraction of the in-field deposition [ ].
+// Rows must be in strictly ascending distance order, otherwise NewDriftCurve panics.
+func NewDriftCurve(curve drift.Curve, table [][2]float64) DriftCurve {
+	switch curve {
+	case drift.BBA:
+		return BBADrift{}
+	case drift.Table:
+		for i := 1; i < len(table); i++ {
+			if table[i][0] <= table[i-1][0] {
+				panic(fmt.Sprintf("drift table distances must be strictly ascending, got %g after %g", table[i][0], table[i-1][0]))
+			}
+		}
+		return DriftTable{Data: table}
+	default:
+		return NoDrift{}
+	}
+}
+
+// NoDrift exposes all patches like the treated field.
+type NoDrift struct{}
+
+// Fraction implements [DriftCurve].
+func (d NoDrift) Fraction(distance float64) float64 {
+	return 1
+}
+
+// BBADrift is the regression of the BBA basic drift values for field crops (Rautmann et al. 2001).
+//
+// Drift [%] = 2.7705 * distance^-0.9787, with distances below 1 m treated as 1 m.
+type BBADrift struct{}
+
+// Fraction implements [DriftCurve].
+func (d BBADrift) Fraction(distance float64) float64 {
+	if distance <= 0 {
+		return 1
+	}
+	return 0.027705 * math.Pow(math.Max(distance, 1), -0.9787)
+}
+
+// DriftTable interpolates drift deposition linearly between the given distances.
+// Beyond the first and the last distance, the respective value is used.
+type DriftTable struct {
+	Data [][2]float64
+}
+
+// Fraction implements [DriftCurve].
+func (d DriftTable) Fraction(distance float64) float64 {
+	if distance <= 0 {
+		return 1
+	}
+	if len(d.Data) == 0 {
+		return 0
+	}
+	if distance <= d.Data[0][0] {
+		return d.Data[0][1]
+	}
+	last := len(d.Data) - 1
+	for i := 0; i < last; i++ {
+		x1, x2 := d.Data[i][0], d.Data[i+1][0]
+		if distance <= x2 {
+			frac := (distance - x1) / (x2 - x1)
+			return d.Data[i][1]*(1-frac) + d.Data[i+1][1]*frac
+		}
+	}
+	return d.Data[last][1]
+}
@@ -0,0 +1,37 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/drift"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDriftBBA(t *testing.T) {
+	d := util.NewDriftCurve(drift.BBA, nil)
+
+	assert.Equal(t, 1.0, d.Fraction(0))
+	assert.InDelta(t, 0.0277, d.Fraction(0.5), 1e-4)
+	assert.InDelta(t, 0.0057, d.Fraction(5), 1e-4)
+	assert.InDelta(t, 0.0029, d.Fraction(10), 1e-4)
+	assert.Less(t, d.Fraction(50), d.Fraction(20))
+
+	assert.Equal(t, 1.0, util.NewDriftCurve(drift.None, nil).Fraction(100))
+}
+
+func TestDriftTable(t *testing.T) {
+	d := util.NewDriftCurve(drift.Table, [][2]float64{{1, 0.1}, {5, 0.02}, {10, 0.01}})
+
+	assert.Equal(t, 1.0, d.Fraction(0))
+	assert.Equal(t, 0.1, d.Fraction(0.5))
+	assert.InDelta(t, 0.06, d.Fraction(3), 1e-12)
+	assert.InDelta(t, 0.015, d.Fraction(7.5), 1e-12)
+	assert.Equal(t, 0.01, d.Fraction(100))
+
+	assert.Equal(t, 0.0, util.NewDriftCurve(drift.Table, nil).Fraction(10))
+
+	assert.Panics(t, func() { util.NewDriftCurve(drift.Table, [][2]float64{{1, 0.1}, {1, 0.05}}) })
+	assert.Panics(t, func() { util.NewDriftCurve(drift.Table, [][2]float64{{5, 0.02}, {1, 0.1}}) })
+	assert.NotPanics(t, func() { util.NewDriftCurve(drift.BBA, [][2]float64{{1, 0.1}, {1, 0.05}}) })
+}
@@ -0,0 +1,89 @@
+package util
+
+import (
+	"math"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/kinetics"
+)
+
+// Kinetics is a residue degradation model.
+// It returns the fraction of the initial residue remaining t days after application.
+type Kinetics interface {
+	Remaining(t float64) float64
+}
+
+// NewKinetics creates a [Kinetics] of the given model family.
+//
+// For SFO kinetics, a DT50 of zero or less results in no degradation.
+func NewKinetics(model kinetics.Model, DT50 float64, alpha float64, beta float64, g float64, k1 float64, k2 float64) Kinetics {
+	switch model {
+	case kinetics.FOMC:
+		return FOMC{Alpha: alpha, Beta: beta}
+	case kinetics.DFOP:
+		return DFOP{G: g, K1: k1, K2: k2}
+	default:
+		if DT50 <= 0 {
+			return NoDegradation{}
+		}
+		return SFO{DT50: DT50}
+	}
+}
+
+// DecayFactor returns the factor by which a residue of the given age [d] declines during the next day.
+func DecayFactor(k Kinetics, age float64) float64 {
+	if sfo, ok := k.(SFO); ok {
+		return math.Exp(-math.Log(2) / sfo.DT50) // independent of age, and exactly as in BEEHAVE_ecotox
+	}
+	before := k.Remaining(age)
+	if before <= 0 {
+		return 0
+	}
+	return k.Remaining(age+1) / before
+}
+
+// NoDegradation keeps residues constant.
+type NoDegradation struct{}
+
+// Remaining implements [Kinetics].
+func (k NoDegradation) Remaining(t float64) float64 {
+	return 1
+}
+
+// SFO is single first-order kinetics.
+//
+// C/C0 = exp(-ln(2)/DT50 * t)
+type SFO struct {
+	DT50 float64
+}
+
+// Remaining implements [Kinetics].
+func (k SFO) Remaining(t float64) float64 {
+	return math.Exp(-math.Log(2) / k.DT50 * t)
+}
+
+// FOMC is first-order multi-compartment kinetics.
+//
+// C/C0 = 1 / (t/beta + 1)^alpha
+type FOMC struct {
+	Alpha float64
+	Beta  float64
+}
+
+// Remaining implements [Kinetics].
+func (k FOMC) Remaining(t float64) float64 {
+	return 1 / math.Pow(t/k.Beta+1, k.Alpha)
+}
+
+// DFOP is double first-order in parallel kinetics.
+//
+// C/C0 = g*exp(-k1*t) + (1-g)*exp(-k2*t)
+type DFOP struct {
+	G  float64
+	K1 float64
+	K2 float64
+}
+
+// Remaining implements [Kinetics].
+func (k DFOP) Remaining(t float64) float64 {
+	return k.G*math.Exp(-k.K1*t) + (1-k.G)*math.Exp(-k.K2*t)
+}
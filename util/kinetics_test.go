@@ -0,0 +1,31 @@
+package util_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/enum/kinetics"
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKineticsSFO(t *testing.T) {
+	k := util.NewKinetics(kinetics.SFO, 10, 0, 0, 0, 0, 0)
+
+	assert.InDelta(t, 1.0, k.Remaining(0), 1e-12)
+	assert.InDelta(t, 0.5, k.Remaining(10), 1e-12)
+	assert.InDelta(t, math.Exp(-math.Log(2)/10), util.DecayFactor(k, 0), 1e-12)
+	assert.InDelta(t, math.Exp(-math.Log(2)/10), util.DecayFactor(k, 25), 1e-12)
+
+	assert.Equal(t, 1.0, util.DecayFactor(util.NewKinetics(kinetics.SFO, 0, 0, 0, 0, 0, 0), 5))
+}
+
+func TestKineticsBiphasic(t *testing.T) {
+	fomc := util.NewKinetics(kinetics.FOMC, 0, 2, 10, 0, 0, 0)
+	assert.InDelta(t, 0.5, fomc.Remaining(10*(math.Sqrt2-1)), 1e-12)
+	assert.Less(t, util.DecayFactor(fomc, 0), util.DecayFactor(fomc, 20))
+
+	dfop := util.NewKinetics(kinetics.DFOP, 0, 0, 0, 0.4, 1, 0.01)
+	assert.InDelta(t, 1.0, dfop.Remaining(0), 1e-12)
+	assert.Less(t, util.DecayFactor(dfop, 0), util.DecayFactor(dfop, 20))
+}
@@ -1,5 +1,7 @@
 package util
 
+import "math"
+
 type numbers interface {
 	int | int8 | int16 | int32 | int64 | float32 | float64
 }
@@ -30,3 +32,34 @@ func Clamp[T numbers](v, low, high T) T {
 	}
 	return v
 }
+
+// CompensatedSum is a running sum with Neumaier compensation of floating-point rounding errors.
+type CompensatedSum struct {
+	sum float64
+	c   float64
+}
+
+// Add a value to the sum.
+func (s *CompensatedSum) Add(v float64) {
+	t := s.sum + v
+	if math.Abs(s.sum) >= math.Abs(v) {
+		s.c += (s.sum - t) + v
+	} else {
+		s.c += (v - t) + s.sum
+	}
+	s.sum = t
+}
+
+// Value returns the compensated sum.
+func (s *CompensatedSum) Value() float64 {
+	return s.sum + s.c
+}
+
+// Sum returns the sum of the given values, with compensation of floating-point rounding errors.
+func Sum(values ...float64) float64 {
+	s := CompensatedSum{}
+	for _, v := range values {
+		s.Add(v)
+	}
+	return s.Value()
+}
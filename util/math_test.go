@@ -0,0 +1,18 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/fzeitner/Nursebeecs-master-thesis/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompensatedSum(t *testing.T) {
+	assert.Equal(t, 2.0, util.Sum(1.0, 1e100, 1.0, -1e100))
+
+	s := util.CompensatedSum{}
+	for range 10 {
+		s.Add(0.1)
+	}
+	assert.Equal(t, 1.0, s.Value())
+}